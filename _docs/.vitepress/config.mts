@@ -16,7 +16,21 @@ export default defineConfig({
       provider: "local",
     },
     nav: [{ text: "About", link: "/" }],
-    sidebar: [{ text: "Quickstart", link: "/quickstart" }],
+    sidebar: [
+      { text: "Quickstart", link: "/quickstart" },
+      { text: "Relations", link: "/relations" },
+      { text: "Joint Calendars", link: "/joints" },
+      { text: "Business Hours", link: "/hours" },
+      { text: "Weekends", link: "/weekends" },
+      { text: "Business Days", link: "/business-days" },
+      { text: "Coverage", link: "/coverage" },
+      { text: "History", link: "/history" },
+      { text: "Trash", link: "/trash" },
+      { text: "Change-sets", link: "/change-sets" },
+      { text: "Batch", link: "/batch" },
+      { text: "CalDAV", link: "/caldav" },
+      { text: "Go Client", link: "/client" },
+    ],
 
     socialLinks: [
       { icon: "github", link: "https://github.com/worldline-go/calendar" },
@@ -9,8 +9,10 @@ import (
 	"github.com/worldline-go/initializer"
 	"github.com/worldline-go/tell"
 
+	"github.com/worldline-go/calendar/internal/adapter/memory"
 	"github.com/worldline-go/calendar/internal/adapter/repository"
 	"github.com/worldline-go/calendar/internal/config"
+	"github.com/worldline-go/calendar/internal/core/port"
 	"github.com/worldline-go/calendar/internal/core/service"
 	"github.com/worldline-go/calendar/internal/server"
 )
@@ -49,22 +51,25 @@ func run(ctx context.Context) error {
 
 	// ///////////////////////////////////////////////////////
 	// database operations
-	if err := repository.MigrateDB(ctx, cfg); err != nil {
-		return fmt.Errorf("failed database migration: %w", err)
-	}
-
-	calendarPostgresAdapter, err := repository.New(ctx, cfg)
+	calendarAdapter, err := newCalendarAdapter(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return err
 	}
 
 	// ///////////////////////////////////////////////////////
 	// service initialize
-	svc, err := service.NewCalendarService(ctx, calendarPostgresAdapter)
+	svc, err := service.NewCalendarService(ctx, calendarAdapter)
 	if err != nil {
 		return fmt.Errorf("failed to create service: %w", err)
 	}
 
+	if err := svc.RegisterMetrics(); err != nil {
+		return fmt.Errorf("failed to register metrics: %w", err)
+	}
+
+	go svc.RunPurge(ctx, cfg.Trash.Retention, cfg.Trash.PurgeInterval)
+	go svc.RunChangeSets(ctx, cfg.ChangeSet.Interval)
+
 	// ///////////////////////////////////////////////////////
 	// server initialize
 	srv, err := server.NewServer(ctx, svc)
@@ -78,3 +83,26 @@ func run(ctx context.Context) error {
 
 	return srv.Start(fmt.Sprintf(":%d", cfg.Port))
 }
+
+// newCalendarAdapter returns the storage of the db_type, the in-memory calendar has no migrations.
+func newCalendarAdapter(ctx context.Context, cfg *config.Config) (port.CalendarPort, error) {
+	if cfg.DBType == memory.DBType {
+		calendarAdapter, err := memory.New(cfg.DBDataSource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load calendar file: %w", err)
+		}
+
+		return calendarAdapter, nil
+	}
+
+	if err := repository.MigrateDB(ctx, cfg); err != nil {
+		return nil, fmt.Errorf("failed database migration: %w", err)
+	}
+
+	calendarAdapter, err := repository.New(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return calendarAdapter, nil
+}
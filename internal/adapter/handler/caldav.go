@@ -0,0 +1,895 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/rest/server"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/pkg/ical"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// CalDAV serves the entities and event groups as calendar collections (RFC 4791).
+//   - {base}/principal/ is the principal of every user.
+//   - {base}/entities/ and {base}/groups/ are the calendar homes.
+//   - {base}/entities/{entity}/ is a calendar, {base}/entities/{entity}/{uid}.ics is an event resource.
+//
+// Event resources are written with PUT and DELETE, occurrences of the repeating functions are read-only.
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+
+	davHomeEntities = "entities"
+	davHomeGroups   = "groups"
+	davPrincipal    = "principal"
+
+	davAllow = "OPTIONS, GET, HEAD, PROPFIND, REPORT, PUT, DELETE"
+)
+
+var errDAVNotFound = errors.New("not found")
+
+type davNodeType int
+
+const (
+	davNodeRoot davNodeType = iota
+	davNodePrincipal
+	davNodeHome
+	davNodeCalendar
+	davNodeObject
+)
+
+// davNode is a resource of the CalDAV tree resolved from the request path.
+type davNode struct {
+	typ    davNodeType
+	home   string
+	name   string
+	object string
+}
+
+// davObject is an event resource of a calendar.
+type davObject struct {
+	name  string
+	event models.Event
+	ics   string
+	etag  string
+	// occurrence is set for the resources of an event listed more than once, they are not written.
+	occurrence bool
+}
+
+// RegisterCalDAV registers the CalDAV handlers, all paths under the group are CalDAV resources.
+func (h *HTTP) RegisterCalDAV(g *echo.Group) {
+	g.Any("", h.CalDAV)
+	g.Any("/*", h.CalDAV)
+}
+
+// CalDAV handles the WebDAV and CalDAV methods of the calendar collections.
+func (h *HTTP) CalDAV(c echo.Context) error {
+	base := strings.TrimSuffix(strings.TrimSuffix(c.Path(), "*"), "/")
+
+	node, err := parseDAVPath(base, c.Request().URL.Path)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	switch c.Request().Method {
+	case http.MethodOptions:
+		c.Response().Header().Set("DAV", "1, 3, calendar-access")
+		c.Response().Header().Set("Allow", davAllow)
+
+		return c.NoContent(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		return h.davGet(c, node)
+	case "PROPFIND":
+		return h.davPropfind(c, base, node)
+	case "REPORT":
+		return h.davReport(c, base, node)
+	case http.MethodPut:
+		return h.davPut(c, node)
+	case http.MethodDelete:
+		return h.davDelete(c, node)
+	default:
+		c.Response().Header().Set("Allow", davAllow)
+
+		return echo.NewHTTPError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func parseDAVPath(base, p string) (davNode, error) {
+	rel := strings.Trim(strings.TrimPrefix(p, base), "/")
+
+	var segments []string
+	if rel != "" {
+		for v := range strings.SplitSeq(rel, "/") {
+			segment, err := url.PathUnescape(v)
+			if err != nil {
+				return davNode{}, fmt.Errorf("invalid path: %w", err)
+			}
+
+			segments = append(segments, segment)
+		}
+	}
+
+	switch {
+	case len(segments) == 0:
+		return davNode{typ: davNodeRoot}, nil
+	case len(segments) == 1 && segments[0] == davPrincipal:
+		return davNode{typ: davNodePrincipal}, nil
+	case segments[0] != davHomeEntities && segments[0] != davHomeGroups:
+		return davNode{}, errDAVNotFound
+	case len(segments) == 1:
+		return davNode{typ: davNodeHome, home: segments[0]}, nil
+	case len(segments) == 2:
+		return davNode{typ: davNodeCalendar, home: segments[0], name: segments[1]}, nil
+	case len(segments) == 3 && strings.HasSuffix(segments[2], ".ics"):
+		return davNode{typ: davNodeObject, home: segments[0], name: segments[1], object: strings.TrimSuffix(segments[2], ".ics")}, nil
+	}
+
+	return davNode{}, errDAVNotFound
+}
+
+// ///////////////////////////////////////////////////////////////
+// Methods
+// ///////////////////////////////////////////////////////////////
+
+func (h *HTTP) davGet(c echo.Context, node davNode) error {
+	switch node.typ {
+	case davNodeCalendar:
+		objects, err := h.davObjects(c, node, nil)
+		if err != nil {
+			return err
+		}
+
+		events := make([]models.Event, 0, len(objects))
+		for _, o := range objects {
+			events = append(events, o.event)
+		}
+
+		str, err := ical.GenerateICS(events, node.name)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		return davCalendarData(c, str, davCTag(objects))
+	case davNodeObject:
+		object, err := h.davObject(c, node)
+		if err != nil {
+			return err
+		}
+
+		return davCalendarData(c, object.ics, object.etag)
+	}
+
+	return echo.NewHTTPError(http.StatusMethodNotAllowed, "not a calendar resource")
+}
+
+func davCalendarData(c echo.Context, data, etag string) error {
+	if notModified(c, etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", []byte(data))
+}
+
+func (h *HTTP) davPropfind(c echo.Context, base string, node davNode) error {
+	names, err := davRequestedProps(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	depth := c.Request().Header.Get("Depth")
+
+	ms := &davMultiStatus{}
+
+	switch node.typ {
+	case davNodeRoot, davNodePrincipal:
+		ms.add(c.Request().URL.Path, davPrincipalProps(base, node.typ == davNodePrincipal), names)
+	case davNodeHome:
+		ms.add(davHref(base, node.home), davHomeProps(node.home), names)
+
+		if depth != "0" {
+			calendars, err := h.davCalendars(c, node.home)
+			if err != nil {
+				return err
+			}
+
+			for _, name := range calendars {
+				objects, err := h.davObjects(c, davNode{typ: davNodeCalendar, home: node.home, name: name}, nil)
+				if err != nil {
+					return err
+				}
+
+				ms.add(davHref(base, node.home, name), davCalendarProps(name, objects), names)
+			}
+		}
+	case davNodeCalendar:
+		objects, err := h.davObjects(c, node, nil)
+		if err != nil {
+			return err
+		}
+
+		ms.add(davHref(base, node.home, node.name), davCalendarProps(node.name, objects), names)
+
+		if depth != "0" {
+			for _, o := range objects {
+				ms.add(davHref(base, node.home, node.name, o.name+".ics"), davObjectProps(o, false), names)
+			}
+		}
+	case davNodeObject:
+		object, err := h.davObject(c, node)
+		if err != nil {
+			return err
+		}
+
+		ms.add(davHref(base, node.home, node.name, object.name+".ics"), davObjectProps(*object, false), names)
+	}
+
+	return ms.write(c)
+}
+
+// davReport answers calendar-query and calendar-multiget reports of a calendar.
+func (h *HTTP) davReport(c echo.Context, base string, node davNode) error {
+	if node.typ != davNodeCalendar {
+		return echo.NewHTTPError(http.StatusForbidden, "reports are supported on calendars")
+	}
+
+	var report davReportRequest
+	if err := xml.NewDecoder(c.Request().Body).Decode(&report); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid report: "+err.Error())
+	}
+
+	names := report.Prop.names()
+	ms := &davMultiStatus{}
+
+	switch report.XMLName {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		timeRange, err := report.Filter.timeRange()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		objects, err := h.davObjects(c, node, timeRange)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range objects {
+			ms.add(davHref(base, node.home, node.name, o.name+".ics"), davObjectProps(o, true), names)
+		}
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		objects, err := h.davObjects(c, node, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, href := range report.Hrefs {
+			href = strings.TrimSpace(href)
+			if u, err := url.Parse(href); err == nil {
+				href = u.Path
+			}
+
+			target, err := parseDAVPath(base, href)
+			idx := slices.IndexFunc(objects, func(o davObject) bool { return o.name == target.object })
+			if err != nil || target.typ != davNodeObject || target.home != node.home || target.name != node.name || idx < 0 {
+				ms.notFound(href)
+
+				continue
+			}
+
+			ms.add(href, davObjectProps(objects[idx], true), names)
+		}
+	default:
+		return echo.NewHTTPError(http.StatusForbidden, "unsupported report: "+report.XMLName.Local)
+	}
+
+	return ms.write(c)
+}
+
+// davPut creates or replaces the event of the resource with the VEVENT of the body.
+// New events are added to the calendar with the event group or an include relation of the entity.
+func (h *HTTP) davPut(c echo.Context, node davNode) error {
+	if node.typ != davNodeObject {
+		return echo.NewHTTPError(http.StatusMethodNotAllowed, "not an event resource")
+	}
+
+	_, stored, err := h.davWritable(c, node)
+	if err != nil {
+		return err
+	}
+
+	// all-day events keep their days in the timezone of the stored event
+	loc := time.UTC
+	if stored != nil && stored.Tz != "" {
+		if v, err := time.LoadLocation(stored.Tz); err == nil {
+			loc = v
+		}
+	}
+
+	events, err := ical.ParseICS(c.Request().Body, loc)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if len(events) != 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "calendar data should have one VEVENT")
+	}
+
+	e := events[0]
+	if e.ID == "" {
+		e.ID = node.object
+	}
+
+	if e.ID != node.object {
+		return echo.NewHTTPError(http.StatusBadRequest, "UID should match the resource name")
+	}
+
+	if e.DateFrom.IsZero() || !e.DateTo.After(e.DateFrom.Time) {
+		return echo.NewHTTPError(http.StatusBadRequest, "VEVENT should have DTSTART before DTEND")
+	}
+
+	ctx := c.Request().Context()
+	updatedBy := server.GetUser(c)
+
+	status := http.StatusNoContent
+	if stored != nil {
+		stored.Name = e.Name
+		stored.Description = e.Description
+		stored.DateFrom = e.DateFrom
+		stored.DateTo = e.DateTo
+		stored.Tz = e.DateFrom.Location().String()
+		stored.AllDay = e.AllDay
+		stored.RRule = e.RRule
+		stored.UpdatedBy = updatedBy
+
+		// the stored version fails the update when the event is changed after the precondition check
+		if err := h.Service.UpdateEvent(ctx, stored.ID, stored); err != nil {
+			return davWriteError(c, err)
+		}
+	} else {
+		e.Tz = e.DateFrom.Location().String()
+		e.Type = models.EventTypeHoliday
+		e.UpdatedBy = updatedBy
+		if node.home == davHomeGroups {
+			e.EventGroup = types.NewNull(node.name)
+		}
+
+		var relations []models.Relation
+		if node.home == davHomeEntities {
+			relations = append(relations, models.Relation{
+				Entity:    node.name,
+				Type:      models.RelationTypeInclude,
+				EventID:   types.NewNull(e.ID),
+				UpdatedBy: updatedBy,
+			})
+		}
+
+		// the event isn't kept without its relation, an event added after the precondition check fails it
+		if err := h.Service.AddEventWithRelations(ctx, &e, relations); err != nil {
+			return davWriteError(c, err)
+		}
+
+		status = http.StatusCreated
+	}
+
+	// the resource is not listed when the event is out of the calendar years
+	if object, err := h.davLookup(c, node); err == nil && object != nil {
+		c.Response().Header().Set("ETag", object.etag)
+	}
+
+	return c.NoContent(status)
+}
+
+// davDelete removes the event of the resource.
+func (h *HTTP) davDelete(c echo.Context, node davNode) error {
+	if node.typ != davNodeObject {
+		return echo.NewHTTPError(http.StatusMethodNotAllowed, "not an event resource")
+	}
+
+	current, stored, err := h.davWritable(c, node)
+	if err != nil {
+		return err
+	}
+
+	if current == nil || stored == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	}
+
+	if err := h.Service.RemoveEventVersion(c.Request().Context(), stored.ID, stored.Version, server.GetUser(c)); err != nil {
+		return eventError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// davWriteError maps the error of a write, an event changed after the precondition check fails a conditional request
+// and conflicts with an unconditional one.
+func davWriteError(c echo.Context, err error) error {
+	if !errors.Is(err, domain.ErrEventVersion) && !errors.Is(err, domain.ErrEventNotFound) && !errors.Is(err, domain.ErrEventExists) {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	if c.Request().Header.Get("If-Match") != "" || c.Request().Header.Get("If-None-Match") != "" {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+
+	return echo.NewHTTPError(http.StatusConflict, err.Error())
+}
+
+// davWritable returns the current resource and the stored event of a write, nil when they don't exist.
+// It checks the If-Match and If-None-Match preconditions with the ETag of the resource.
+func (h *HTTP) davWritable(c echo.Context, node davNode) (*davObject, *models.Event, error) {
+	current, err := h.davLookup(c, node)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if current != nil && current.occurrence {
+		return nil, nil, echo.NewHTTPError(http.StatusForbidden, "occurrence of a repeating event is read-only")
+	}
+
+	stored, err := h.Service.GetEvent(c.Request().Context(), node.object)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	if stored != nil && current == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusConflict, "event exists out of the calendar")
+	}
+
+	// FUNC and multiple RRULE repeats can't be written back from a VEVENT
+	if stored != nil && (strings.Contains(stored.RRule, "FUNC:") || len(strings.Fields(stored.RRule)) > 1) {
+		return nil, nil, echo.NewHTTPError(http.StatusForbidden, "event with a repeating function is read-only")
+	}
+
+	if v := c.Request().Header.Get("If-Match"); v != "" && (current == nil || !matchStrongETag(v, current.etag)) {
+		return nil, nil, echo.NewHTTPError(http.StatusPreconditionFailed, "event is changed")
+	}
+
+	if v := c.Request().Header.Get("If-None-Match"); v != "" && current != nil && matchETag(v, current.etag) {
+		return nil, nil, echo.NewHTTPError(http.StatusPreconditionFailed, "event already exists")
+	}
+
+	return current, stored, nil
+}
+
+// ///////////////////////////////////////////////////////////////
+// Resources
+// ///////////////////////////////////////////////////////////////
+
+// davCalendars returns the entity or event group names of the home from the relations.
+func (h *HTTP) davCalendars(c echo.Context, home string) ([]string, error) {
+	relations, err := h.Service.GetRelations(c.Request().Context(), &query.Query{})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	var names []string
+	for _, r := range relations {
+		name := r.Entity
+		if home == davHomeGroups {
+			name = r.EventGroup.V
+		}
+
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// davObjects returns the event resources of the calendar, filtered with the time range when it is set.
+// Resources are the events of the ICS feed with its default years, an event listed more than once is named with its date.
+func (h *HTTP) davObjects(c echo.Context, node davNode, timeRange *[2]time.Time) ([]davObject, error) {
+	field := "entity"
+	if node.home == davHomeGroups {
+		field = "event_group"
+	}
+
+	cmp := query.ExpressionCmp{Operator: query.OperatorEq, Field: field, Value: node.name}
+	q := &query.Query{
+		Values: map[string][]query.ExpressionCmp{field: {cmp}},
+		Where:  []query.Expression{cmp},
+	}
+
+	events, err := h.Service.GetEventsICS(c.Request().Context(), q)
+	if err != nil {
+		return nil, searchError(err)
+	}
+
+	counts := make(map[string]int, len(events))
+	for _, e := range events {
+		counts[e.ID]++
+	}
+
+	objects := make([]davObject, 0, len(events))
+	for _, e := range events {
+		if timeRange != nil && !davOverlaps(e, timeRange[0], timeRange[1]) {
+			continue
+		}
+
+		name := e.ID
+		occurrence := counts[e.ID] > 1
+		if occurrence {
+			name = e.ID + "-" + e.DateFrom.Format("20060102")
+			e.ID = name
+		}
+
+		if slices.ContainsFunc(objects, func(o davObject) bool { return o.name == name }) {
+			continue
+		}
+
+		str, err := ical.GenerateICS([]models.Event{e}, node.name)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		objects = append(objects, davObject{name: name, event: e, ics: str, etag: davETag(str), occurrence: occurrence})
+	}
+
+	return objects, nil
+}
+
+func (h *HTTP) davObject(c echo.Context, node davNode) (*davObject, error) {
+	object, err := h.davLookup(c, node)
+	if err != nil {
+		return nil, err
+	}
+
+	if object == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "event not found")
+	}
+
+	return object, nil
+}
+
+// davLookup returns the event resource of the calendar, nil when there is no such resource.
+func (h *HTTP) davLookup(c echo.Context, node davNode) (*davObject, error) {
+	objects, err := h.davObjects(c, node, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range objects {
+		if objects[i].name == node.object {
+			return &objects[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// davOverlaps reports whether an occurrence of the event overlaps with [from, to).
+func davOverlaps(e models.Event, from, to time.Time) bool {
+	if e.RRule == "" {
+		return e.DateFrom.Before(to) && e.DateTo.After(from)
+	}
+
+	repeat, err := ical.ParseRepeat("RRULE:" + e.RRule)
+	if err != nil {
+		return false
+	}
+
+	return len(ical.Occurrences(e, repeat, from, to)) > 0
+}
+
+func davETag(data string) string {
+	sum := sha256.Sum256([]byte(data))
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// davCTag is the tag of the calendar changing with any of its resources.
+func davCTag(objects []davObject) string {
+	var b strings.Builder
+	for _, o := range objects {
+		b.WriteString(o.name)
+		b.WriteString(o.etag)
+	}
+
+	return davETag(b.String())
+}
+
+func davHref(base string, segments ...string) string {
+	var b strings.Builder
+	b.WriteString(base)
+	for _, v := range segments {
+		b.WriteString("/")
+		b.WriteString(url.PathEscape(v))
+	}
+
+	if len(segments) < 3 {
+		b.WriteString("/")
+	}
+
+	return b.String()
+}
+
+// ///////////////////////////////////////////////////////////////
+// Properties
+// ///////////////////////////////////////////////////////////////
+
+// davProp is a property with its inner XML.
+type davProp struct {
+	name  xml.Name
+	value string
+}
+
+func davPrincipalProps(base string, principal bool) []davProp {
+	resourceType := "<d:collection/>"
+	if principal {
+		resourceType += "<d:principal/>"
+	}
+
+	return []davProp{
+		{xml.Name{Space: nsDAV, Local: "resourcetype"}, resourceType},
+		{xml.Name{Space: nsDAV, Local: "displayname"}, "Calendar"},
+		{xml.Name{Space: nsDAV, Local: "current-user-principal"}, "<d:href>" + base + "/" + davPrincipal + "/</d:href>"},
+		{xml.Name{Space: nsDAV, Local: "principal-URL"}, "<d:href>" + base + "/" + davPrincipal + "/</d:href>"},
+		{xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}, "<d:href>" + davHref(base, davHomeEntities) + "</d:href><d:href>" + davHref(base, davHomeGroups) + "</d:href>"},
+	}
+}
+
+func davHomeProps(home string) []davProp {
+	return []davProp{
+		{xml.Name{Space: nsDAV, Local: "resourcetype"}, "<d:collection/>"},
+		{xml.Name{Space: nsDAV, Local: "displayname"}, davEscape(home)},
+	}
+}
+
+func davCalendarProps(name string, objects []davObject) []davProp {
+	ctag := davCTag(objects)
+
+	return []davProp{
+		{xml.Name{Space: nsDAV, Local: "resourcetype"}, "<d:collection/><c:calendar/>"},
+		{xml.Name{Space: nsDAV, Local: "displayname"}, davEscape(name)},
+		{xml.Name{Space: nsDAV, Local: "getetag"}, davEscape(ctag)},
+		{xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}, "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>"},
+		{xml.Name{Space: nsDAV, Local: "supported-report-set"}, "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"},
+		{xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}, `<c:comp name="VEVENT"/>`},
+		{xml.Name{Space: nsCalDAV, Local: "calendar-description"}, "Holidays of " + davEscape(name)},
+		{xml.Name{Space: nsCS, Local: "getctag"}, davEscape(ctag)},
+	}
+}
+
+func davObjectProps(o davObject, data bool) []davProp {
+	props := []davProp{
+		{xml.Name{Space: nsDAV, Local: "resourcetype"}, ""},
+		{xml.Name{Space: nsDAV, Local: "getetag"}, davEscape(o.etag)},
+		{xml.Name{Space: nsDAV, Local: "getcontenttype"}, "text/calendar; charset=utf-8; component=vevent"},
+		{xml.Name{Space: nsDAV, Local: "getcontentlength"}, strconv.Itoa(len(o.ics))},
+	}
+
+	if data {
+		props = append(props, davProp{xml.Name{Space: nsCalDAV, Local: "calendar-data"}, davEscape(o.ics)})
+	}
+
+	return props
+}
+
+func davEscape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
+
+// ///////////////////////////////////////////////////////////////
+// Requests
+// ///////////////////////////////////////////////////////////////
+
+type davPropNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+// names returns the requested property names, nil is all properties.
+func (p *davPropNames) names() []xml.Name {
+	if p == nil {
+		return nil
+	}
+
+	names := make([]xml.Name, 0, len(p.Names))
+	for _, v := range p.Names {
+		names = append(names, v.XMLName)
+	}
+
+	return names
+}
+
+type davPropfindRequest struct {
+	XMLName xml.Name      `xml:"DAV: propfind"`
+	Prop    *davPropNames `xml:"DAV: prop"`
+}
+
+// davRequestedProps returns the property names of the propfind body, nil for allprop or an empty body.
+func davRequestedProps(body io.Reader) ([]xml.Name, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var req davPropfindRequest
+	if err := xml.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("invalid propfind: %w", err)
+	}
+
+	return req.Prop.names(), nil
+}
+
+type davCompFilter struct {
+	Name       string          `xml:"name,attr"`
+	TimeRange  *davTimeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilter []davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type davTimeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+type davFilter struct {
+	CompFilter davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type davReportRequest struct {
+	XMLName xml.Name
+	Prop    *davPropNames `xml:"DAV: prop"`
+	Hrefs   []string      `xml:"DAV: href"`
+	Filter  *davFilter    `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// timeRange returns the time range of the VEVENT filter, nil when there is no range.
+func (f *davFilter) timeRange() (*[2]time.Time, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	var tr *davTimeRange
+	for _, v := range f.CompFilter.CompFilter {
+		if v.Name == "VEVENT" && v.TimeRange != nil {
+			tr = v.TimeRange
+		}
+	}
+
+	if tr == nil {
+		return nil, nil
+	}
+
+	// open ends are limited with the years of the ICS feed
+	result := [2]time.Time{
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for i, v := range []string{tr.Start, tr.End} {
+		if v == "" {
+			continue
+		}
+
+		t, err := time.Parse("20060102T150405Z", v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time-range: %s", v)
+		}
+
+		result[i] = t
+	}
+
+	if !result[1].After(result[0]) {
+		return nil, fmt.Errorf("time-range end should be after start")
+	}
+
+	return &result, nil
+}
+
+// ///////////////////////////////////////////////////////////////
+// Multistatus
+// ///////////////////////////////////////////////////////////////
+
+type davMultiStatus struct {
+	b bytes.Buffer
+}
+
+// add writes the response of the href with the requested properties, nil names are all properties.
+func (m *davMultiStatus) add(href string, props []davProp, names []xml.Name) {
+	found := props
+	var missing []xml.Name
+
+	if names != nil {
+		found = nil
+		for _, name := range names {
+			idx := slices.IndexFunc(props, func(p davProp) bool { return p.name == name })
+			if idx < 0 {
+				missing = append(missing, name)
+
+				continue
+			}
+
+			found = append(found, props[idx])
+		}
+	}
+
+	m.b.WriteString("<d:response><d:href>" + davEscape(href) + "</d:href>")
+
+	if len(found) > 0 {
+		m.b.WriteString("<d:propstat><d:prop>")
+		for _, p := range found {
+			writeDAVProp(&m.b, p.name, p.value)
+		}
+		m.b.WriteString("</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>")
+	}
+
+	if len(missing) > 0 {
+		m.b.WriteString("<d:propstat><d:prop>")
+		for _, name := range missing {
+			writeDAVProp(&m.b, name, "")
+		}
+		m.b.WriteString("</d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>")
+	}
+
+	m.b.WriteString("</d:response>")
+}
+
+func (m *davMultiStatus) notFound(href string) {
+	m.b.WriteString("<d:response><d:href>" + davEscape(href) + "</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>")
+}
+
+func (m *davMultiStatus) write(c echo.Context) error {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<d:multistatus xmlns:d="` + nsDAV + `" xmlns:c="` + nsCalDAV + `" xmlns:cs="` + nsCS + `">`)
+	b.Write(m.b.Bytes())
+	b.WriteString("</d:multistatus>")
+
+	return c.Blob(http.StatusMultiStatus, "application/xml; charset=utf-8", b.Bytes())
+}
+
+func writeDAVProp(b *bytes.Buffer, name xml.Name, value string) {
+	tag := name.Local
+	attr := ""
+
+	switch name.Space {
+	case nsDAV:
+		tag = "d:" + tag
+	case nsCalDAV:
+		tag = "c:" + tag
+	case nsCS:
+		tag = "cs:" + tag
+	default:
+		tag = "x:" + tag
+		attr = ` xmlns:x="` + davEscape(name.Space) + `"`
+	}
+
+	if value == "" {
+		b.WriteString("<" + tag + attr + "/>")
+
+		return
+	}
+
+	b.WriteString("<" + tag + attr + ">" + value + "</" + tag + ">")
+}
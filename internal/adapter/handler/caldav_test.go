@@ -0,0 +1,414 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/adapter/handler"
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/internal/core/port"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+const davBase = "/calendar/dav"
+
+// davService keeps the calendar of the CalDAV tests, an entity has the events of its include relations.
+type davService struct {
+	port.CalendarService
+
+	events    []models.Event
+	relations []models.Relation
+}
+
+func (s *davService) GetRelations(_ context.Context, _ *query.Query) ([]models.Relation, error) {
+	return s.relations, nil
+}
+
+func (s *davService) GetEventsICS(_ context.Context, q *query.Query) ([]models.Event, error) {
+	entity, group := q.GetValue("entity"), q.GetValue("event_group")
+
+	var events []models.Event
+	for _, e := range s.events {
+		related := slices.ContainsFunc(s.relations, func(r models.Relation) bool {
+			return r.Entity == entity && r.EventID.V == e.ID
+		})
+
+		if related || (group != "" && e.EventGroup.V == group) {
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+func (s *davService) GetEvent(_ context.Context, id string) (*models.Event, error) {
+	for _, e := range s.events {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *davService) AddEventWithRelations(_ context.Context, event *models.Event, relations []models.Relation) error {
+	if slices.ContainsFunc(s.events, func(e models.Event) bool { return e.ID == event.ID }) {
+		return domain.ErrEventExists
+	}
+
+	s.events = append(s.events, *event)
+	s.relations = append(s.relations, relations...)
+
+	return nil
+}
+
+func (s *davService) UpdateEvent(_ context.Context, id string, event *models.Event) error {
+	for i := range s.events {
+		if s.events[i].ID == id {
+			s.events[i] = *event
+		}
+	}
+
+	return nil
+}
+
+func (s *davService) RemoveEventVersion(_ context.Context, id string, version int64, _ string) error {
+	i := slices.IndexFunc(s.events, func(e models.Event) bool { return e.ID == id })
+	if i < 0 {
+		return domain.ErrEventNotFound
+	}
+
+	if s.events[i].Version != version {
+		return domain.ErrEventVersion
+	}
+
+	s.events = slices.Delete(s.events, i, i+1)
+
+	return nil
+}
+
+func davDay(month time.Month, day int) types.Time {
+	return types.Time{Time: time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// newDAVServer returns the CalDAV handlers with the holidays of NLD.
+func newDAVServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	svc := &davService{
+		events: []models.Event{
+			{ID: "new-year", Name: "New Year", DateFrom: davDay(time.January, 1), DateTo: davDay(time.January, 2), Tz: "UTC", AllDay: true},
+			{ID: "kings-day", Name: "Kings Day", DateFrom: davDay(time.April, 27), DateTo: davDay(time.April, 28), Tz: "UTC", AllDay: true},
+		},
+		relations: []models.Relation{
+			{Entity: "NLD", Type: models.RelationTypeInclude, EventID: types.NewNull("new-year")},
+			{Entity: "NLD", Type: models.RelationTypeInclude, EventID: types.NewNull("kings-day")},
+		},
+	}
+
+	h, err := handler.NewHTTP(svc)
+	if err != nil {
+		t.Fatalf("NewHTTP() error = %v", err)
+	}
+
+	e := echo.New()
+	h.RegisterCalDAV(e.Group(davBase))
+
+	return e
+}
+
+func davRequest(e *echo.Echo, method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+
+	req := httptest.NewRequest(method, davBase+path, r)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// davHrefs returns the hrefs of the multistatus responses.
+func davHrefs(body string) []string {
+	var hrefs []string
+	for _, v := range strings.Split(body, "<d:response><d:href>")[1:] {
+		hrefs = append(hrefs, v[:strings.Index(v, "</d:href>")])
+	}
+
+	return hrefs
+}
+
+func TestCalDAVPropfind(t *testing.T) {
+	e := newDAVServer(t)
+
+	tests := []struct {
+		name  string
+		path  string
+		depth string
+		want  []string
+	}{
+		{name: "calendar depth 0", path: "/entities/NLD/", depth: "0", want: []string{davBase + "/entities/NLD/"}},
+		{
+			name:  "calendar depth 1",
+			path:  "/entities/NLD/",
+			depth: "1",
+			want:  []string{davBase + "/entities/NLD/", davBase + "/entities/NLD/new-year.ics", davBase + "/entities/NLD/kings-day.ics"},
+		},
+		{name: "home depth 0", path: "/entities/", depth: "0", want: []string{davBase + "/entities/"}},
+		{name: "home depth 1", path: "/entities/", depth: "1", want: []string{davBase + "/entities/", davBase + "/entities/NLD/"}},
+		{name: "object", path: "/entities/NLD/kings-day.ics", depth: "0", want: []string{davBase + "/entities/NLD/kings-day.ics"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := davRequest(e, "PROPFIND", tt.path, "", map[string]string{"Depth": tt.depth})
+			if rec.Code != http.StatusMultiStatus {
+				t.Fatalf("PROPFIND status = %d, body = %s", rec.Code, rec.Body.String())
+			}
+
+			if got := davHrefs(rec.Body.String()); !slices.Equal(got, tt.want) {
+				t.Errorf("PROPFIND hrefs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	body := `<?xml version="1.0"?><d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/"><d:prop><cs:getctag/><d:owner/></d:prop></d:propfind>`
+	rec := davRequest(e, "PROPFIND", "/entities/NLD/", body, map[string]string{"Depth": "0"})
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("PROPFIND prop status = %d", rec.Code)
+	}
+
+	got := rec.Body.String()
+	if !strings.Contains(got, "<cs:getctag>") || !strings.Contains(got, "<d:owner/></d:prop><d:status>HTTP/1.1 404 Not Found") {
+		t.Errorf("PROPFIND prop = %s", got)
+	}
+
+	if rec := davRequest(e, "PROPFIND", "/entities/NLD/missing.ics", "", map[string]string{"Depth": "0"}); rec.Code != http.StatusNotFound {
+		t.Errorf("PROPFIND missing status = %d", rec.Code)
+	}
+}
+
+func TestCalDAVReport(t *testing.T) {
+	e := newDAVServer(t)
+
+	calendarQuery := func(start, end string) string {
+		return `<?xml version="1.0"?><c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">` +
+			`<d:prop><d:getetag/><c:calendar-data/></d:prop>` +
+			`<c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT">` +
+			`<c:time-range start="` + start + `" end="` + end + `"/>` +
+			`</c:comp-filter></c:comp-filter></c:filter></c:calendar-query>`
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		want       []string
+		wantStatus int
+	}{
+		{name: "april", start: "20250401T000000Z", end: "20250501T000000Z", want: []string{davBase + "/entities/NLD/kings-day.ics"}},
+		{
+			name:  "open end",
+			start: "20250101T000000Z",
+			want:  []string{davBase + "/entities/NLD/new-year.ics", davBase + "/entities/NLD/kings-day.ics"},
+		},
+		{name: "no events", start: "20250601T000000Z", end: "20250701T000000Z"},
+		{name: "end before start", start: "20250501T000000Z", end: "20250401T000000Z", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := davRequest(e, "REPORT", "/entities/NLD/", calendarQuery(tt.start, tt.end), map[string]string{"Depth": "1"})
+
+			wantStatus := tt.wantStatus
+			if wantStatus == 0 {
+				wantStatus = http.StatusMultiStatus
+			}
+
+			if rec.Code != wantStatus {
+				t.Fatalf("REPORT status = %d, want %d, body = %s", rec.Code, wantStatus, rec.Body.String())
+			}
+
+			if wantStatus != http.StatusMultiStatus {
+				return
+			}
+
+			if got := davHrefs(rec.Body.String()); !slices.Equal(got, tt.want) {
+				t.Errorf("REPORT hrefs = %v, want %v", got, tt.want)
+			}
+
+			if len(tt.want) > 0 && !strings.Contains(rec.Body.String(), "BEGIN:VEVENT") {
+				t.Errorf("REPORT without calendar-data: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+// davEvent returns a calendar with an all-day VEVENT, dates are in YYYYMMDD.
+func davEvent(uid, name, from, to string) string {
+	return "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:" + uid + "\r\n" +
+		"SUMMARY:" + name + "\r\n" +
+		"DTSTART;VALUE=DATE:" + from + "\r\n" +
+		"DTEND;VALUE=DATE:" + to + "\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+}
+
+// davETagOf returns the ETag of the event resource.
+func davETagOf(t *testing.T, e *echo.Echo, path string) string {
+	t.Helper()
+
+	rec := davRequest(e, http.MethodGet, path, "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s status = %d", path, rec.Code)
+	}
+
+	return rec.Header().Get("ETag")
+}
+
+func TestCalDAVPut(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		header     func(etag string) map[string]string
+		wantStatus int
+		wantName   string
+	}{
+		{
+			name:       "create",
+			path:       "/entities/NLD/liberation-day.ics",
+			body:       davEvent("liberation-day", "Liberation Day", "20250505", "20250506"),
+			header:     func(string) map[string]string { return map[string]string{"If-None-Match": "*"} },
+			wantStatus: http.StatusCreated,
+			wantName:   "SUMMARY:Liberation Day",
+		},
+		{
+			name:       "create existing",
+			path:       "/entities/NLD/kings-day.ics",
+			body:       davEvent("kings-day", "Kings Day", "20250427", "20250428"),
+			header:     func(string) map[string]string { return map[string]string{"If-None-Match": "*"} },
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "update",
+			path:       "/entities/NLD/kings-day.ics",
+			body:       davEvent("kings-day", "Koningsdag", "20250426", "20250427"),
+			header:     func(etag string) map[string]string { return map[string]string{"If-Match": etag} },
+			wantStatus: http.StatusNoContent,
+			wantName:   "SUMMARY:Koningsdag",
+		},
+		{
+			name:       "update weak etag",
+			path:       "/entities/NLD/kings-day.ics",
+			body:       davEvent("kings-day", "Koningsdag", "20250426", "20250427"),
+			header:     func(etag string) map[string]string { return map[string]string{"If-Match": "W/" + etag} },
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "update changed",
+			path:       "/entities/NLD/kings-day.ics",
+			body:       davEvent("kings-day", "Koningsdag", "20250426", "20250427"),
+			header:     func(string) map[string]string { return map[string]string{"If-Match": `"changed"`} },
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "uid mismatch",
+			path:       "/entities/NLD/liberation-day.ics",
+			body:       davEvent("bevrijdingsdag", "Liberation Day", "20250505", "20250506"),
+			header:     func(string) map[string]string { return nil },
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newDAVServer(t)
+			etag := davETagOf(t, e, "/entities/NLD/kings-day.ics")
+
+			rec := davRequest(e, http.MethodPut, tt.path, tt.body, tt.header(etag))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("PUT status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if tt.wantName == "" {
+				return
+			}
+
+			if rec.Header().Get("ETag") == "" {
+				t.Errorf("PUT without ETag")
+			}
+
+			got := davRequest(e, http.MethodGet, tt.path, "", nil)
+			if got.Code != http.StatusOK || !strings.Contains(got.Body.String(), tt.wantName) {
+				t.Errorf("GET after PUT status = %d, body = %s", got.Code, got.Body.String())
+			}
+		})
+	}
+}
+
+func TestCalDAVDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		header     func(etag string) map[string]string
+		wantStatus int
+	}{
+		{
+			name:       "delete",
+			path:       "/entities/NLD/kings-day.ics",
+			header:     func(etag string) map[string]string { return map[string]string{"If-Match": etag} },
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "weak etag",
+			path:       "/entities/NLD/kings-day.ics",
+			header:     func(etag string) map[string]string { return map[string]string{"If-Match": "W/" + etag} },
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "missing",
+			path:       "/entities/NLD/missing.ics",
+			header:     func(string) map[string]string { return nil },
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newDAVServer(t)
+			etag := davETagOf(t, e, "/entities/NLD/kings-day.ics")
+
+			rec := davRequest(e, http.MethodDelete, tt.path, "", tt.header(etag))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("DELETE status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusNoContent {
+				return
+			}
+
+			if rec := davRequest(e, http.MethodGet, tt.path, "", nil); rec.Code != http.StatusNotFound {
+				t.Errorf("GET after DELETE status = %d", rec.Code)
+			}
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +19,7 @@ import (
 	"github.com/worldline-go/rest/server"
 	"github.com/worldline-go/types"
 
+	"github.com/worldline-go/calendar/internal/core/domain"
 	"github.com/worldline-go/calendar/internal/core/port"
 	"github.com/worldline-go/calendar/pkg/ical"
 	"github.com/worldline-go/calendar/pkg/models"
@@ -30,18 +37,53 @@ type QueryValidator struct {
 
 	GetRelations    *query.Validator
 	DeleteRelations *query.Validator
+	// PatchRelations matches the relations like the delete.
+	PatchRelations *query.Validator
+
+	GetJoints *query.Validator
+	GetHours  *query.Validator
+
+	GetWeekends    *query.Validator
+	DeleteWeekends *query.Validator
+
+	GetHistory      *query.Validator
+	GetEventHistory *query.Validator
+
+	GetTrashEvents    *query.Validator
+	GetTrashRelations *query.Validator
+	// RestoreRelations matches the relations like the delete.
+	RestoreRelations *query.Validator
+
+	GetChangeSets *query.Validator
+	// GetChangeSetHolidays is the holidays query of a preview, as_of is not allowed.
+	GetChangeSetHolidays *query.Validator
 
 	GetEventsDate *query.Validator
+	GetWorkDay    *query.Validator
+	GetOpenAt     *query.Validator
+	GetSettlement *query.Validator
+	GetAdjust     *query.Validator
+	GetSchedule   *query.Validator
+	GetBridges    *query.Validator
+	GetDiff       *query.Validator
+	GetCoverage   *query.Validator
+	GetDefinition *query.Validator
 	GetICS        *query.Validator
 }
 
 var DefaultLimit uint64 = 25
 
+// ScheduleRangeLimit is the maximum number of days between from and to of a schedule.
+var ScheduleRangeLimit = 10 * 366
+
+// BatchLimit is the maximum number of operations in a batch.
+var BatchLimit = 1000
+
 func NewHTTP(svc port.CalendarService) (*HTTP, error) {
 	validatorGetEvents, err := query.NewValidator(
 		query.WithField(query.WithNotAllowed()),
-		query.WithSort(query.WithIn("id", "entity", "event_group", "name", "description", "disabled", "date_from", "date_to", "updated_at", "updated_by")),
-		query.WithValues(query.WithIn("id", "entity", "event_group", "name", "description", "disabled", "updated_by")),
+		query.WithSort(query.WithIn("id", "entity", "event_group", "name", "description", "disabled", "type", "date_from", "date_to", "updated_at", "updated_by")),
+		query.WithValues(query.WithIn("id", "entity", "event_group", "name", "description", "disabled", "type", "updated_by")),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create validator for GetEvents: %w", err)
@@ -61,11 +103,14 @@ func NewHTTP(svc port.CalendarService) (*HTTP, error) {
 
 	validatorGetRelations, err := query.NewValidator(
 		query.WithField(query.WithNotAllowed()),
-		query.WithSort(query.WithIn("event_id", "event_group", "entity")),
-		query.WithValues(query.WithIn("entity", "event_id", "event_group")),
+		query.WithSort(query.WithIn("event_id", "event_group", "entity", "type", "parent", "occurrence")),
+		query.WithValues(query.WithIn("entity", "event_id", "event_group", "type", "parent", "occurrence")),
 		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 		query.WithValue("event_id", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("type", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("parent", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("occurrence", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create validator for GetRelations: %w", err)
@@ -73,10 +118,13 @@ func NewHTTP(svc port.CalendarService) (*HTTP, error) {
 
 	validatorDeleteRelations, err := query.NewValidator(
 		query.WithField(query.WithNotAllowed()),
-		query.WithValues(query.WithIn("entity", "event_id", "event_group")),
+		query.WithValues(query.WithIn("entity", "event_id", "event_group", "type", "parent", "occurrence")),
 		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn), query.WithNotEmpty()),
 		query.WithValue("event_id", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("type", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("parent", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("occurrence", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 		query.WithLimit(query.WithNotAllowed()),
 		query.WithOffset(query.WithNotAllowed()),
 		query.WithSort(query.WithNotAllowed()),
@@ -85,23 +133,285 @@ func NewHTTP(svc port.CalendarService) (*HTTP, error) {
 		return nil, fmt.Errorf("failed to create validator for DeleteRelations: %w", err)
 	}
 
+	validatorGetJoints, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithSort(query.WithIn("name", "mode", "updated_at", "updated_by")),
+		query.WithValues(query.WithIn("name", "mode", "updated_by")),
+		query.WithValue("name", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetJoints: %w", err)
+	}
+
+	validatorGetHours, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithSort(query.WithIn("entity", "tz", "updated_at", "updated_by")),
+		query.WithValues(query.WithIn("entity", "tz", "updated_by")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetHours: %w", err)
+	}
+
+	validatorGetWeekends, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithSort(query.WithIn("entity", "effective_from", "updated_at", "updated_by")),
+		query.WithValues(query.WithIn("entity", "effective_from", "updated_by")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetWeekends: %w", err)
+	}
+
+	validatorDeleteWeekends, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "effective_from")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn), query.WithNotEmpty()),
+		query.WithValue("effective_from", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithLimit(query.WithNotAllowed()),
+		query.WithOffset(query.WithNotAllowed()),
+		query.WithSort(query.WithNotAllowed()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for DeleteWeekends: %w", err)
+	}
+
+	validatorGetHistory, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithSort(query.WithIn("id", "event_id", "action", "changed_at", "changed_by")),
+		query.WithValues(query.WithIn("event_id", "action", "changed_at", "changed_by")),
+		query.WithValue("event_id", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("action", query.WithOperator(query.OperatorEq, query.OperatorIn), query.WithIn(models.HistoryActionInsert, models.HistoryActionUpdate, models.HistoryActionDelete, models.HistoryActionRestore)),
+		query.WithValue("changed_at", query.WithOperator(query.OperatorGt, query.OperatorGte, query.OperatorLt, query.OperatorLte)),
+		query.WithValue("changed_by", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetHistory: %w", err)
+	}
+
+	validatorGetEventHistory, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithSort(query.WithIn("id", "action", "changed_at", "changed_by")),
+		query.WithValues(query.WithIn("action", "changed_at", "changed_by")),
+		query.WithValue("action", query.WithOperator(query.OperatorEq, query.OperatorIn), query.WithIn(models.HistoryActionInsert, models.HistoryActionUpdate, models.HistoryActionDelete, models.HistoryActionRestore)),
+		query.WithValue("changed_at", query.WithOperator(query.OperatorGt, query.OperatorGte, query.OperatorLt, query.OperatorLte)),
+		query.WithValue("changed_by", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetEventHistory: %w", err)
+	}
+
+	validatorGetTrashEvents, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithSort(query.WithIn("id", "name", "event_group", "type", "deleted_at", "deleted_by")),
+		query.WithValues(query.WithIn("id", "name", "event_group", "type", "deleted_at", "deleted_by")),
+		query.WithValue("deleted_at", query.WithOperator(query.OperatorGt, query.OperatorGte, query.OperatorLt, query.OperatorLte)),
+		query.WithValue("deleted_by", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetTrashEvents: %w", err)
+	}
+
+	validatorGetTrashRelations, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithSort(query.WithIn("entity", "event_id", "event_group", "type", "parent", "occurrence", "deleted_at", "deleted_by")),
+		query.WithValues(query.WithIn("entity", "event_id", "event_group", "type", "parent", "occurrence", "deleted_at", "deleted_by")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_id", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("type", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("parent", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("occurrence", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("deleted_at", query.WithOperator(query.OperatorGt, query.OperatorGte, query.OperatorLt, query.OperatorLte)),
+		query.WithValue("deleted_by", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetTrashRelations: %w", err)
+	}
+
+	validatorGetChangeSets, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithSort(query.WithIn("id", "name", "status", "effective_at", "created_at", "created_by", "updated_at", "approved_at", "approved_by", "applied_at")),
+		query.WithValues(query.WithIn("id", "name", "status", "effective_at", "created_by", "approved_by")),
+		query.WithValue("id", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("status", query.WithOperator(query.OperatorEq, query.OperatorIn), query.WithIn(models.ChangeSetStatusDraft, models.ChangeSetStatusApproved, models.ChangeSetStatusApplied, models.ChangeSetStatusFailed)),
+		query.WithValue("effective_at", query.WithOperator(query.OperatorGt, query.OperatorGte, query.OperatorLt, query.OperatorLte)),
+		query.WithValue("created_by", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("approved_by", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetChangeSets: %w", err)
+	}
+
+	validatorGetChangeSetHolidays, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "event_group", "date", "joint", "mode")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("date", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq), query.WithIn(models.JointModeUnion, models.JointModeIntersection)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetChangeSetHolidays: %w", err)
+	}
+
 	validatorGetEventsDate, err := query.NewValidator(
 		query.WithField(query.WithNotAllowed()),
-		query.WithValues(query.WithIn("entity", "event_group", "date")),
+		query.WithValues(query.WithIn("entity", "event_group", "date", "joint", "mode", "as_of")),
 		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 		query.WithValue("date", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq), query.WithIn(models.JointModeUnion, models.JointModeIntersection)),
+		query.WithValue("as_of", query.WithOperator(query.OperatorEq)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create validator for GetEventsDate: %w", err)
 	}
 
+	validatorGetWorkDay, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "event_group", "date", "joint", "mode", "days", "as_of")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("date", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq), query.WithIn(models.JointModeUnion, models.JointModeIntersection)),
+		query.WithValue("days", query.WithOperator(query.OperatorEq), query.WithMin("1")),
+		query.WithValue("as_of", query.WithOperator(query.OperatorEq)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetWorkDay: %w", err)
+	}
+
+	validatorGetOpenAt, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "event_group", "time", "joint", "mode", "as_of")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("time", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq), query.WithIn(models.JointModeUnion, models.JointModeIntersection)),
+		query.WithValue("as_of", query.WithOperator(query.OperatorEq)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetOpenAt: %w", err)
+	}
+
+	validatorGetSettlement, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "event_group", "joint", "mode", "trade", "days", "cutoff", "tz", "as_of")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq), query.WithIn(models.JointModeUnion, models.JointModeIntersection)),
+		query.WithValue("trade", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("days", query.WithOperator(query.OperatorEq), query.WithMin("0")),
+		query.WithValue("cutoff", query.WithOperator(query.OperatorEq)),
+		query.WithValue("tz", query.WithOperator(query.OperatorEq)),
+		query.WithValue("as_of", query.WithOperator(query.OperatorEq)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetSettlement: %w", err)
+	}
+
+	validatorGetAdjust, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "event_group", "joint", "mode", "date", "convention", "as_of")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq), query.WithIn(models.JointModeUnion, models.JointModeIntersection)),
+		query.WithValue("date", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("convention", query.WithOperator(query.OperatorEq), query.WithIn(
+			models.ConventionFollowing,
+			models.ConventionModifiedFollowing,
+			models.ConventionPreceding,
+			models.ConventionModifiedPreceding,
+			models.ConventionEndOfMonth,
+		)),
+		query.WithValue("as_of", query.WithOperator(query.OperatorEq)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetAdjust: %w", err)
+	}
+
+	validatorGetSchedule, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "event_group", "joint", "mode", "rule", "from", "to", "as_of")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq), query.WithIn(models.JointModeUnion, models.JointModeIntersection)),
+		query.WithValue("from", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("to", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("as_of", query.WithOperator(query.OperatorEq)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetSchedule: %w", err)
+	}
+
+	validatorGetBridges, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "event_group", "joint", "mode", "year", "days", "as_of")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("mode", query.WithOperator(query.OperatorEq), query.WithIn(models.JointModeUnion, models.JointModeIntersection)),
+		query.WithValue("year", query.WithOperator(query.OperatorEq)),
+		query.WithValue("days", query.WithOperator(query.OperatorEq), query.WithMin("1")),
+		query.WithValue("as_of", query.WithOperator(query.OperatorEq)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetBridges: %w", err)
+	}
+
+	validatorGetDiff, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "year", "base_entity", "base_year")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq), query.WithNotEmpty()),
+		query.WithValue("year", query.WithOperator(query.OperatorEq)),
+		query.WithValue("base_entity", query.WithOperator(query.OperatorEq)),
+		query.WithValue("base_year", query.WithOperator(query.OperatorEq)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetDiff: %w", err)
+	}
+
+	validatorGetCoverage, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("years", "ratio", "all")),
+		query.WithValue("years", query.WithOperator(query.OperatorEq), query.WithMin("1"), query.WithMax("10")),
+		query.WithValue("ratio", query.WithOperator(query.OperatorEq)),
+		query.WithValue("all", query.WithOperator(query.OperatorEq), query.WithIn("true", "false")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetCoverage: %w", err)
+	}
+
+	validatorGetDefinition, err := query.NewValidator(
+		query.WithField(query.WithNotAllowed()),
+		query.WithValues(query.WithIn("entity", "event_group")),
+		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithLimit(query.WithNotAllowed()),
+		query.WithOffset(query.WithNotAllowed()),
+		query.WithSort(query.WithNotAllowed()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for GetDefinition: %w", err)
+	}
+
 	validatorGetICS, err := query.NewValidator(
 		query.WithField(query.WithNotAllowed()),
-		query.WithValues(query.WithIn("entity", "event_group", "year")),
+		query.WithValues(query.WithIn("entity", "event_group", "year", "joint", "as_of")),
 		query.WithValue("entity", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 		query.WithValue("event_group", query.WithOperator(query.OperatorEq, query.OperatorIn)),
 		query.WithValue("year", query.WithOperator(query.OperatorEq, query.OperatorIn)),
+		query.WithValue("joint", query.WithOperator(query.OperatorEq)),
+		query.WithValue("as_of", query.WithOperator(query.OperatorEq)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create validator for GetICS: %w", err)
@@ -110,12 +420,33 @@ func NewHTTP(svc port.CalendarService) (*HTTP, error) {
 	return &HTTP{
 		Service: svc,
 		Validator: QueryValidator{
-			GetEvents:       validatorGetEvents,
-			DeleteEvents:    validatorDeleteEvents,
-			DeleteRelations: validatorDeleteRelations,
-			GetRelations:    validatorGetRelations,
-			GetEventsDate:   validatorGetEventsDate,
-			GetICS:          validatorGetICS,
+			GetEvents:            validatorGetEvents,
+			DeleteEvents:         validatorDeleteEvents,
+			DeleteRelations:      validatorDeleteRelations,
+			PatchRelations:       validatorDeleteRelations,
+			GetRelations:         validatorGetRelations,
+			GetJoints:            validatorGetJoints,
+			GetHours:             validatorGetHours,
+			GetWeekends:          validatorGetWeekends,
+			DeleteWeekends:       validatorDeleteWeekends,
+			GetHistory:           validatorGetHistory,
+			GetEventHistory:      validatorGetEventHistory,
+			GetTrashEvents:       validatorGetTrashEvents,
+			GetTrashRelations:    validatorGetTrashRelations,
+			RestoreRelations:     validatorDeleteRelations,
+			GetChangeSets:        validatorGetChangeSets,
+			GetChangeSetHolidays: validatorGetChangeSetHolidays,
+			GetEventsDate:        validatorGetEventsDate,
+			GetWorkDay:           validatorGetWorkDay,
+			GetOpenAt:            validatorGetOpenAt,
+			GetSettlement:        validatorGetSettlement,
+			GetAdjust:            validatorGetAdjust,
+			GetSchedule:          validatorGetSchedule,
+			GetBridges:           validatorGetBridges,
+			GetDiff:              validatorGetDiff,
+			GetCoverage:          validatorGetCoverage,
+			GetDefinition:        validatorGetDefinition,
+			GetICS:               validatorGetICS,
 		},
 	}, nil
 }
@@ -128,12 +459,58 @@ func (h *HTTP) RegisterRoutes(g *echo.Group) {
 	g.GET("/events/:id", h.GetEvent)
 	g.DELETE("/events/:id", h.DeleteEvent)
 	g.PUT("/events/:id", h.PutEvent)
+	g.PATCH("/events/:id", h.PatchEvent)
+	g.GET("/events/:id/history", h.GetEventHistory)
+	g.POST("/events/:id/restore", h.RestoreEvent)
+
+	g.GET("/history", h.GetHistory)
+
+	g.GET("/trash/events", h.GetTrashEvents)
+	g.GET("/trash/relations", h.GetTrashRelations)
+
+	g.GET("/changesets", h.GetChangeSets)
+	g.POST("/changesets", h.AddChangeSet)
+
+	g.GET("/changesets/:id", h.GetChangeSet)
+	g.PUT("/changesets/:id", h.PutChangeSet)
+	g.DELETE("/changesets/:id", h.DeleteChangeSet)
+	g.POST("/changesets/:id/approve", h.ApproveChangeSet)
+	g.GET("/changesets/:id/holidays", h.ChangeSetHolidays)
+
+	g.POST("/batch", h.Batch)
 
 	g.GET("/relations", h.GetRelations)
 	g.POST("/relations", h.AddRelations)
 	g.DELETE("/relations", h.DeleteRelations)
+	g.PATCH("/relations", h.PatchRelations)
+	g.POST("/relations/restore", h.RestoreRelations)
+
+	g.GET("/joints", h.GetJoints)
+	g.POST("/joints", h.AddJoints)
+
+	g.GET("/joints/:name", h.GetJoint)
+	g.DELETE("/joints/:name", h.DeleteJoint)
+	g.PUT("/joints/:name", h.PutJoint)
+
+	g.GET("/hours", h.GetHours)
+	g.GET("/hours/:entity", h.GetEntityHours)
+	g.PUT("/hours/:entity", h.PutEntityHours)
+	g.DELETE("/hours/:entity", h.DeleteEntityHours)
+
+	g.GET("/weekends", h.GetWeekends)
+	g.POST("/weekends", h.AddWeekends)
+	g.DELETE("/weekends", h.DeleteWeekends)
 
 	g.GET("/holidays", h.Holidays)
+	g.GET("/workday", h.WorkDay)
+	g.GET("/is-open-at", h.IsOpenAt)
+	g.GET("/settlement", h.Settlement)
+	g.GET("/adjust", h.Adjust)
+	g.GET("/schedule", h.Schedule)
+	g.GET("/bridges", h.Bridges)
+	g.GET("/diff", h.Diff)
+	g.GET("/coverage", h.Coverage)
+	g.GET("/definitions", h.GetDefinitions)
 	g.POST("/ics", h.AddICS)
 	g.GET("/ics", h.GetICS)
 }
@@ -146,6 +523,7 @@ func (h *HTTP) RegisterRoutes(g *echo.Group) {
 // @Param event_group query string false "event_group"
 // @Param entity query string false "entity for relation"
 // @Param disabled query bool false "disabled"
+// @Param type query string false "type, holiday or half-day"
 // @Param limit query int false "limit" default(25)
 // @Param offset query int false "offset"
 // @Success 200 {object} rest.Response[[]models.Event]
@@ -202,6 +580,10 @@ func (h *HTTP) AddEvents(c echo.Context) error {
 
 	updatedBy := server.GetUser(c)
 	for i := range v {
+		if err := checkEvent(&v[i]); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
 		v[i].UpdatedBy = updatedBy
 	}
 
@@ -223,10 +605,11 @@ func (h *HTTP) AddEvents(c echo.Context) error {
 }
 
 // @Summary GetEvent
-// @Description GetEvent
+// @Description GetEvent, ETag is the version of the event to update or delete it with If-Match.
 // @Param id path string true "Event ID"
 // @Success 200 {object} rest.Response[models.Event]
 // @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
 // @Failure 500 {object} rest.ResponseMessage
 // @Router /events/{id} [get]
 // @Tags Events
@@ -244,16 +627,23 @@ func (h *HTTP) GetEvent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "event not found")
 	}
 
+	c.Response().Header().Set("ETag", eventETag(event.Version))
+
 	return c.JSON(http.StatusOK, rest.Response[models.Event]{
 		Payload: *event,
 	})
 }
 
 // @Summary DeleteEvent
-// @Description DeleteEvent
+// @Description DeleteEvent, If-Match is the ETag of the event or "*" for any version.
+// @Description The event moves to the trash with its relations, it is restored with POST /events/{id}/restore.
 // @Param id path string true "Event ID"
+// @Param If-Match header string true "ETag of the event"
 // @Success 200 {object} rest.ResponseMessage
 // @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 412 {object} rest.ResponseMessage
+// @Failure 428 {object} rest.ResponseMessage
 // @Failure 500 {object} rest.ResponseMessage
 // @Router /events/{id} [delete]
 // @Tags Events
@@ -263,18 +653,27 @@ func (h *HTTP) DeleteEvent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "missing event ID")
 	}
 
-	if err := h.Service.RemoveEvent(c.Request().Context(), id); err != nil {
+	version, err := h.ifMatchVersion(c, id)
+	if err != nil {
 		return err
 	}
 
+	if err := h.Service.RemoveEventVersion(c.Request().Context(), id, version, server.GetUser(c)); err != nil {
+		return eventError(err)
+	}
+
 	return nil
 }
 
 // @Summary DeleteEvents
-// @Description DeleteEvents for multiple events
-// @Param id query string true "Event ID"
+// @Description DeleteEvents for multiple events, every id has the version of the event like id=<id>:<version>.
+// @Description The events move to the trash with their relations in one transaction, nothing is removed when one of them has another version.
+// @Param id query string true "Event ID with its version like 01JQ...:3, comma separated for multiple events"
 // @Success 200 {object} rest.ResponseMessage
 // @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 412 {object} rest.ResponseMessage
+// @Failure 428 {object} rest.ResponseMessage
 // @Failure 500 {object} rest.ResponseMessage
 // @Router /events [delete]
 // @Tags Events
@@ -292,19 +691,49 @@ func (h *HTTP) DeleteEvents(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "missing event ID")
 	}
 
-	if err := h.Service.RemoveEvent(c.Request().Context(), q.GetValues("id")...); err != nil {
-		return err
+	events := make([]models.EventVersion, 0, len(ids))
+	for _, id := range ids {
+		event, err := parseEventVersion(id)
+		if err != nil {
+			return err
+		}
+
+		events = append(events, event)
+	}
+
+	if err := h.Service.RemoveEvents(c.Request().Context(), events, server.GetUser(c)); err != nil {
+		return eventError(err)
 	}
 
 	return nil
 }
 
+// parseEventVersion parses the event ID with its version like <id>:<version>.
+func parseEventVersion(v string) (models.EventVersion, error) {
+	i := strings.LastIndex(v, ":")
+	if i < 0 {
+		return models.EventVersion{}, echo.NewHTTPError(http.StatusPreconditionRequired, "version of the event is required like id="+v+":<version>")
+	}
+
+	version, err := strconv.ParseInt(v[i+1:], 10, 64)
+	if err != nil || v[:i] == "" {
+		return models.EventVersion{}, echo.NewHTTPError(http.StatusBadRequest, "invalid event version: "+v)
+	}
+
+	return models.EventVersion{ID: v[:i], Version: version}, nil
+}
+
 // @Summary PutEvent
-// @Description PutEvent
+// @Description PutEvent, If-Match is the ETag of the event or "*" for any version.
+// @Description ETag of the response is the new version of the event.
 // @Param id path string true "Event ID"
+// @Param If-Match header string true "ETag of the event"
 // @Param body body models.Event true "Event"
 // @Success 200 {object} rest.ResponseMessage
 // @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 412 {object} rest.ResponseMessage
+// @Failure 428 {object} rest.ResponseMessage
 // @Failure 500 {object} rest.ResponseMessage
 // @Router /events/{id} [put]
 // @Tags Events
@@ -319,13 +748,25 @@ func (h *HTTP) PutEvent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
+	if err := checkEvent(&v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	version, err := h.ifMatchVersion(c, id)
+	if err != nil {
+		return err
+	}
+
 	updatedBy := server.GetUser(c)
 	v.UpdatedBy = updatedBy
+	v.Version = version
 
 	if err := h.Service.UpdateEvent(c.Request().Context(), id, &v); err != nil {
-		return err
+		return eventError(err)
 	}
 
+	c.Response().Header().Set("ETag", eventETag(v.Version))
+
 	return c.JSON(http.StatusOK, rest.ResponseMessage{
 		Message: &rest.Message{
 			Text: "Event updated",
@@ -333,154 +774,2243 @@ func (h *HTTP) PutEvent(c echo.Context) error {
 	})
 }
 
-// /////////////////////////////////////////////////////////////
-// Relations
-// /////////////////////////////////////////////////////////////
-
-// @Summary AddRelations
-// @Description AddRelations
-// @Param body body []models.Relation true "Relation"
-// @Success 200 {object} rest.ResponseMessage
+// @Summary PatchEvent
+// @Description PatchEvent with a JSON merge patch (RFC 7396), only the fields in the patch are changed and null clears a field.
+// @Description If-Match is the ETag of the event or "*" for any version, ETag of the response is the new version of the event.
+// @Accept application/merge-patch+json
+// @Param id path string true "Event ID"
+// @Param If-Match header string true "ETag of the event"
+// @Param body body models.Event true "Fields of the event to change"
+// @Success 200 {object} rest.Response[models.Event]
 // @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 412 {object} rest.ResponseMessage
+// @Failure 415 {object} rest.ResponseMessage
+// @Failure 428 {object} rest.ResponseMessage
 // @Failure 500 {object} rest.ResponseMessage
-// @Router /relations [post]
-// @Tags Relations
-func (h *HTTP) AddRelations(c echo.Context) error {
-	v := []models.Relation{}
-	if err := rest.BindJSONList(c.Request().Body, &v); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err)
+// @Router /events/{id} [patch]
+// @Tags Events
+func (h *HTTP) PatchEvent(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing event ID")
 	}
 
-	updatedBy := server.GetUser(c)
-	for i := range v {
-		if v[i].Entity == "" {
-			return echo.NewHTTPError(http.StatusBadRequest, "missing entity")
-		}
-
-		v[i].UpdatedBy = updatedBy
+	patch, err := readMergePatch(c)
+	if err != nil {
+		return err
 	}
 
-	if err := h.Service.AddRelations(c.Request().Context(), v); err != nil {
+	version, err := h.ifMatchVersion(c, id)
+	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, rest.ResponseMessage{
-		Message: &rest.Message{
-			Text: "Relations added",
-		},
-	})
-}
+	stored, err := h.Service.GetEvent(c.Request().Context(), id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if stored == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	}
 
-// @Summary DeleteRelations
-// @Description DeleteRelations for multiple relations
-// @Param entity query string true "entity"
-// @Param event_id query string false "event_id"
-// @Param event_group query string false "event_group"
-// @Success 200 {object} rest.ResponseMessage
-// @Failure 400 {object} rest.ResponseMessage
-// @Failure 500 {object} rest.ResponseMessage
-// @Router /relations [delete]
-// @Tags Relations
-func (h *HTTP) DeleteRelations(c echo.Context) error {
-	q, err := query.ParseWithValidator(
-		c.QueryString(),
-		h.Validator.DeleteRelations,
-	)
+	v, err := mergePatch(*stored, patch)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if err := h.Service.RemoveRelation(c.Request().Context(), q); err != nil {
-		return err
+	if v.ID != id {
+		return echo.NewHTTPError(http.StatusBadRequest, "event ID cannot be changed")
 	}
 
-	return c.JSON(http.StatusOK, rest.ResponseMessage{
+	if err := checkEvent(&v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	v.UpdatedBy = server.GetUser(c)
+	v.Version = version
+
+	if err := h.Service.UpdateEvent(c.Request().Context(), id, &v); err != nil {
+		return eventError(err)
+	}
+
+	c.Response().Header().Set("ETag", eventETag(v.Version))
+
+	return c.JSON(http.StatusOK, rest.Response[models.Event]{
 		Message: &rest.Message{
-			Text: "Relation removed",
+			Text: "Event updated",
 		},
+		Payload: v,
 	})
 }
 
-// @Summary GetRelations
-// @Description GetRelations
-// @Param entity query string false "entity"
-// @Param event_id query string false "event_id"
-// @Param event_group query string false "event_group"
-// @Param sort query string false "sort"
-// @Param limit query int false "limit" default(25)
-// @Param offset query int false "offset"
-// @Success 200 {object} rest.Response[[]models.Relation]
-// @Failure 400 {object} rest.ResponseMessage
-// @Failure 500 {object} rest.ResponseMessage
-// @Router /relations [get]
-// @Tags Relations
-func (h *HTTP) GetRelations(c echo.Context) error {
-	q, err := query.ParseWithValidator(c.QueryString(), h.Validator.GetRelations, query.WithDefaultLimit(DefaultLimit))
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+// ifMatchVersion returns the event version of the If-Match header, "*" is the stored version of the event.
+// Other ETags never match a version, the write fails with the not found or version mismatch error.
+func (h *HTTP) ifMatchVersion(c echo.Context, id string) (int64, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if ifMatch == "" {
+		return 0, echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match with the ETag of the event is required")
 	}
 
-	relations, err := h.Service.GetRelations(c.Request().Context(), q)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	if ifMatch == "*" {
+		event, err := h.Service.GetEvent(c.Request().Context(), id)
+		if err != nil {
+			return 0, echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+		if event == nil {
+			return 0, echo.NewHTTPError(http.StatusNotFound, "event not found")
+		}
+
+		return event.Version, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(ifMatch, `"`), 10, 64)
+	if err != nil || !strings.HasPrefix(ifMatch, `"`) {
+		return 0, nil
+	}
+
+	return version, nil
+}
+
+// eventETag is the ETag of the event version.
+func eventETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+func eventError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrEventNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrEventVersion):
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+
+	return echo.NewHTTPError(http.StatusInternalServerError, err)
+}
+
+// checkEvent sets the default type of the event.
+func checkEvent(e *models.Event) error {
+	switch e.Type {
+	case "":
+		e.Type = models.EventTypeHoliday
+	case models.EventTypeHoliday, models.EventTypeHalfDay:
+	default:
+		return fmt.Errorf("invalid event type: %s", e.Type)
+	}
+
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////
+// History
+// /////////////////////////////////////////////////////////////
+
+// @Summary GetEventHistory
+// @Description GetEventHistory returns the recorded changes of the event, the latest change is first.
+// @Description History of a removed event is kept.
+// @Param id path string true "Event ID"
+// @Param action query string false "insert, update, delete or restore"
+// @Param changed_by query string false "changed_by"
+// @Param changed_at[gte] query string false "changed at or after the time"
+// @Param changed_at[lt] query string false "changed before the time"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.EventHistory]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /events/{id}/history [get]
+// @Tags History
+func (h *HTTP) GetEventHistory(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing event ID")
+	}
+
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetEventHistory,
+		query.WithDefaultLimit(DefaultLimit),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	history, err := h.Service.GetEventHistory(c.Request().Context(), id, q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(history) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no history found")
+	}
+
+	count, err := h.Service.GetEventHistoryCount(c.Request().Context(), id, q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "history count failed").SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.EventHistory]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: history,
+	})
+}
+
+// @Summary GetHistory
+// @Description GetHistory returns the recorded changes of all events for auditing, the latest change is first.
+// @Param event_id query string false "event_id"
+// @Param action query string false "insert, update, delete or restore"
+// @Param changed_by query string false "changed_by"
+// @Param changed_at[gte] query string false "changed at or after the time"
+// @Param changed_at[lt] query string false "changed before the time"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.EventHistory]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /history [get]
+// @Tags History
+func (h *HTTP) GetHistory(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetHistory,
+		query.WithDefaultLimit(DefaultLimit),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	history, err := h.Service.GetHistory(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(history) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no history found")
+	}
+
+	count, err := h.Service.GetHistoryCount(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "history count failed").SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.EventHistory]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: history,
+	})
+}
+
+// /////////////////////////////////////////////////////////////
+// Trash
+// /////////////////////////////////////////////////////////////
+
+// @Summary GetTrashEvents
+// @Description GetTrashEvents returns the removed events, the latest removed is first.
+// @Description Events stay in the trash until the purge after the retention.
+// @Param id query string false "id"
+// @Param name query string false "name"
+// @Param event_group query string false "event_group"
+// @Param deleted_by query string false "deleted_by"
+// @Param deleted_at[gte] query string false "removed at or after the time"
+// @Param deleted_at[lt] query string false "removed before the time"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.Event]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /trash/events [get]
+// @Tags Trash
+func (h *HTTP) GetTrashEvents(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetTrashEvents,
+		query.WithDefaultLimit(DefaultLimit),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	events, err := h.Service.GetTrashEvents(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(events) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no events in the trash")
+	}
+
+	count, err := h.Service.GetTrashEventsCount(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "trash count failed").SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Event]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: events,
+	})
+}
+
+// @Summary GetTrashRelations
+// @Description GetTrashRelations returns the removed relations, the latest removed is first.
+// @Description Relations removed with their event have the deleted_at of the event.
+// @Param entity query string false "entity"
+// @Param event_id query string false "event_id"
+// @Param event_group query string false "event_group"
+// @Param type query string false "type"
+// @Param deleted_by query string false "deleted_by"
+// @Param deleted_at[gte] query string false "removed at or after the time"
+// @Param deleted_at[lt] query string false "removed before the time"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.Relation]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /trash/relations [get]
+// @Tags Trash
+func (h *HTTP) GetTrashRelations(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetTrashRelations,
+		query.WithDefaultLimit(DefaultLimit),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	relations, err := h.Service.GetTrashRelations(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(relations) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no relations in the trash")
+	}
+
+	count, err := h.Service.GetTrashRelationsCount(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "trash count failed").SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Relation]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: relations,
+	})
+}
+
+// @Summary RestoreEvent
+// @Description RestoreEvent takes the event back from the trash with the relations removed together with it.
+// @Param id path string true "Event ID"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /events/{id}/restore [post]
+// @Tags Trash
+func (h *HTTP) RestoreEvent(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing event ID")
+	}
+
+	if err := h.Service.RestoreEvent(c.Request().Context(), id, server.GetUser(c)); err != nil {
+		return eventError(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Event restored",
+		},
+	})
+}
+
+// @Summary RestoreRelations
+// @Description RestoreRelations takes the matching relations back from the trash.
+// @Description Relations of an event in the trash are skipped, they are restored with the event.
+// @Param entity query string true "entity"
+// @Param event_id query string false "event_id"
+// @Param event_group query string false "event_group"
+// @Param type query string false "type"
+// @Param parent query string false "parent"
+// @Param occurrence query string false "occurrence"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /relations/restore [post]
+// @Tags Trash
+func (h *HTTP) RestoreRelations(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.RestoreRelations,
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.Service.RestoreRelations(c.Request().Context(), q, server.GetUser(c)); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Relations restored",
+		},
+	})
+}
+
+// /////////////////////////////////////////////////////////////
+// Change-sets
+// /////////////////////////////////////////////////////////////
+
+// @Summary GetChangeSets
+// @Description GetChangeSets returns the change-sets, the latest created is first.
+// @Param id query string false "id"
+// @Param name query string false "name"
+// @Param status query string false "draft, approved, applied or failed"
+// @Param created_by query string false "created_by"
+// @Param approved_by query string false "approved_by"
+// @Param effective_at[gte] query string false "effective at or after the time"
+// @Param effective_at[lt] query string false "effective before the time"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.ChangeSet]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /changesets [get]
+// @Tags ChangeSets
+func (h *HTTP) GetChangeSets(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetChangeSets,
+		query.WithDefaultLimit(DefaultLimit),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	changeSets, err := h.Service.GetChangeSets(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(changeSets) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no change-sets found")
+	}
+
+	count, err := h.Service.GetChangeSetsCount(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "change-sets count failed").SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.ChangeSet]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: changeSets,
+	})
+}
+
+// @Summary AddChangeSet
+// @Description AddChangeSet stores the event and relation changes as a draft, the calendar is not changed until another user approves it.
+// @Description New events without an ID get one in the draft, relations of the same change-set could use it.
+// @Description Without effective_at the changes are applied on the approval, otherwise at that time.
+// @Param body body models.ChangeSet true "ChangeSet, only name, description, changes and effective_at are used"
+// @Success 200 {object} rest.Response[models.ChangeSet]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /changesets [post]
+// @Tags ChangeSets
+func (h *HTTP) AddChangeSet(c echo.Context) error {
+	v, err := bindChangeSet(c)
+	if err != nil {
+		return err
+	}
+
+	v.CreatedBy = server.GetUser(c)
+
+	if err := h.Service.AddChangeSet(c.Request().Context(), &v); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.ChangeSet]{
+		Message: &rest.Message{
+			Text: "Change-set added",
+		},
+		Payload: v,
+	})
+}
+
+// @Summary GetChangeSet
+// @Description GetChangeSet
+// @Param id path string true "ChangeSet ID"
+// @Success 200 {object} rest.Response[models.ChangeSet]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /changesets/{id} [get]
+// @Tags ChangeSets
+func (h *HTTP) GetChangeSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing change-set ID")
+	}
+
+	changeSet, err := h.Service.GetChangeSet(c.Request().Context(), id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if changeSet == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "change-set not found")
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.ChangeSet]{
+		Payload: *changeSet,
+	})
+}
+
+// @Summary PutChangeSet
+// @Description PutChangeSet replaces the name, description, changes and effective_at of a draft.
+// @Param id path string true "ChangeSet ID"
+// @Param body body models.ChangeSet true "ChangeSet"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 409 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /changesets/{id} [put]
+// @Tags ChangeSets
+func (h *HTTP) PutChangeSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing change-set ID")
+	}
+
+	v, err := bindChangeSet(c)
+	if err != nil {
+		return err
+	}
+
+	v.ID = id
+	v.UpdatedBy = server.GetUser(c)
+
+	if err := h.Service.UpdateChangeSet(c.Request().Context(), id, &v); err != nil {
+		return changeSetError(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Change-set updated",
+		},
+	})
+}
+
+// @Summary DeleteChangeSet
+// @Description DeleteChangeSet removes a change-set not applied yet, like a draft or an approved one waiting for its effective_at.
+// @Param id path string true "ChangeSet ID"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 409 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /changesets/{id} [delete]
+// @Tags ChangeSets
+func (h *HTTP) DeleteChangeSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing change-set ID")
+	}
+
+	if err := h.Service.RemoveChangeSet(c.Request().Context(), id); err != nil {
+		return changeSetError(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Change-set removed",
+		},
+	})
+}
+
+// @Summary ApproveChangeSet
+// @Description ApproveChangeSet approves a draft of another user.
+// @Description Without effective_at or with a passed one the changes are applied at once in one transaction, otherwise at the effective_at.
+// @Description Changes conflicting with the calendar, like an updated event changed after the draft, fail the approval and keep the draft.
+// @Param id path string true "ChangeSet ID"
+// @Success 200 {object} rest.Response[models.ChangeSet]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 403 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 409 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /changesets/{id}/approve [post]
+// @Tags ChangeSets
+func (h *HTTP) ApproveChangeSet(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing change-set ID")
+	}
+
+	changeSet, err := h.Service.ApproveChangeSet(c.Request().Context(), id, server.GetUser(c))
+	if err != nil {
+		return changeSetError(err)
+	}
+
+	text := "Change-set applied"
+	if changeSet.Status == models.ChangeSetStatusApproved {
+		text = "Change-set approved"
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.ChangeSet]{
+		Message: &rest.Message{
+			Text: text,
+		},
+		Payload: *changeSet,
+	})
+}
+
+// @Summary ChangeSetHolidays
+// @Description ChangeSetHolidays previews the holidays with the changes of the change-set, like /holidays after the approval.
+// @Description The calendar is not changed, an applied change-set shows the current holidays.
+// @Param id path string true "ChangeSet ID"
+// @Param entity query string false "entity for relation"
+// @Param event_group query string false "country for relation"
+// @Param joint query string false "saved joint calendar name"
+// @Param mode query string false "union (default) or intersection"
+// @Param date query string true "date specific event"
+// @Success 200 {object} rest.Response[[]models.Event]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 409 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /changesets/{id}/holidays [get]
+// @Tags ChangeSets
+func (h *HTTP) ChangeSetHolidays(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing change-set ID")
+	}
+
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetChangeSetHolidays,
+		query.WithSkipExpressionCmp("date", "joint", "mode"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	events, err := h.Service.PreviewChangeSet(c.Request().Context(), id, q)
+	if err != nil {
+		return changeSetError(err)
+	}
+	if len(events) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no events found")
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Event]{
+		Meta: &rest.Meta{
+			TotalItemCount: uint64(len(events)),
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: events,
+	})
+}
+
+// bindChangeSet reads the editable fields of a change-set and checks its changes.
+func bindChangeSet(c echo.Context) (models.ChangeSet, error) {
+	v := models.ChangeSet{}
+	if err := rest.BindJSON(c.Request().Body, &v); err != nil {
+		return v, echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := checkChanges(&v.Changes.V); err != nil {
+		return v, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return models.ChangeSet{
+		Name:        v.Name,
+		Description: v.Description,
+		Changes:     v.Changes,
+		EffectiveAt: v.EffectiveAt,
+	}, nil
+}
+
+// checkChanges checks the events and relations of the changes like their own endpoints.
+func checkChanges(changes *models.Changes) error {
+	for i := range changes.AddEvents {
+		if err := checkEvent(&changes.AddEvents[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range changes.UpdateEvents {
+		if changes.UpdateEvents[i].ID == "" {
+			return errors.New("missing event ID to update")
+		}
+
+		if err := checkEvent(&changes.UpdateEvents[i]); err != nil {
+			return err
+		}
+	}
+
+	if slices.Contains(changes.RemoveEvents, "") {
+		return errors.New("missing event ID to remove")
+	}
+
+	for i := range changes.AddRelations {
+		if err := checkRelation(&changes.AddRelations[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range changes.RemoveRelations {
+		if err := checkRelation(&changes.RemoveRelations[i]); err != nil {
+			return err
+		}
+	}
+
+	if len(changes.AddEvents)+len(changes.UpdateEvents)+len(changes.RemoveEvents)+len(changes.AddRelations)+len(changes.RemoveRelations) == 0 {
+		return errors.New("missing changes")
+	}
+
+	return nil
+}
+
+func changeSetError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrChangeSetNotFound), errors.Is(err, domain.ErrJointNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrChangeSetApprover):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrChangeSetStatus),
+		errors.Is(err, domain.ErrEventNotFound),
+		errors.Is(err, domain.ErrEventVersion):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	return echo.NewHTTPError(http.StatusInternalServerError, err)
+}
+
+// /////////////////////////////////////////////////////////////
+// Batch
+// /////////////////////////////////////////////////////////////
+
+// @Summary Batch
+// @Description Batch runs the event and relation operations in order in one transaction, a failing operation rolls back all of them.
+// @Description op is one of add_event, update_event, remove_event, add_relation, update_relation or remove_relation.
+// @Description Event operations use id, version and event, relation operations use relation and to for the update.
+// @Description update_event and remove_event need the version of the event, without it 428 is returned.
+// @Description The results are in the order of the operations, after a failure they are rolled_back, failed or skipped.
+// @Param body body []models.BatchOperation true "Operations"
+// @Success 200 {object} rest.Response[[]models.BatchResult]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.Response[[]models.BatchResult]
+// @Failure 412 {object} rest.Response[[]models.BatchResult]
+// @Failure 428 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /batch [post]
+// @Tags Batch
+func (h *HTTP) Batch(c echo.Context) error {
+	v := []models.BatchOperation{}
+	if err := rest.BindJSONList(c.Request().Body, &v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if len(v) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing operations")
+	}
+
+	if len(v) > BatchLimit {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("too many operations, limit is %d", BatchLimit))
+	}
+
+	for i := range v {
+		if err := checkBatchOperation(&v[i]); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, domain.ErrEventVersionRequired) {
+				status = http.StatusPreconditionRequired
+			}
+
+			return echo.NewHTTPError(status, fmt.Sprintf("operation %d: %s", i, err.Error()))
+		}
+	}
+
+	results, err := h.Service.Batch(c.Request().Context(), v, server.GetUser(c))
+	if err != nil {
+		status := batchStatus(err)
+		if status == http.StatusInternalServerError {
+			return echo.NewHTTPError(status, err)
+		}
+
+		return c.JSON(status, rest.Response[[]models.BatchResult]{
+			Message: &rest.Message{
+				Text: "Batch rolled back",
+				Err:  err.Error(),
+			},
+			Payload: results,
+		})
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.BatchResult]{
+		Message: &rest.Message{
+			Text: "Batch applied",
+		},
+		Payload: results,
+	})
+}
+
+// checkBatchOperation checks the fields of the op like the endpoint of the operation.
+func checkBatchOperation(op *models.BatchOperation) error {
+	switch op.Op {
+	case models.BatchOpAddEvent, models.BatchOpUpdateEvent:
+		if op.Op == models.BatchOpUpdateEvent && op.ID == "" {
+			return errors.New("missing event ID")
+		}
+
+		if op.Event == nil {
+			return errors.New("missing event")
+		}
+
+		if op.Op == models.BatchOpUpdateEvent && op.Version == 0 {
+			return domain.ErrEventVersionRequired
+		}
+
+		return checkEvent(op.Event)
+	case models.BatchOpRemoveEvent:
+		if op.ID == "" {
+			return errors.New("missing event ID")
+		}
+
+		if op.Version == 0 {
+			return domain.ErrEventVersionRequired
+		}
+	case models.BatchOpAddRelation, models.BatchOpUpdateRelation, models.BatchOpRemoveRelation:
+		if op.Relation == nil {
+			return errors.New("missing relation")
+		}
+
+		if err := checkRelation(op.Relation); err != nil {
+			return err
+		}
+
+		if op.Op != models.BatchOpUpdateRelation {
+			return nil
+		}
+
+		if op.To == nil {
+			return errors.New("missing to relation")
+		}
+
+		return checkRelation(op.To)
+	default:
+		return fmt.Errorf("invalid op: %q", op.Op)
+	}
+
+	return nil
+}
+
+// batchStatus is the status code of the failed operation, errors of the database are internal.
+func batchStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrEventNotFound), errors.Is(err, domain.ErrRelationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrEventVersion):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domain.ErrEventVersionRequired):
+		return http.StatusPreconditionRequired
+	}
+
+	return http.StatusInternalServerError
+}
+
+// /////////////////////////////////////////////////////////////
+// Relations
+// /////////////////////////////////////////////////////////////
+
+// @Summary AddRelations
+// @Description AddRelations, type is one of include (default), parent or exclude.
+// @Description Parent relations make the entity inherit the calendar of the parent entity.
+// @Description Exclude relations remove an event_id or event_group from the entity and its inherited calendars.
+// @Description Exclude relations with an occurrence day remove only that occurrence of the event_id.
+// @Param body body []models.Relation true "Relation"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /relations [post]
+// @Tags Relations
+func (h *HTTP) AddRelations(c echo.Context) error {
+	v := []models.Relation{}
+	if err := rest.BindJSONList(c.Request().Body, &v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	updatedBy := server.GetUser(c)
+	for i := range v {
+		if err := checkRelation(&v[i]); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		v[i].UpdatedBy = updatedBy
+	}
+
+	if err := h.Service.AddRelations(c.Request().Context(), v); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Relations added",
+		},
+	})
+}
+
+// @Summary DeleteRelations
+// @Description DeleteRelations for multiple relations, they move to the trash and are restored with POST /relations/restore.
+// @Param entity query string true "entity"
+// @Param event_id query string false "event_id"
+// @Param event_group query string false "event_group"
+// @Param type query string false "type"
+// @Param parent query string false "parent"
+// @Param occurrence query string false "occurrence"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /relations [delete]
+// @Tags Relations
+func (h *HTTP) DeleteRelations(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.DeleteRelations,
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.Service.RemoveRelation(c.Request().Context(), q, server.GetUser(c)); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Relation removed",
+		},
+	})
+}
+
+// @Summary PatchRelations
+// @Description PatchRelations changes the matching relations in place with a JSON merge patch (RFC 7396).
+// @Description The patch is applied to every matching relation and checked like a new relation, all of them are changed or none.
+// @Accept application/merge-patch+json
+// @Param entity query string true "entity"
+// @Param event_id query string false "event_id"
+// @Param event_group query string false "event_group"
+// @Param type query string false "type"
+// @Param parent query string false "parent"
+// @Param occurrence query string false "occurrence"
+// @Param body body models.Relation true "Fields of the relations to change"
+// @Success 200 {object} rest.Response[[]models.Relation]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 404 {object} rest.ResponseMessage
+// @Failure 415 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /relations [patch]
+// @Tags Relations
+func (h *HTTP) PatchRelations(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.PatchRelations,
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	patch, err := readMergePatch(c)
+	if err != nil {
+		return err
+	}
+
+	relations, err := h.Service.GetRelations(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(relations) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no relations found")
+	}
+
+	updatedBy := server.GetUser(c)
+	updates := make([]models.RelationUpdate, 0, len(relations))
+	for _, r := range relations {
+		v, err := mergePatch(r, patch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		if err := checkRelation(&v); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		v.UpdatedBy = updatedBy
+		updates = append(updates, models.RelationUpdate{From: r, To: v})
+	}
+
+	if err := h.Service.UpdateRelations(c.Request().Context(), updates); err != nil {
+		if errors.Is(err, domain.ErrRelationNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	result := make([]models.Relation, 0, len(updates))
+	for _, u := range updates {
+		result = append(result, u.To)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Relation]{
+		Message: &rest.Message{
+			Text: "Relations updated",
+		},
+		Meta: &rest.Meta{
+			TotalItemCount: uint64(len(result)),
+		},
+		Payload: result,
+	})
+}
+
+// @Summary GetRelations
+// @Description GetRelations
+// @Param entity query string false "entity"
+// @Param event_id query string false "event_id"
+// @Param event_group query string false "event_group"
+// @Param type query string false "type"
+// @Param parent query string false "parent"
+// @Param occurrence query string false "occurrence"
+// @Param sort query string false "sort"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.Relation]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /relations [get]
+// @Tags Relations
+func (h *HTTP) GetRelations(c echo.Context) error {
+	q, err := query.ParseWithValidator(c.QueryString(), h.Validator.GetRelations, query.WithDefaultLimit(DefaultLimit))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	relations, err := h.Service.GetRelations(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(relations) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no relations found")
+	}
+
+	count, err := h.Service.GetRelationsCount(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Relation]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: relations,
+	})
+}
+
+// checkRelation sets the default type and checks the fields required by the type.
+func checkRelation(r *models.Relation) error {
+	if r.Entity == "" {
+		return errors.New("missing entity")
+	}
+
+	if r.Type == "" {
+		r.Type = models.RelationTypeInclude
+	}
+
+	if r.Occurrence.Valid {
+		if r.Type != models.RelationTypeExclude || !r.EventID.Valid {
+			return errors.New("occurrence is only allowed for exclude relation with event_id")
+		}
+
+		// keep the day as written, the column is a date
+		year, month, day := r.Occurrence.V.Date()
+		r.Occurrence.V = types.Time{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+	}
+
+	switch r.Type {
+	case models.RelationTypeInclude, models.RelationTypeExclude:
+		if !r.EventID.Valid && !r.EventGroup.Valid {
+			return fmt.Errorf("missing event_id or event_group for %s relation", r.Type)
+		}
+		if r.Parent.Valid {
+			return fmt.Errorf("parent is not allowed for %s relation", r.Type)
+		}
+	case models.RelationTypeParent:
+		if r.Parent.V == "" {
+			return errors.New("missing parent for parent relation")
+		}
+		if r.Parent.V == r.Entity {
+			return errors.New("entity cannot be parent of itself")
+		}
+		if r.EventID.Valid || r.EventGroup.Valid {
+			return errors.New("event_id and event_group are not allowed for parent relation")
+		}
+	default:
+		return fmt.Errorf("invalid relation type: %s", r.Type)
+	}
+
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////
+// Joints
+// /////////////////////////////////////////////////////////////
+
+// @Summary GetJoints
+// @Description GetJoints
+// @Param name query string false "name"
+// @Param mode query string false "mode"
+// @Param sort query string false "sort"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.Joint]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /joints [get]
+// @Tags Joints
+func (h *HTTP) GetJoints(c echo.Context) error {
+	q, err := query.ParseWithValidator(c.QueryString(), h.Validator.GetJoints, query.WithDefaultLimit(DefaultLimit))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	joints, err := h.Service.GetJoints(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(joints) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no joints found")
+	}
+
+	count, err := h.Service.GetJointsCount(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Joint]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: joints,
+	})
+}
+
+// @Summary AddJoints
+// @Description AddJoints, a joint calendar combines the entities with the mode union (default) or intersection.
+// @Description Union closes a day when any of the entities has a holiday, intersection only when all of them have.
+// @Param body body []models.Joint true "Joint"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /joints [post]
+// @Tags Joints
+func (h *HTTP) AddJoints(c echo.Context) error {
+	v := []models.Joint{}
+	if err := rest.BindJSONList(c.Request().Body, &v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	updatedBy := server.GetUser(c)
+	for i := range v {
+		if v[i].Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "missing joint name")
+		}
+
+		if err := checkJoint(&v[i]); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		v[i].UpdatedBy = updatedBy
+	}
+
+	if err := h.Service.AddJoints(c.Request().Context(), v); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Joints added",
+		},
+	})
+}
+
+// @Summary GetJoint
+// @Description GetJoint
+// @Param name path string true "Joint name"
+// @Success 200 {object} rest.Response[models.Joint]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /joints/{name} [get]
+// @Tags Joints
+func (h *HTTP) GetJoint(c echo.Context) error {
+	name := c.Param("name")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing joint name")
+	}
+
+	joint, err := h.Service.GetJoint(c.Request().Context(), name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if joint == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "joint not found")
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.Joint]{
+		Payload: *joint,
+	})
+}
+
+// @Summary DeleteJoint
+// @Description DeleteJoint
+// @Param name path string true "Joint name"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /joints/{name} [delete]
+// @Tags Joints
+func (h *HTTP) DeleteJoint(c echo.Context) error {
+	name := c.Param("name")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing joint name")
+	}
+
+	if err := h.Service.RemoveJoint(c.Request().Context(), name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// @Summary PutJoint
+// @Description PutJoint
+// @Param name path string true "Joint name"
+// @Param body body models.Joint true "Joint"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /joints/{name} [put]
+// @Tags Joints
+func (h *HTTP) PutJoint(c echo.Context) error {
+	name := c.Param("name")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing joint name")
+	}
+
+	v := models.Joint{}
+	if err := rest.BindJSON(c.Request().Body, &v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := checkJoint(&v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	v.UpdatedBy = server.GetUser(c)
+
+	if err := h.Service.UpdateJoint(c.Request().Context(), name, &v); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Joint updated",
+		},
+	})
+}
+
+// checkJoint sets the default mode and checks the entities.
+func checkJoint(j *models.Joint) error {
+	if len(j.Entities) == 0 {
+		return errors.New("missing entities")
+	}
+
+	for _, entity := range j.Entities {
+		if entity == "" {
+			return errors.New("empty entity")
+		}
+	}
+
+	switch j.Mode {
+	case "":
+		j.Mode = models.JointModeUnion
+	case models.JointModeUnion, models.JointModeIntersection:
+	default:
+		return fmt.Errorf("invalid joint mode: %s", j.Mode)
+	}
+
+	return nil
+}
+
+// checkAsOf validates the as_of time of the query, the calendar is resolved as it was at that time.
+func checkAsOf(q *query.Query) error {
+	if v := q.GetValue("as_of"); v != "" {
+		var t types.Time
+		if err := t.Parse(v); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid as_of: "+err.Error())
+		}
+	}
+
+	return nil
+}
+
+// searchError returns not found for a missing joint calendar and bad request for an invalid as_of, other errors are internal.
+func searchError(err error) error {
+	if errors.Is(err, domain.ErrJointNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if errors.Is(err, domain.ErrAsOf) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return echo.NewHTTPError(http.StatusInternalServerError, err)
+}
+
+// /////////////////////////////////////////////////////////////
+// Business Hours
+// /////////////////////////////////////////////////////////////
+
+// @Summary GetHours
+// @Description GetHours
+// @Param entity query string false "entity"
+// @Param tz query string false "tz"
+// @Param sort query string false "sort"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.BusinessHours]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /hours [get]
+// @Tags Hours
+func (h *HTTP) GetHours(c echo.Context) error {
+	q, err := query.ParseWithValidator(c.QueryString(), h.Validator.GetHours, query.WithDefaultLimit(DefaultLimit))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	hours, err := h.Service.GetHours(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(hours) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no business hours found")
+	}
+
+	count, err := h.Service.GetHoursCount(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.BusinessHours]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: hours,
+	})
+}
+
+// @Summary GetEntityHours
+// @Description GetEntityHours
+// @Param entity path string true "Entity"
+// @Success 200 {object} rest.Response[models.BusinessHours]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /hours/{entity} [get]
+// @Tags Hours
+func (h *HTTP) GetEntityHours(c echo.Context) error {
+	entity := c.Param("entity")
+	if entity == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing entity")
+	}
+
+	hours, err := h.Service.GetEntityHours(c.Request().Context(), entity)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if hours == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "business hours not found")
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.BusinessHours]{
+		Payload: *hours,
+	})
+}
+
+// @Summary PutEntityHours
+// @Description PutEntityHours sets the weekly business hours of the entity.
+// @Description Hours are lowercase weekday names to open windows like {"monday": [{"from": "09:00", "to": "17:00"}]}, a weekday without windows is closed.
+// @Param entity path string true "Entity"
+// @Param body body models.BusinessHours true "BusinessHours"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /hours/{entity} [put]
+// @Tags Hours
+func (h *HTTP) PutEntityHours(c echo.Context) error {
+	entity := c.Param("entity")
+	if entity == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing entity")
+	}
+
+	v := models.BusinessHours{}
+	if err := rest.BindJSON(c.Request().Body, &v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := checkHours(&v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	v.Entity = entity
+	v.UpdatedBy = server.GetUser(c)
+
+	if err := h.Service.SetHours(c.Request().Context(), &v); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Business hours updated",
+		},
+	})
+}
+
+// @Summary DeleteEntityHours
+// @Description DeleteEntityHours, the entity falls back to the default hours.
+// @Param entity path string true "Entity"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /hours/{entity} [delete]
+// @Tags Hours
+func (h *HTTP) DeleteEntityHours(c echo.Context) error {
+	entity := c.Param("entity")
+	if entity == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing entity")
+	}
+
+	if err := h.Service.RemoveHours(c.Request().Context(), entity); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var weekdays = map[string]struct{}{
+	"monday": {}, "tuesday": {}, "wednesday": {}, "thursday": {}, "friday": {}, "saturday": {}, "sunday": {},
+}
+
+// checkHours checks the timezone, weekday names and the windows.
+func checkHours(v *models.BusinessHours) error {
+	if _, err := time.LoadLocation(v.Tz); err != nil {
+		return fmt.Errorf("invalid timezone: %s", v.Tz)
+	}
+
+	for day, windows := range v.Hours {
+		if _, ok := weekdays[day]; !ok {
+			return fmt.Errorf("invalid weekday: %s", day)
+		}
+
+		for _, w := range windows {
+			if _, _, err := w.Minutes(); err != nil {
+				return fmt.Errorf("invalid window of %s: %w", day, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////
+// Weekends
+// /////////////////////////////////////////////////////////////
+
+// @Summary GetWeekends
+// @Description GetWeekends
+// @Param entity query string false "entity"
+// @Param effective_from query string false "effective_from"
+// @Param sort query string false "sort"
+// @Param limit query int false "limit" default(25)
+// @Param offset query int false "offset"
+// @Success 200 {object} rest.Response[[]models.Weekend]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /weekends [get]
+// @Tags Weekends
+func (h *HTTP) GetWeekends(c echo.Context) error {
+	q, err := query.ParseWithValidator(c.QueryString(), h.Validator.GetWeekends, query.WithDefaultLimit(DefaultLimit))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	weekends, err := h.Service.GetWeekends(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	if len(weekends) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no weekends found")
+	}
+
+	count, err := h.Service.GetWeekendsCount(c.Request().Context(), q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Weekend]{
+		Meta: &rest.Meta{
+			TotalItemCount: count,
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: weekends,
+	})
+}
+
+// @Summary AddWeekends
+// @Description AddWeekends, the weekend days of the entity starting from effective_from until the next pattern.
+// @Description Days before the first pattern use saturday and sunday, an existing effective_from of the entity is replaced.
+// @Param body body []models.Weekend true "Weekend"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /weekends [post]
+// @Tags Weekends
+func (h *HTTP) AddWeekends(c echo.Context) error {
+	v := []models.Weekend{}
+	if err := rest.BindJSONList(c.Request().Body, &v); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	updatedBy := server.GetUser(c)
+	for i := range v {
+		if err := checkWeekend(&v[i]); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		v[i].UpdatedBy = updatedBy
+	}
+
+	if err := h.Service.AddWeekends(c.Request().Context(), v); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Weekends added",
+		},
+	})
+}
+
+// @Summary DeleteWeekends
+// @Description DeleteWeekends for multiple weekend patterns
+// @Param entity query string true "entity"
+// @Param effective_from query string false "effective_from"
+// @Success 200 {object} rest.ResponseMessage
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /weekends [delete]
+// @Tags Weekends
+func (h *HTTP) DeleteWeekends(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.DeleteWeekends,
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.Service.RemoveWeekends(c.Request().Context(), q); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, rest.ResponseMessage{
+		Message: &rest.Message{
+			Text: "Weekends removed",
+		},
+	})
+}
+
+// checkWeekend checks the weekday names and keeps only the day of effective_from.
+func checkWeekend(w *models.Weekend) error {
+	if w.Entity == "" {
+		return errors.New("missing entity")
+	}
+
+	if w.EffectiveFrom.IsZero() {
+		return errors.New("missing effective_from")
+	}
+
+	year, month, day := w.EffectiveFrom.Date()
+	w.EffectiveFrom = types.Time{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+
+	for _, day := range w.Days {
+		if _, ok := weekdays[day]; !ok {
+			return fmt.Errorf("invalid weekday: %s", day)
+		}
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////
+
+// @Summary Holidays
+// @Description Holidays for specific date, events starting on a weekend day of the entity have the weekend flag.
+// @Description Multiple entities or a joint calendar are combined with the mode, intersection returns holidays only when all entities have one.
+// @Description ETag and Last-Modified come from the last change of the events, relations, weekends and the joint calendar, If-None-Match or If-Modified-Since returns 304.
+// @Param entity query string false "entity for relation"
+// @Param event_group query string false "country for relation"
+// @Param joint query string false "saved joint calendar name"
+// @Param mode query string false "union (default) or intersection"
+// @Param date query string true "date specific event"
+// @Param as_of query string false "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z"
+// @Param If-None-Match header string false "ETag of the previous result"
+// @Param If-Modified-Since header string false "Last-Modified of the previous result"
+// @Success 200 {object} rest.Response[[]models.Event]
+// @Success 304
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /holidays [get]
+// @Tags Search
+func (h *HTTP) Holidays(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetEventsDate,
+		query.WithSkipExpressionCmp("date", "joint", "mode", "as_of"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := checkAsOf(q); err != nil {
+		return err
+	}
+
+	unchanged, err := h.notModifiedQuery(c, q)
+	if err != nil {
+		return err
+	}
+
+	if unchanged {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	events, err := h.Service.GetEvents(c.Request().Context(), q)
+	if err != nil {
+		return searchError(err)
+	}
+	if len(events) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no events found")
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Event]{
+		Meta: &rest.Meta{
+			TotalItemCount: uint64(len(events)),
+			Limit:          q.GetLimit(),
+			Offset:         q.GetOffset(),
+		},
+		Payload: events,
+	})
+}
+
+// @Summary WorkDay
+// @Description Next workday after the date, weekends and holidays of the entities are skipped
+// @Description Multiple entities or a joint calendar are combined with the mode, union needs a workday in all entities.
+// @Param entity query string false "entity for relation"
+// @Param event_group query string false "country for relation"
+// @Param joint query string false "saved joint calendar name"
+// @Param mode query string false "union (default) or intersection"
+// @Param date query string true "date to start from"
+// @Param days query int false "number of workdays to move" default(1)
+// @Param as_of query string false "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z"
+// @Success 200 {object} rest.Response[models.WorkDay]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /workday [get]
+// @Tags Search
+func (h *HTTP) WorkDay(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetWorkDay,
+		query.WithSkipExpressionCmp("date", "joint", "mode", "days", "as_of"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := checkAsOf(q); err != nil {
+		return err
+	}
+
+	date := types.Time{}
+	if err := date.Parse(q.GetValue("date")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid date: "+err.Error())
+	}
+
+	days := 1
+	if v := q.GetValue("days"); v != "" {
+		days, err = strconv.Atoi(v)
+		if err != nil || days < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid days: "+v)
+		}
+	}
+
+	workDay, err := h.Service.WorkDay(c.Request().Context(), q, date, days)
+	if err != nil {
+		return searchError(err)
 	}
-	if len(relations) == 0 {
-		return echo.NewHTTPError(http.StatusNotFound, "no relations found")
+
+	return c.JSON(http.StatusOK, rest.Response[models.WorkDay]{
+		Payload: *workDay,
+	})
+}
+
+// @Summary IsOpenAt
+// @Description Open state at the time with the business hours, holidays and half-day events of the entities.
+// @Description Entities without business hours are open all day in UTC, weekend days of the entities are closed.
+// @Description Multiple entities or a joint calendar are combined with the mode, union needs all entities open.
+// @Param entity query string false "entity for relation"
+// @Param event_group query string false "country for relation"
+// @Param joint query string false "saved joint calendar name"
+// @Param mode query string false "union (default) or intersection"
+// @Param time query string true "time to check like 2025-12-24T14:00:00Z"
+// @Param as_of query string false "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z"
+// @Success 200 {object} rest.Response[models.OpenAt]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /is-open-at [get]
+// @Tags Search
+func (h *HTTP) IsOpenAt(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetOpenAt,
+		query.WithSkipExpressionCmp("time", "joint", "mode", "as_of"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	count, err := h.Service.GetRelationsCount(c.Request().Context(), q)
+	if err := checkAsOf(q); err != nil {
+		return err
+	}
+
+	t := types.Time{}
+	if err := t.Parse(q.GetValue("time")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid time: "+err.Error())
+	}
+
+	openAt, err := h.Service.IsOpenAt(c.Request().Context(), q, t)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+		return searchError(err)
 	}
 
-	return c.JSON(http.StatusOK, rest.Response[[]models.Relation]{
+	return c.JSON(http.StatusOK, rest.Response[models.OpenAt]{
+		Payload: *openAt,
+	})
+}
+
+// @Summary Settlement
+// @Description Settlement date of a trade after T+N workdays of the entities, skipped holidays are returned.
+// @Description Trades at or after the cut-off, or not on a workday, are booked on the next workday.
+// @Description Multiple entities or a joint calendar are combined with the mode, union needs a workday in all entities.
+// @Param entity query string false "entity for relation"
+// @Param event_group query string false "country for relation"
+// @Param joint query string false "saved joint calendar name"
+// @Param mode query string false "union (default) or intersection"
+// @Param trade query string true "trade timestamp like 2025-04-17T16:30:00Z"
+// @Param days query int false "number of workdays after the trade date" default(0)
+// @Param cutoff query string false "cut-off time like 16:00"
+// @Param tz query string false "timezone of the cut-off, default is the business hours timezone of the first entity"
+// @Param as_of query string false "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z"
+// @Success 200 {object} rest.Response[models.Settlement]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /settlement [get]
+// @Tags Search
+func (h *HTTP) Settlement(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetSettlement,
+		query.WithSkipExpressionCmp("joint", "mode", "trade", "days", "cutoff", "tz", "as_of"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := checkAsOf(q); err != nil {
+		return err
+	}
+
+	req := models.SettlementRequest{
+		CutOff: q.GetValue("cutoff"),
+		Tz:     q.GetValue("tz"),
+	}
+
+	if err := req.Trade.Parse(q.GetValue("trade")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid trade: "+err.Error())
+	}
+
+	if v := q.GetValue("days"); v != "" {
+		req.Days, err = strconv.Atoi(v)
+		if err != nil || req.Days < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid days: "+v)
+		}
+	}
+
+	if req.CutOff != "" {
+		if _, err := time.Parse("15:04", req.CutOff); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid cutoff: "+req.CutOff)
+		}
+	}
+
+	if req.Tz != "" {
+		if _, err := time.LoadLocation(req.Tz); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid timezone: "+req.Tz)
+		}
+	}
+
+	settlement, err := h.Service.Settlement(c.Request().Context(), q, req)
+	if err != nil {
+		return searchError(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.Settlement]{
+		Payload: *settlement,
+	})
+}
+
+// @Summary Adjust
+// @Description Adjust the date to a workday of the entities with a business day convention.
+// @Description Conventions are following (default), modified_following, preceding, modified_preceding and end_of_month.
+// @Description Multiple entities or a joint calendar are combined with the mode, union needs a workday in all entities.
+// @Param entity query string false "entity for relation"
+// @Param event_group query string false "country for relation"
+// @Param joint query string false "saved joint calendar name"
+// @Param mode query string false "union (default) or intersection"
+// @Param date query string true "date to adjust"
+// @Param convention query string false "business day convention" default(following)
+// @Param as_of query string false "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z"
+// @Success 200 {object} rest.Response[models.Adjustment]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /adjust [get]
+// @Tags Search
+func (h *HTTP) Adjust(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetAdjust,
+		query.WithSkipExpressionCmp("joint", "mode", "date", "convention", "as_of"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := checkAsOf(q); err != nil {
+		return err
+	}
+
+	date := types.Time{}
+	if err := date.Parse(q.GetValue("date")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid date: "+err.Error())
+	}
+
+	convention := q.GetValue("convention")
+	if convention == "" {
+		convention = models.ConventionFollowing
+	}
+
+	adjustment, err := h.Service.Adjust(c.Request().Context(), q, date, convention)
+	if err != nil {
+		return searchError(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.Adjustment]{
+		Payload: *adjustment,
+	})
+}
+
+// @Summary Schedule
+// @Description Business days of a recurring schedule between from and to, resolved with the holidays and weekends of the entities.
+// @Description Rule is like FREQ=MONTHLY;BYBUSINESSDAY=-1 with FREQ of WEEKLY, MONTHLY or YEARLY and optional INTERVAL, BYMONTH and COUNT.
+// @Description BYBUSINESSDAY ordinals count the business days in every period, negative ones from the end.
+// @Param entity query string false "entity for relation"
+// @Param event_group query string false "country for relation"
+// @Param joint query string false "saved joint calendar name"
+// @Param mode query string false "union (default) or intersection"
+// @Param rule query string true "schedule rule, URL encoded"
+// @Param from query string true "start date, inclusive"
+// @Param to query string true "end date, exclusive"
+// @Param as_of query string false "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z"
+// @Success 200 {object} rest.Response[[]string]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /schedule [get]
+// @Tags Search
+func (h *HTTP) Schedule(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetSchedule,
+		query.WithSkipExpressionCmp("joint", "mode", "rule", "from", "to", "as_of"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := checkAsOf(q); err != nil {
+		return err
+	}
+
+	// rule has its own separators, take it as it is
+	rule := c.QueryParam("rule")
+	if _, err := ical.ParseSchedule(rule); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid rule: "+err.Error())
+	}
+
+	from, to := types.Time{}, types.Time{}
+	if err := from.Parse(q.GetValue("from")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid from: "+err.Error())
+	}
+	if err := to.Parse(q.GetValue("to")); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid to: "+err.Error())
+	}
+
+	if !to.After(from.Time) || to.Sub(from.Time) > time.Duration(ScheduleRangeLimit)*24*time.Hour {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("to should be after from within %d days", ScheduleRangeLimit))
+	}
+
+	dates, err := h.Service.Schedule(c.Request().Context(), q, rule, from, to)
+	if err != nil {
+		return searchError(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]types.Time]{
 		Meta: &rest.Meta{
-			TotalItemCount: count,
-			Limit:          q.GetLimit(),
-			Offset:         q.GetOffset(),
+			TotalItemCount: uint64(len(dates)),
 		},
-		Payload: relations,
+		Payload: dates,
 	})
 }
 
-// ////////////////////////////////////////////////////////////////
+// @Summary Bridges
+// @Description Bridge days and long weekends starting in the year with the holidays and weekends of the entities.
+// @Description A bridge is a run of up to days workdays between closed days next to a holiday.
+// @Description A long weekend is a closed run of more than two days with a weekend day and a holiday.
+// @Param entity query string false "entity for relation"
+// @Param event_group query string false "country for relation"
+// @Param joint query string false "saved joint calendar name"
+// @Param mode query string false "union (default) or intersection"
+// @Param year query int false "year to check, default is current year"
+// @Param days query int false "maximum workdays of a bridge" default(1)
+// @Param as_of query string false "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z"
+// @Success 200 {object} rest.Response[models.Bridges]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /bridges [get]
+// @Tags Search
+func (h *HTTP) Bridges(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetBridges,
+		query.WithSkipExpressionCmp("joint", "mode", "year", "days", "as_of"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 
-// @Summary Holidays
-// @Description Holidays for specific date
+	if err := checkAsOf(q); err != nil {
+		return err
+	}
+
+	year := time.Now().Year()
+	if v := q.GetValue("year"); v != "" {
+		year, err = strconv.Atoi(v)
+		if err != nil || year < 1 || year > 9999 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid year: "+v)
+		}
+	}
+
+	days := 1
+	if v := q.GetValue("days"); v != "" {
+		days, err = strconv.Atoi(v)
+		if err != nil || days < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid days: "+v)
+		}
+	}
+
+	bridges, err := h.Service.Bridges(c.Request().Context(), q, year, days)
+	if err != nil {
+		return searchError(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.Bridges]{
+		Payload: *bridges,
+	})
+}
+
+// @Summary Diff
+// @Description Added, removed and moved holidays of the entity and year compared with the base entity and year.
+// @Description Holidays are matched by event ID and then by name, base dates are shifted to the year before comparing.
+// @Param entity query string true "entity to compare"
+// @Param year query int false "year to compare, default is current year"
+// @Param base_entity query string false "base entity, default is entity"
+// @Param base_year query int false "base year, default is the previous year for the same entity and year otherwise"
+// @Success 200 {object} rest.Response[models.Diff]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /diff [get]
+// @Tags Search
+func (h *HTTP) Diff(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetDiff,
+		query.WithSkipExpressionCmp("entity", "year", "base_entity", "base_year"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	target := models.DiffSide{
+		Entity: q.GetValue("entity"),
+		Year:   time.Now().Year(),
+	}
+	if target.Entity == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "entity is required")
+	}
+
+	if v := q.GetValue("year"); v != "" {
+		target.Year, err = strconv.Atoi(v)
+		if err != nil || target.Year < 1 || target.Year > 9999 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid year: "+v)
+		}
+	}
+
+	base := models.DiffSide{
+		Entity: target.Entity,
+		Year:   target.Year - 1,
+	}
+	if v := q.GetValue("base_entity"); v != "" && v != target.Entity {
+		base.Entity = v
+		base.Year = target.Year
+	}
+
+	if v := q.GetValue("base_year"); v != "" {
+		base.Year, err = strconv.Atoi(v)
+		if err != nil || base.Year < 1 || base.Year > 9999 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid base_year: "+v)
+		}
+	}
+
+	diff, err := h.Service.Diff(c.Request().Context(), base, target)
+	if err != nil {
+		return searchError(err)
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[models.Diff]{
+		Payload: *diff,
+	})
+}
+
+// @Summary Coverage
+// @Description Entities and event groups with missing or low occurrences in the upcoming years.
+// @Description A year is low when it has fewer occurrences than the ratio of the current year, all lists the ok ones too.
+// @Param years query int false "number of upcoming years" default(2)
+// @Param ratio query number false "ratio of the current year occurrences" default(0.5)
+// @Param all query bool false "list all entities and event groups"
+// @Success 200 {object} rest.Response[[]models.Coverage]
+// @Failure 400 {object} rest.ResponseMessage
+// @Failure 500 {object} rest.ResponseMessage
+// @Router /coverage [get]
+// @Tags Search
+func (h *HTTP) Coverage(c echo.Context) error {
+	q, err := query.ParseWithValidator(
+		c.QueryString(),
+		h.Validator.GetCoverage,
+		query.WithSkipExpressionCmp("years", "ratio", "all"),
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	years := models.DefaultCoverageYears
+	if v := q.GetValue("years"); v != "" {
+		years, err = strconv.Atoi(v)
+		if err != nil || years < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid years: "+v)
+		}
+	}
+
+	ratio := float64(models.DefaultCoverageRatio)
+	if v := q.GetValue("ratio"); v != "" {
+		ratio, err = strconv.ParseFloat(v, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid ratio: "+v)
+		}
+	}
+
+	coverage, err := h.Service.Coverage(c.Request().Context(), years, ratio)
+	if err != nil {
+		return searchError(err)
+	}
+
+	if q.GetValue("all") != "true" {
+		coverage = slices.DeleteFunc(coverage, func(v models.Coverage) bool {
+			return v.Status == models.CoverageStatusOK
+		})
+	}
+
+	return c.JSON(http.StatusOK, rest.Response[[]models.Coverage]{
+		Meta: &rest.Meta{
+			TotalItemCount: uint64(len(coverage)),
+		},
+		Payload: coverage,
+	})
+}
+
+// @Summary GetDefinitions
+// @Description Enabled events of the entities with the resolved exclusions, to calculate the occurrences offline.
+// @Description ETag is the hash of the result, If-None-Match with the same value returns 304.
 // @Param entity query string false "entity for relation"
 // @Param event_group query string false "country for relation"
-// @Param date query string true "date specific event"
+// @Param If-None-Match header string false "ETag of the previous result"
 // @Success 200 {object} rest.Response[[]models.Event]
+// @Success 304
 // @Failure 400 {object} rest.ResponseMessage
 // @Failure 500 {object} rest.ResponseMessage
-// @Router /holidays [get]
+// @Router /definitions [get]
 // @Tags Search
-func (h *HTTP) Holidays(c echo.Context) error {
+func (h *HTTP) GetDefinitions(c echo.Context) error {
 	q, err := query.ParseWithValidator(
 		c.QueryString(),
-		h.Validator.GetEventsDate,
-		query.WithSkipExpressionCmp("date"),
+		h.Validator.GetDefinition,
 	)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	events, err := h.Service.GetEvents(c.Request().Context(), q)
+	events, err := h.Service.GetDefinitions(c.Request().Context(), q)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
-	}
-	if len(events) == 0 {
-		return echo.NewHTTPError(http.StatusNotFound, "no events found")
+		return searchError(err)
 	}
 
-	return c.JSON(http.StatusOK, rest.Response[[]models.Event]{
+	body, err := json.Marshal(rest.Response[[]models.Event]{
 		Meta: &rest.Meta{
 			TotalItemCount: uint64(len(events)),
-			Limit:          q.GetLimit(),
-			Offset:         q.GetOffset(),
 		},
 		Payload: events,
 	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	sum := sha256.Sum256(body)
+	if notModified(c, `"`+hex.EncodeToString(sum[:16])+`"`) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSONBlob(http.StatusOK, body)
+}
+
+// notModified sets the ETag of the response and reports whether the request has the same one in If-None-Match.
+func notModified(c echo.Context, etag string) bool {
+	c.Response().Header().Set("ETag", etag)
+
+	return matchETag(c.Request().Header.Get("If-None-Match"), etag)
+}
+
+// notModifiedQuery sets the validators of the query result and reports whether the request has them.
+// ETag is the hash of the path, query and the last change of the used data, If-None-Match has precedence over If-Modified-Since.
+// Results of the queries without a year depend on the current year, so it is part of the ETag.
+func (h *HTTP) notModifiedQuery(c echo.Context, q *query.Query) (bool, error) {
+	modified, err := h.Service.Modified(c.Request().Context(), q)
+	if err != nil {
+		return false, searchError(err)
+	}
+
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s?%s\n%d\n%d,%d,%d,%d,%s",
+		c.Path(), c.QueryParams().Encode(), time.Now().Year(),
+		modified.UpdatedAt.UnixNano(), modified.Events, modified.Relations, modified.Weekends, modified.Mode,
+	))
+
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	if !modified.UpdatedAt.IsZero() {
+		c.Response().Header().Set("Last-Modified", modified.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, `"`+hex.EncodeToString(sum[:16])+`"`) {
+		return true, nil
+	}
+
+	if c.Request().Header.Get("If-None-Match") != "" || modified.UpdatedAt.IsZero() {
+		return false, nil
+	}
+
+	since, err := http.ParseTime(c.Request().Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false, nil
+	}
+
+	return !modified.UpdatedAt.Truncate(time.Second).After(since), nil
+}
+
+// matchETag reports whether the If-None-Match header value has the ETag or "*", weak tags are compared without W/.
+func matchETag(header, etag string) bool {
+	for v := range strings.SplitSeq(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchStrongETag reports whether the If-Match header value has the ETag or "*", a weak tag never matches (RFC 9110).
+func matchStrongETag(header, etag string) bool {
+	for v := range strings.SplitSeq(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+
+	return false
 }
 
 // @Summary AddICS
@@ -535,10 +3065,16 @@ func (h *HTTP) AddICS(c echo.Context) error {
 
 // @Summary GetICS
 // @Description GetICS
+// @Description ETag and Last-Modified come from the last change of the events, relations, weekends and the joint calendar, If-None-Match or If-Modified-Since returns 304.
 // @Param entity query string false "entity for relation"
 // @Param event_group query string false "country"
+// @Param joint query string false "saved joint calendar name, events of all entities are listed"
 // @Param year query string false "specific year events"
+// @Param as_of query string false "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z"
+// @Param If-None-Match header string false "ETag of the previous result"
+// @Param If-Modified-Since header string false "Last-Modified of the previous result"
 // @Success 200 {object} rest.ResponseMessage
+// @Success 304
 // @Failure 400 {object} rest.ResponseMessage
 // @Failure 500 {object} rest.ResponseMessage
 // @Router /ics [get]
@@ -547,19 +3083,35 @@ func (h *HTTP) GetICS(c echo.Context) error {
 	q, err := query.ParseWithValidator(
 		c.QueryString(),
 		h.Validator.GetICS,
-		query.WithSkipExpressionCmp("year"),
+		query.WithSkipExpressionCmp("year", "joint", "as_of"),
 	)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if err := checkAsOf(q); err != nil {
+		return err
+	}
+
+	unchanged, err := h.notModifiedQuery(c, q)
+	if err != nil {
+		return err
+	}
+
+	if unchanged {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	events, err := h.Service.GetEventsICS(c.Request().Context(), q)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+		return searchError(err)
 	}
 
 	// convert ics format
 	category := strings.Join(q.GetValues("entity"), ",")
+	if joint := q.GetValue("joint"); joint != "" {
+		category = joint
+	}
 	fileName := strings.ToLower(strings.ReplaceAll(category, ",", "_"))
 	if fileName == "" {
 		fileName = "events"
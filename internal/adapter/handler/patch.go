@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEMergePatch is the content type of the JSON merge patch (RFC 7396).
+const MIMEMergePatch = "application/merge-patch+json"
+
+// mergePatch applies the JSON merge patch to a copy of v with the JSON tags of the type.
+// Null removes the field so it gets the zero value, objects are merged and other values replace the field.
+func mergePatch[T any](v T, patch []byte) (T, error) {
+	var result T
+
+	var patchValue any
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return result, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	if _, ok := patchValue.(map[string]any); !ok {
+		return result, fmt.Errorf("merge patch should be an object")
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return result, err
+	}
+
+	var target any
+	if err := json.Unmarshal(data, &target); err != nil {
+		return result, err
+	}
+
+	data, err = json.Marshal(mergeValue(target, patchValue))
+	if err != nil {
+		return result, err
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	return result, nil
+}
+
+func mergeValue(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any, len(patchObject))
+	}
+
+	for k, v := range patchObject {
+		if v == nil {
+			delete(targetObject, k)
+
+			continue
+		}
+
+		targetObject[k] = mergeValue(targetObject[k], v)
+	}
+
+	return targetObject
+}
+
+// readMergePatch returns the body of a merge patch request, plain JSON is accepted too.
+func readMergePatch(c echo.Context) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != MIMEMergePatch && mediaType != echo.MIMEApplicationJSON {
+		return nil, echo.NewHTTPError(http.StatusUnsupportedMediaType, "content type should be "+MIMEMergePatch)
+	}
+
+	var patch json.RawMessage
+	if err := json.NewDecoder(c.Request().Body).Decode(&patch); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid merge patch: "+err.Error())
+	}
+
+	return patch, nil
+}
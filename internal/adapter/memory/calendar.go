@@ -0,0 +1,1496 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// /////////////////////////////////////////////////////////////
+// Event
+// /////////////////////////////////////////////////////////////
+
+// AddEvents adds the events, existing IDs are skipped and an event in the trash is replaced with the new one.
+func (m *Memory) AddEvents(_ context.Context, events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	updatedAt := types.Time{Time: time.Now()}
+
+	for i := range events {
+		if events[i].ID == "" {
+			events[i].ID = ulid.Make().String()
+		}
+		if events[i].Type == "" {
+			events[i].Type = domain.EventTypeHoliday
+		}
+		events[i].Version = 1
+		events[i].UpdatedAt = updatedAt
+	}
+
+	return m.write(func(d *data) error {
+		history := make([]models.EventHistory, 0, len(events))
+		for _, event := range events {
+			event = storedEvent(event)
+			event.DeletedAt, event.DeletedBy = types.Null[types.Time]{}, types.Null[string]{}
+
+			switch i := d.eventIndex(event.ID); {
+			case i < 0:
+				d.Events = append(d.Events, event)
+			case d.Events[i].DeletedAt.Valid:
+				d.Events[i] = event
+			default:
+				continue
+			}
+
+			history = append(history, newEventHistory(domain.HistoryActionInsert, nil, &event, event.UpdatedBy))
+		}
+
+		d.addHistory(history)
+
+		return nil
+	})
+}
+
+// storedEvent returns the event without the fields resolved for the responses.
+func storedEvent(event models.Event) models.Event {
+	event.ExDates = nil
+	event.Weekend = false
+
+	return event
+}
+
+// eventIndex returns the index of the event also in the trash, -1 for a missing one.
+func (d *data) eventIndex(id string) int {
+	return slices.IndexFunc(d.Events, func(e models.Event) bool { return e.ID == id })
+}
+
+func (m *Memory) GetEventsCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		events, err := d.getEvents(q)
+		n = uint64(len(events))
+
+		return err
+	})
+
+	return n, err
+}
+
+func (m *Memory) GetEvents(_ context.Context, q *query.Query) ([]models.Event, error) {
+	var events []models.Event
+	err := m.read(func(d *data) error {
+		found, err := d.getEvents(q)
+		if err != nil {
+			return err
+		}
+
+		events, err = arrange(q, found)
+
+		return err
+	})
+
+	return events, err
+}
+
+// GetEventsWithFunc calls fn with the events, the events are read before and fn is called without holding the calendar.
+func (m *Memory) GetEventsWithFunc(ctx context.Context, q *query.Query, fn func(models.Event) error) error {
+	events, err := m.GetEvents(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := fn(event); err != nil {
+			if errors.Is(err, domain.ErrStopLoop) {
+				break
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetModified returns the last change and the counts of the events of the query, all relations and the weekends of the entities.
+func (m *Memory) GetModified(_ context.Context, q *query.Query, entities []string) (*models.Modified, error) {
+	modified := &models.Modified{}
+	latest := func(t types.Time) {
+		if t.After(modified.UpdatedAt.Time) {
+			modified.UpdatedAt = t
+		}
+	}
+
+	err := m.read(func(d *data) error {
+		events, err := d.getEvents(q)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			modified.Events++
+			latest(event.UpdatedAt)
+		}
+
+		for _, r := range d.trashRelations(false) {
+			modified.Relations++
+			latest(r.UpdatedAt)
+		}
+
+		// removals change the calendars without an update, the last one in the trash is a change too
+		for _, event := range d.trashEvents(true) {
+			latest(event.DeletedAt.V)
+		}
+
+		for _, r := range d.trashRelations(true) {
+			latest(r.DeletedAt.V)
+		}
+
+		for _, w := range d.Weekends {
+			if slices.Contains(entities, w.Entity) {
+				modified.Weekends++
+				latest(w.UpdatedAt)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return modified, nil
+}
+
+// getEvents returns the events matching the where of the query like getEventsSelect of the repository.
+// With an entity the events are joined with the include relations of the entity and its ancestors,
+// an event excluded on the path from the entity is skipped.
+func (d *data) getEvents(q *query.Query) ([]models.Event, error) {
+	at, err := asOf(q)
+	if err != nil {
+		return nil, err
+	}
+
+	events, relations := d.tables(at)
+	if q == nil || !q.HasAny("entity") {
+		return filter(q, events)
+	}
+
+	includes := make(map[string][]models.Relation)
+	var excludes []models.Relation
+	for _, r := range relations {
+		switch {
+		case r.Type == domain.RelationTypeInclude:
+			includes[r.Entity] = append(includes[r.Entity], r)
+		case r.Type == domain.RelationTypeExclude && !r.Occurrence.Valid:
+			excludes = append(excludes, r)
+		}
+	}
+
+	tree := entityTree(relations)
+
+	var found []models.Event
+	for _, event := range events {
+		values := columns(event)
+
+	branches:
+		for _, b := range tree {
+			if !slices.ContainsFunc(includes[b.entity], func(r models.Relation) bool { return relates(r, event) }) {
+				continue
+			}
+
+			for _, r := range excludes {
+				if slices.Contains(b.path, r.Entity) && relates(r, event) {
+					continue branches
+				}
+			}
+
+			values["entity"] = b.root
+			ok, err := match(q, values)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				found = append(found, event)
+
+				break
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// relates reports the relation is for the event or for its group.
+func relates(r models.Relation, event models.Event) bool {
+	return (r.EventID.Valid && r.EventID.V == event.ID) ||
+		(r.EventGroup.Valid && event.EventGroup.Valid && r.EventGroup.V == event.EventGroup.V)
+}
+
+// branch is a row of the entity tree, entity is the root itself or one of its ancestors.
+type branch struct {
+	root   string
+	entity string
+	path   []string
+}
+
+// entityTree resolves every entity to itself and to all of its ancestors through the parent relations.
+// The path is the chain from root to entity, it stops on cycles.
+func entityTree(relations []models.Relation) []branch {
+	var entities []string
+	parents := make(map[string][]string)
+	for _, r := range relations {
+		if !slices.Contains(entities, r.Entity) {
+			entities = append(entities, r.Entity)
+		}
+
+		if r.Type == domain.RelationTypeParent && r.Parent.Valid {
+			parents[r.Entity] = append(parents[r.Entity], r.Parent.V)
+		}
+	}
+
+	var tree []branch
+	for _, entity := range entities {
+		queue := []branch{{root: entity, entity: entity, path: []string{entity}}}
+		for len(queue) > 0 {
+			b := queue[0]
+			queue = queue[1:]
+			tree = append(tree, b)
+
+			for _, parent := range parents[b.entity] {
+				if slices.Contains(b.path, parent) {
+					continue
+				}
+
+				queue = append(queue, branch{root: b.root, entity: parent, path: append(slices.Clone(b.path), parent)})
+			}
+		}
+	}
+
+	return tree
+}
+
+func (m *Memory) GetEvent(_ context.Context, id string) (*models.Event, error) {
+	var event *models.Event
+	err := m.read(func(d *data) error {
+		if i := d.eventIndex(id); i >= 0 && !d.Events[i].DeletedAt.Valid {
+			found := d.Events[i]
+			event = &found
+		}
+
+		return nil
+	})
+
+	return event, err
+}
+
+// UpdateEvent replaces the event when its stored version is the version of the event, the version is increased.
+func (m *Memory) UpdateEvent(_ context.Context, id string, event *models.Event) error {
+	version := event.Version
+
+	event.UpdatedAt = types.Time{Time: time.Now()}
+	event.Version = version + 1
+	if event.Type == "" {
+		event.Type = domain.EventTypeHoliday
+	}
+
+	if err := m.write(func(d *data) error {
+		i, err := d.lockEvent(id, version)
+		if err != nil {
+			return err
+		}
+
+		before := d.Events[i]
+
+		after := storedEvent(*event)
+		after.ID = before.ID
+		after.DeletedAt, after.DeletedBy = before.DeletedAt, before.DeletedBy
+		d.Events[i] = after
+
+		d.addHistory([]models.EventHistory{
+			newEventHistory(domain.HistoryActionUpdate, &before, &after, event.UpdatedBy),
+		})
+
+		return nil
+	}); err != nil {
+		event.Version = version
+
+		return err
+	}
+
+	return nil
+}
+
+// RemoveEventVersion moves the event to the trash when its stored version is the version.
+func (m *Memory) RemoveEventVersion(_ context.Context, id string, version int64, removedBy string) error {
+	deletedAt := time.Now()
+
+	return m.write(func(d *data) error {
+		i, err := d.lockEvent(id, version)
+		if err != nil {
+			return err
+		}
+
+		before := d.Events[i]
+
+		if err := d.removeRelations(func(r models.Relation) (bool, error) {
+			return r.EventID.Valid && r.EventID.V == id, nil
+		}, deletedAt, removedBy); err != nil {
+			return err
+		}
+
+		d.Events[i].DeletedAt, d.Events[i].DeletedBy = deletedValues(deletedAt, removedBy)
+
+		d.addHistory([]models.EventHistory{
+			newEventHistory(domain.HistoryActionDelete, &before, nil, removedBy),
+		})
+
+		return nil
+	})
+}
+
+// lockEvent returns the index of the stored event when it has the version.
+func (d *data) lockEvent(id string, version int64) (int, error) {
+	i := d.eventIndex(id)
+	if i < 0 || d.Events[i].DeletedAt.Valid {
+		return 0, fmt.Errorf("%w: %s", domain.ErrEventNotFound, id)
+	}
+
+	if d.Events[i].Version != version {
+		return 0, fmt.Errorf("%w: %s has version %d", domain.ErrEventVersion, id, d.Events[i].Version)
+	}
+
+	return i, nil
+}
+
+// RemoveEvent moves the events to the trash, their relations are moved with them and restored together.
+func (m *Memory) RemoveEvent(_ context.Context, removedBy string, id ...string) error {
+	deletedAt := time.Now()
+
+	return m.write(func(d *data) error {
+		if err := d.removeRelations(func(r models.Relation) (bool, error) {
+			return r.EventID.Valid && slices.Contains(id, r.EventID.V), nil
+		}, deletedAt, removedBy); err != nil {
+			return err
+		}
+
+		var history []models.EventHistory
+		for i := range d.Events {
+			if d.Events[i].DeletedAt.Valid || !slices.Contains(id, d.Events[i].ID) {
+				continue
+			}
+
+			d.Events[i].DeletedAt, d.Events[i].DeletedBy = deletedValues(deletedAt, removedBy)
+
+			removed := d.Events[i]
+			history = append(history, newEventHistory(domain.HistoryActionDelete, &removed, nil, removedBy))
+		}
+
+		d.addHistory(history)
+
+		return nil
+	})
+}
+
+// /////////////////////////////////////////////////////////////
+// Trash
+// /////////////////////////////////////////////////////////////
+
+// deletedValues moves the items to the trash.
+func deletedValues(deletedAt time.Time, deletedBy string) (types.Null[types.Time], types.Null[string]) {
+	return types.NewNull(types.Time{Time: deletedAt}), types.NewNull(deletedBy)
+}
+
+// trashEvents returns the events in the trash or the ones out of it.
+func (d *data) trashEvents(trash bool) []models.Event {
+	var events []models.Event
+	for _, event := range d.Events {
+		if event.DeletedAt.Valid == trash {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// trashRelations returns the relations in the trash or the ones out of it.
+func (d *data) trashRelations(trash bool) []models.Relation {
+	var relations []models.Relation
+	for _, r := range d.Relations {
+		if r.DeletedAt.Valid == trash {
+			relations = append(relations, r)
+		}
+	}
+
+	return relations
+}
+
+func (m *Memory) GetTrashEventsCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		var err error
+		n, err = count(q, d.trashEvents(true))
+
+		return err
+	})
+
+	return n, err
+}
+
+// GetTrashEvents returns the events in the trash, the latest removed is first without a sort.
+func (m *Memory) GetTrashEvents(_ context.Context, q *query.Query) ([]models.Event, error) {
+	var events []models.Event
+	err := m.read(func(d *data) error {
+		var err error
+		events, err = find(q, d.trashEvents(true),
+			query.ExpressionSort{Field: "deleted_at", Desc: true},
+			query.ExpressionSort{Field: "id"},
+		)
+
+		return err
+	})
+
+	return events, err
+}
+
+func (m *Memory) GetTrashRelationsCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		var err error
+		n, err = count(q, d.trashRelations(true))
+
+		return err
+	})
+
+	return n, err
+}
+
+// GetTrashRelations returns the relations in the trash, the latest removed is first without a sort.
+func (m *Memory) GetTrashRelations(_ context.Context, q *query.Query) ([]models.Relation, error) {
+	var relations []models.Relation
+	err := m.read(func(d *data) error {
+		var err error
+		relations, err = find(q, d.trashRelations(true),
+			query.ExpressionSort{Field: "deleted_at", Desc: true},
+			query.ExpressionSort{Field: "entity"},
+		)
+
+		return err
+	})
+
+	return relations, err
+}
+
+// RestoreEvent takes the event back from the trash with the relations removed together with it.
+func (m *Memory) RestoreEvent(_ context.Context, id string, restoredBy string) error {
+	return m.write(func(d *data) error {
+		i := d.eventIndex(id)
+		if i < 0 || !d.Events[i].DeletedAt.Valid {
+			return fmt.Errorf("%w: %s is not in the trash", domain.ErrEventNotFound, id)
+		}
+
+		deletedAt := d.Events[i].DeletedAt.V.Time
+
+		d.Events[i].DeletedAt, d.Events[i].DeletedBy = types.Null[types.Time]{}, types.Null[string]{}
+		d.Events[i].UpdatedAt = types.Time{Time: time.Now()}
+		restored := d.Events[i]
+
+		d.restoreRelations(func(r models.Relation) (bool, error) {
+			return r.EventID.Valid && r.EventID.V == id && r.DeletedAt.V.Equal(deletedAt), nil
+		}, restoredBy)
+
+		d.addHistory([]models.EventHistory{
+			newEventHistory(domain.HistoryActionRestore, nil, &restored, restoredBy),
+		})
+
+		return nil
+	})
+}
+
+// RestoreRelations takes the matching relations back from the trash.
+// Relations of an event in the trash are skipped, they are restored with the event.
+func (m *Memory) RestoreRelations(_ context.Context, q *query.Query, restoredBy string) error {
+	return m.write(func(d *data) error {
+		return d.restoreRelations(func(r models.Relation) (bool, error) {
+			if r.EventID.Valid {
+				if i := d.eventIndex(r.EventID.V); i >= 0 && d.Events[i].DeletedAt.Valid {
+					return false, nil
+				}
+			}
+
+			return match(q, columns(r))
+		}, restoredBy)
+	})
+}
+
+// restoreRelations takes the matching relations back from the trash and records them.
+func (d *data) restoreRelations(matches func(models.Relation) (bool, error), restoredBy string) error {
+	var history []relationHistory
+	for i := range d.Relations {
+		if !d.Relations[i].DeletedAt.Valid {
+			continue
+		}
+
+		ok, err := matches(d.Relations[i])
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		d.Relations[i].DeletedAt, d.Relations[i].DeletedBy = types.Null[types.Time]{}, types.Null[string]{}
+		d.Relations[i].UpdatedAt = types.Time{Time: time.Now()}
+
+		restored := d.Relations[i]
+		history = append(history, newRelationHistory(domain.HistoryActionRestore, nil, &restored, restoredBy))
+	}
+
+	d.RelationHistory = append(d.RelationHistory, history...)
+
+	return nil
+}
+
+// PurgeTrash removes the events and relations moved to the trash before the time permanently.
+// It returns the count of the removed events and relations, their history is kept.
+func (m *Memory) PurgeTrash(_ context.Context, before time.Time) (uint64, error) {
+	var purged uint64
+
+	err := m.write(func(d *data) error {
+		purge := func(deletedAt types.Null[types.Time]) bool {
+			return deletedAt.Valid && deletedAt.V.Before(before)
+		}
+
+		n := len(d.Relations)
+		d.Relations = slices.DeleteFunc(d.Relations, func(r models.Relation) bool { return purge(r.DeletedAt) })
+		purged += uint64(n - len(d.Relations))
+
+		var ids []string
+		d.Events = slices.DeleteFunc(d.Events, func(e models.Event) bool {
+			if purge(e.DeletedAt) {
+				ids = append(ids, e.ID)
+
+				return true
+			}
+
+			return false
+		})
+		purged += uint64(len(ids))
+
+		// relations of the removed events are removed with them like the foreign key of the database
+		d.Relations = slices.DeleteFunc(d.Relations, func(r models.Relation) bool {
+			return r.EventID.Valid && slices.Contains(ids, r.EventID.V)
+		})
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// /////////////////////////////////////////////////////////////
+// History
+// /////////////////////////////////////////////////////////////
+
+// newEventHistory records a change of an event, before is nil for inserts and after is nil for deletes.
+func newEventHistory(action string, before, after *models.Event, changedBy string) models.EventHistory {
+	history := models.EventHistory{
+		Action:    action,
+		ChangedAt: types.Time{Time: time.Now()},
+		ChangedBy: changedBy,
+	}
+
+	if before != nil {
+		history.EventID = before.ID
+		history.Version = before.Version
+		history.Before = types.NewJSON(*before)
+	}
+
+	if after != nil {
+		history.EventID = after.ID
+		history.Version = after.Version
+		history.After = types.NewJSON(*after)
+	}
+
+	return history
+}
+
+// addHistory records the changes of the events with increasing IDs.
+func (d *data) addHistory(history []models.EventHistory) {
+	var id int64
+	if len(d.History) > 0 {
+		id = d.History[len(d.History)-1].ID
+	}
+
+	for i := range history {
+		id++
+		history[i].ID = id
+	}
+
+	d.History = append(d.History, history...)
+}
+
+func (m *Memory) GetHistoryCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		var err error
+		n, err = count(q, d.History)
+
+		return err
+	})
+
+	return n, err
+}
+
+// GetHistory returns the recorded changes of the events, the latest change is first without a sort.
+func (m *Memory) GetHistory(_ context.Context, q *query.Query) ([]models.EventHistory, error) {
+	var history []models.EventHistory
+	err := m.read(func(d *data) error {
+		var err error
+		history, err = find(q, d.History, query.ExpressionSort{Field: "id", Desc: true})
+
+		return err
+	})
+
+	return history, err
+}
+
+// newRelationHistory records a change of a relation, before is nil for inserts and after is nil for deletes.
+func newRelationHistory(action string, before, after *models.Relation, changedBy string) relationHistory {
+	history := relationHistory{
+		Action:    action,
+		ChangedAt: types.Time{Time: time.Now()},
+		ChangedBy: changedBy,
+	}
+
+	if before != nil {
+		history.Before = types.NewJSON(*before)
+	}
+
+	if after != nil {
+		history.After = types.NewJSON(*after)
+	}
+
+	return history
+}
+
+// /////////////////////////////////////////////////////////////
+// As of
+// /////////////////////////////////////////////////////////////
+
+// tables returns the events and relations not in the trash, non-zero asOf returns their state at that time from the history.
+func (d *data) tables(asOf time.Time) ([]models.Event, []models.Relation) {
+	if asOf.IsZero() {
+		return d.trashEvents(false), d.trashRelations(false)
+	}
+
+	return d.eventsAsOf(asOf), d.relationsAsOf(asOf)
+}
+
+// eventsAsOf returns the events with their latest change at or before the time, removed events are skipped.
+func (d *data) eventsAsOf(asOf time.Time) []models.Event {
+	var ids []string
+	latest := make(map[string]models.EventHistory)
+	for _, h := range d.History {
+		if h.ChangedAt.After(asOf) {
+			continue
+		}
+
+		if _, ok := latest[h.EventID]; !ok {
+			ids = append(ids, h.EventID)
+		}
+
+		latest[h.EventID] = h
+	}
+
+	var events []models.Event
+	for _, id := range ids {
+		if h := latest[id]; h.Action != domain.HistoryActionDelete && h.After.Valid {
+			events = append(events, h.After.V)
+		}
+	}
+
+	return events
+}
+
+// relationKey is the identity of a relation, relations have no ID.
+type relationKey struct {
+	Entity     string
+	Type       string
+	EventID    types.Null[string]
+	EventGroup types.Null[string]
+	Parent     types.Null[string]
+	Occurrence string
+}
+
+func newRelationKey(r models.Relation) relationKey {
+	key := relationKey{
+		Entity:     r.Entity,
+		Type:       r.Type,
+		EventID:    r.EventID,
+		EventGroup: r.EventGroup,
+		Parent:     r.Parent,
+	}
+
+	if r.Occurrence.Valid {
+		key.Occurrence = r.Occurrence.V.Format(time.DateOnly)
+	}
+
+	return key
+}
+
+// relationsAsOf replays the relation changes at or before the time.
+// A relation exists when it is added more than removed.
+func (d *data) relationsAsOf(asOf time.Time) []models.Relation {
+	type replayed struct {
+		relation models.Relation
+		n        int
+	}
+
+	var keys []relationKey
+	replay := make(map[relationKey]*replayed)
+	add := func(r models.Relation, n int) {
+		key := newRelationKey(r)
+
+		current, ok := replay[key]
+		if !ok {
+			keys = append(keys, key)
+			current = &replayed{relation: models.Relation{
+				Entity:     r.Entity,
+				Type:       r.Type,
+				EventID:    r.EventID,
+				EventGroup: r.EventGroup,
+				Parent:     r.Parent,
+				Occurrence: r.Occurrence,
+				UpdatedAt:  r.UpdatedAt,
+				UpdatedBy:  r.UpdatedBy,
+			}}
+			replay[key] = current
+		}
+
+		current.n += n
+		if r.UpdatedAt.After(current.relation.UpdatedAt.Time) {
+			current.relation.UpdatedAt = r.UpdatedAt
+		}
+		current.relation.UpdatedBy = max(current.relation.UpdatedBy, r.UpdatedBy)
+	}
+
+	for _, h := range d.RelationHistory {
+		if h.ChangedAt.After(asOf) {
+			continue
+		}
+
+		if h.After.Valid {
+			add(h.After.V, 1)
+		}
+
+		if h.Before.Valid {
+			add(h.Before.V, -1)
+		}
+	}
+
+	var relations []models.Relation
+	for _, key := range keys {
+		if r := replay[key]; r.n > 0 {
+			relations = append(relations, r.relation)
+		}
+	}
+
+	return relations
+}
+
+// asOf returns the as_of time of the query, the current state is the zero time.
+func asOf(q *query.Query) (time.Time, error) {
+	var t types.Time
+	if q == nil {
+		return t.Time, nil
+	}
+
+	if v := q.GetValue("as_of"); v != "" {
+		if err := t.Parse(v); err != nil {
+			return time.Time{}, fmt.Errorf("%w %q: %w", domain.ErrAsOf, v, err)
+		}
+	}
+
+	return t.Time, nil
+}
+
+// /////////////////////////////////////////////////////////////
+// Relation
+// /////////////////////////////////////////////////////////////
+
+// AddRelations adds the relations, a relation with the same fields is skipped and one in the trash is replaced.
+func (m *Memory) AddRelations(_ context.Context, relations []models.Relation) error {
+	updatedAt := types.Time{Time: time.Now()}
+
+	for i := range relations {
+		relations[i].UpdatedAt = updatedAt
+	}
+
+	return m.write(func(d *data) error {
+		history := make([]relationHistory, 0, len(relations))
+		for _, r := range relations {
+			r, err := d.storedRelation(r)
+			if err != nil {
+				return err
+			}
+
+			r.DeletedAt, r.DeletedBy = types.Null[types.Time]{}, types.Null[string]{}
+
+			// a relation in the trash is replaced with the new one like the events
+			d.Relations = slices.DeleteFunc(d.Relations, func(stored models.Relation) bool {
+				return stored.DeletedAt.Valid && newRelationKey(stored) == newRelationKey(r)
+			})
+
+			if d.conflicts(r) {
+				continue
+			}
+
+			d.Relations = append(d.Relations, r)
+			history = append(history, newRelationHistory(domain.HistoryActionInsert, nil, &r, r.UpdatedBy))
+		}
+
+		d.RelationHistory = append(d.RelationHistory, history...)
+
+		return nil
+	})
+}
+
+// storedRelation checks the event of the relation exists and returns the relation with the date of its occurrence.
+func (d *data) storedRelation(r models.Relation) (models.Relation, error) {
+	if r.EventID.Valid && d.eventIndex(r.EventID.V) < 0 {
+		return r, fmt.Errorf("%w: %s", domain.ErrEventNotFound, r.EventID.V)
+	}
+
+	if r.Occurrence.Valid {
+		r.Occurrence.V = date(r.Occurrence.V)
+	}
+
+	return r, nil
+}
+
+// date returns the day of the time like a date column.
+func date(t types.Time) types.Time {
+	u := t.UTC()
+
+	return types.Time{Time: time.Date(u.Year(), u.Month(), u.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// conflicts reports a stored relation has the same fields like the unique index, empty fields are equal.
+func (d *data) conflicts(r models.Relation) bool {
+	return slices.ContainsFunc(d.Relations, func(stored models.Relation) bool {
+		return newRelationKey(stored) == newRelationKey(r)
+	})
+}
+
+func (m *Memory) RemoveRelation(_ context.Context, q *query.Query, removedBy string) error {
+	return m.write(func(d *data) error {
+		return d.removeRelations(func(r models.Relation) (bool, error) {
+			return match(q, columns(r))
+		}, time.Now(), removedBy)
+	})
+}
+
+// RemoveRelations moves the relations matched with all of their fields to the trash, missing ones are skipped.
+func (m *Memory) RemoveRelations(_ context.Context, relations []models.Relation, removedBy string) error {
+	deletedAt := time.Now()
+
+	return m.write(func(d *data) error {
+		for _, key := range relations {
+			if err := d.removeRelations(func(r models.Relation) (bool, error) {
+				return sameRelation(r, key), nil
+			}, deletedAt, removedBy); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// sameRelation matches the relation with all of the fields of the key, null ones match null.
+func sameRelation(r, key models.Relation) bool {
+	return newRelationKey(r) == newRelationKey(key)
+}
+
+// removeRelations moves the matching relations to the trash and records them.
+func (d *data) removeRelations(matches func(models.Relation) (bool, error), deletedAt time.Time, removedBy string) error {
+	var history []relationHistory
+	for i := range d.Relations {
+		if d.Relations[i].DeletedAt.Valid {
+			continue
+		}
+
+		ok, err := matches(d.Relations[i])
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		d.Relations[i].DeletedAt, d.Relations[i].DeletedBy = deletedValues(deletedAt, removedBy)
+
+		removed := d.Relations[i]
+		history = append(history, newRelationHistory(domain.HistoryActionDelete, &removed, nil, removedBy))
+	}
+
+	d.RelationHistory = append(d.RelationHistory, history...)
+
+	return nil
+}
+
+// UpdateRelations replaces the relations in one transaction, a missing relation fails all of them.
+func (m *Memory) UpdateRelations(_ context.Context, updates []models.RelationUpdate) error {
+	updatedAt := types.Time{Time: time.Now()}
+
+	return m.write(func(d *data) error {
+		var history []relationHistory
+		for i := range updates {
+			updates[i].To.UpdatedAt = updatedAt
+
+			to, err := d.storedRelation(updates[i].To)
+			if err != nil {
+				return err
+			}
+
+			found := false
+			for j := range d.Relations {
+				if d.Relations[j].DeletedAt.Valid || !sameRelation(d.Relations[j], updates[i].From) {
+					continue
+				}
+
+				found = true
+
+				before := d.Relations[j]
+				after := to
+				after.DeletedAt, after.DeletedBy = before.DeletedAt, before.DeletedBy
+				d.Relations[j] = after
+
+				history = append(history, newRelationHistory(domain.HistoryActionUpdate, &before, &after, updates[i].To.UpdatedBy))
+			}
+
+			if !found {
+				return fmt.Errorf("%w: %s %s", domain.ErrRelationNotFound, updates[i].From.Entity, updates[i].From.Type)
+			}
+		}
+
+		d.RelationHistory = append(d.RelationHistory, history...)
+
+		return nil
+	})
+}
+
+// GetExclusions returns the occurrence exclusions of the entities, also the ones defined on their ancestors.
+// Non-zero asOf returns the exclusions of that time.
+func (m *Memory) GetExclusions(_ context.Context, entities []string, asOf time.Time) ([]models.Relation, error) {
+	var exclusions []models.Relation
+	err := m.read(func(d *data) error {
+		_, relations := d.tables(asOf)
+
+		added := make(map[int]bool)
+		for _, b := range entityTree(relations) {
+			if !slices.Contains(entities, b.root) {
+				continue
+			}
+
+			for i, r := range relations {
+				if added[i] || r.Entity != b.entity || r.Type != domain.RelationTypeExclude || !r.Occurrence.Valid {
+					continue
+				}
+
+				added[i] = true
+				exclusions = append(exclusions, r)
+			}
+		}
+
+		return nil
+	})
+
+	return exclusions, err
+}
+
+func (m *Memory) GetRelationsCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		var err error
+		n, err = count(q, d.trashRelations(false))
+
+		return err
+	})
+
+	return n, err
+}
+
+func (m *Memory) GetRelations(_ context.Context, q *query.Query) ([]models.Relation, error) {
+	var relations []models.Relation
+	err := m.read(func(d *data) error {
+		var err error
+		relations, err = find(q, d.trashRelations(false))
+
+		return err
+	})
+
+	return relations, err
+}
+
+// /////////////////////////////////////////////////////////////
+// Joint
+// /////////////////////////////////////////////////////////////
+
+func (m *Memory) AddJoints(_ context.Context, joints []models.Joint) error {
+	updatedAt := types.Time{Time: time.Now()}
+
+	for i := range joints {
+		joints[i].UpdatedAt = updatedAt
+	}
+
+	return m.write(func(d *data) error {
+		for _, joint := range joints {
+			if d.jointIndex(joint.Name) < 0 {
+				d.Joints = append(d.Joints, joint)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (d *data) jointIndex(name string) int {
+	return slices.IndexFunc(d.Joints, func(j models.Joint) bool { return j.Name == name })
+}
+
+func (m *Memory) GetJointsCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		var err error
+		n, err = count(q, d.Joints)
+
+		return err
+	})
+
+	return n, err
+}
+
+func (m *Memory) GetJoints(_ context.Context, q *query.Query) ([]models.Joint, error) {
+	var joints []models.Joint
+	err := m.read(func(d *data) error {
+		var err error
+		joints, err = find(q, d.Joints)
+
+		return err
+	})
+
+	return joints, err
+}
+
+func (m *Memory) GetJoint(_ context.Context, name string) (*models.Joint, error) {
+	var joint *models.Joint
+	err := m.read(func(d *data) error {
+		if i := d.jointIndex(name); i >= 0 {
+			found := d.Joints[i]
+			joint = &found
+		}
+
+		return nil
+	})
+
+	return joint, err
+}
+
+func (m *Memory) UpdateJoint(_ context.Context, name string, joint *models.Joint) error {
+	joint.UpdatedAt = types.Time{Time: time.Now()}
+
+	return m.write(func(d *data) error {
+		if i := d.jointIndex(name); i >= 0 {
+			updated := *joint
+			updated.Name = name
+			d.Joints[i] = updated
+		}
+
+		return nil
+	})
+}
+
+func (m *Memory) RemoveJoint(_ context.Context, name ...string) error {
+	return m.write(func(d *data) error {
+		d.Joints = slices.DeleteFunc(d.Joints, func(j models.Joint) bool { return slices.Contains(name, j.Name) })
+
+		return nil
+	})
+}
+
+// /////////////////////////////////////////////////////////////
+// Business Hours
+// /////////////////////////////////////////////////////////////
+
+// SetHours adds or replaces the business hours of the entity.
+func (m *Memory) SetHours(_ context.Context, hours *models.BusinessHours) error {
+	hours.UpdatedAt = types.Time{Time: time.Now()}
+
+	return m.write(func(d *data) error {
+		if i := d.hoursIndex(hours.Entity); i >= 0 {
+			d.Hours[i] = *hours
+		} else {
+			d.Hours = append(d.Hours, *hours)
+		}
+
+		return nil
+	})
+}
+
+func (d *data) hoursIndex(entity string) int {
+	return slices.IndexFunc(d.Hours, func(h models.BusinessHours) bool { return h.Entity == entity })
+}
+
+func (m *Memory) GetHoursCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		var err error
+		n, err = count(q, d.Hours)
+
+		return err
+	})
+
+	return n, err
+}
+
+func (m *Memory) GetHours(_ context.Context, q *query.Query) ([]models.BusinessHours, error) {
+	var hours []models.BusinessHours
+	err := m.read(func(d *data) error {
+		var err error
+		hours, err = find(q, d.Hours)
+
+		return err
+	})
+
+	return hours, err
+}
+
+func (m *Memory) GetEntityHours(_ context.Context, entity string) (*models.BusinessHours, error) {
+	var hours *models.BusinessHours
+	err := m.read(func(d *data) error {
+		if i := d.hoursIndex(entity); i >= 0 {
+			found := d.Hours[i]
+			hours = &found
+		}
+
+		return nil
+	})
+
+	return hours, err
+}
+
+func (m *Memory) RemoveHours(_ context.Context, entity ...string) error {
+	return m.write(func(d *data) error {
+		d.Hours = slices.DeleteFunc(d.Hours, func(h models.BusinessHours) bool { return slices.Contains(entity, h.Entity) })
+
+		return nil
+	})
+}
+
+// /////////////////////////////////////////////////////////////
+// Weekend
+// /////////////////////////////////////////////////////////////
+
+// AddWeekends adds the weekend patterns, existing effective days of the entities are replaced.
+func (m *Memory) AddWeekends(_ context.Context, weekends []models.Weekend) error {
+	updatedAt := types.Time{Time: time.Now()}
+
+	for i := range weekends {
+		weekends[i].UpdatedAt = updatedAt
+	}
+
+	return m.write(func(d *data) error {
+		for _, weekend := range weekends {
+			weekend.EffectiveFrom = date(weekend.EffectiveFrom)
+
+			i := slices.IndexFunc(d.Weekends, func(w models.Weekend) bool {
+				return w.Entity == weekend.Entity && w.EffectiveFrom.Equal(weekend.EffectiveFrom.Time)
+			})
+			if i >= 0 {
+				d.Weekends[i] = weekend
+			} else {
+				d.Weekends = append(d.Weekends, weekend)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m *Memory) RemoveWeekends(_ context.Context, q *query.Query) error {
+	return m.write(func(d *data) error {
+		weekends := d.Weekends[:0]
+		for _, weekend := range d.Weekends {
+			ok, err := match(q, columns(weekend))
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				weekends = append(weekends, weekend)
+			}
+		}
+
+		d.Weekends = weekends
+
+		return nil
+	})
+}
+
+func (m *Memory) GetWeekendsCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		var err error
+		n, err = count(q, d.Weekends)
+
+		return err
+	})
+
+	return n, err
+}
+
+func (m *Memory) GetWeekends(_ context.Context, q *query.Query) ([]models.Weekend, error) {
+	var weekends []models.Weekend
+	err := m.read(func(d *data) error {
+		var err error
+		weekends, err = find(q, d.Weekends)
+
+		return err
+	})
+
+	return weekends, err
+}
+
+// GetEntityWeekends returns the weekend history of the entity ordered by the effective day.
+func (m *Memory) GetEntityWeekends(_ context.Context, entity string) ([]models.Weekend, error) {
+	var weekends []models.Weekend
+	err := m.read(func(d *data) error {
+		for _, weekend := range d.Weekends {
+			if weekend.Entity == entity {
+				weekends = append(weekends, weekend)
+			}
+		}
+
+		return sortItems(weekends, []query.ExpressionSort{{Field: "effective_from"}})
+	})
+
+	return weekends, err
+}
+
+// /////////////////////////////////////////////////////////////
+// Change-set
+// /////////////////////////////////////////////////////////////
+
+func (m *Memory) AddChangeSet(_ context.Context, changeSet *models.ChangeSet) error {
+	if changeSet.ID == "" {
+		changeSet.ID = ulid.Make().String()
+	}
+
+	changeSet.CreatedAt = types.Time{Time: time.Now()}
+	changeSet.UpdatedAt = changeSet.CreatedAt
+	changeSet.UpdatedBy = changeSet.CreatedBy
+	changeSet.Changes.Valid = true
+
+	return m.write(func(d *data) error {
+		if d.changeSetIndex(changeSet.ID) >= 0 {
+			return fmt.Errorf("change-set %s exists", changeSet.ID)
+		}
+
+		d.ChangeSets = append(d.ChangeSets, *changeSet)
+
+		return nil
+	})
+}
+
+func (d *data) changeSetIndex(id string) int {
+	return slices.IndexFunc(d.ChangeSets, func(c models.ChangeSet) bool { return c.ID == id })
+}
+
+func (m *Memory) GetChangeSetsCount(_ context.Context, q *query.Query) (uint64, error) {
+	var n uint64
+	err := m.read(func(d *data) error {
+		var err error
+		n, err = count(q, d.ChangeSets)
+
+		return err
+	})
+
+	return n, err
+}
+
+// GetChangeSets returns the change-sets, the latest created is first without a sort.
+func (m *Memory) GetChangeSets(_ context.Context, q *query.Query) ([]models.ChangeSet, error) {
+	var changeSets []models.ChangeSet
+	err := m.read(func(d *data) error {
+		var err error
+		changeSets, err = find(q, d.ChangeSets,
+			query.ExpressionSort{Field: "created_at", Desc: true},
+			query.ExpressionSort{Field: "id", Desc: true},
+		)
+
+		return err
+	})
+
+	return changeSets, err
+}
+
+// GetDueChangeSets returns the approved change-sets with an effective time at or before the time, the earliest is first.
+func (m *Memory) GetDueChangeSets(_ context.Context, at time.Time) ([]models.ChangeSet, error) {
+	var changeSets []models.ChangeSet
+	err := m.read(func(d *data) error {
+		for _, changeSet := range d.ChangeSets {
+			if changeSet.Status == domain.ChangeSetStatusApproved && changeSet.EffectiveAt.Valid && !changeSet.EffectiveAt.V.After(at) {
+				changeSets = append(changeSets, changeSet)
+			}
+		}
+
+		return sortItems(changeSets, []query.ExpressionSort{{Field: "effective_at"}, {Field: "id"}})
+	})
+
+	return changeSets, err
+}
+
+func (m *Memory) GetChangeSet(_ context.Context, id string) (*models.ChangeSet, error) {
+	var changeSet *models.ChangeSet
+	err := m.read(func(d *data) error {
+		if i := d.changeSetIndex(id); i >= 0 {
+			found := d.ChangeSets[i]
+			changeSet = &found
+		}
+
+		return nil
+	})
+
+	return changeSet, err
+}
+
+// UpdateChangeSet replaces the change-set when its stored status is the status.
+// Concurrent approvals or applications change it only once, the others get ErrChangeSetStatus.
+func (m *Memory) UpdateChangeSet(_ context.Context, id string, status string, changeSet *models.ChangeSet) error {
+	changeSet.UpdatedAt = types.Time{Time: time.Now()}
+	changeSet.Changes.Valid = true
+
+	return m.write(func(d *data) error {
+		i, err := d.changeSetStatus(id, func(stored string) bool { return stored == status })
+		if err != nil {
+			return err
+		}
+
+		updated := *changeSet
+		updated.ID = id
+		updated.CreatedAt = d.ChangeSets[i].CreatedAt
+		updated.CreatedBy = d.ChangeSets[i].CreatedBy
+		d.ChangeSets[i] = updated
+
+		return nil
+	})
+}
+
+// RemoveChangeSet removes the change-set when it is not applied yet.
+func (m *Memory) RemoveChangeSet(_ context.Context, id string) error {
+	return m.write(func(d *data) error {
+		i, err := d.changeSetStatus(id, func(stored string) bool { return stored != domain.ChangeSetStatusApplied })
+		if err != nil {
+			return err
+		}
+
+		d.ChangeSets = slices.Delete(d.ChangeSets, i, i+1)
+
+		return nil
+	})
+}
+
+// changeSetStatus returns the index of the change-set when its status is accepted, otherwise the reason.
+func (d *data) changeSetStatus(id string, accept func(status string) bool) (int, error) {
+	i := d.changeSetIndex(id)
+	if i < 0 {
+		return 0, fmt.Errorf("%w: %s", domain.ErrChangeSetNotFound, id)
+	}
+
+	if !accept(d.ChangeSets[i].Status) {
+		return 0, fmt.Errorf("%w: %s is %s", domain.ErrChangeSetStatus, id, d.ChangeSets[i].Status)
+	}
+
+	return i, nil
+}
+
+// ApplyChanges applies the changes in one transaction, a failing change rolls back all of them.
+// Removing a missing event or relation is skipped like the removals of the API.
+func (m *Memory) ApplyChanges(ctx context.Context, changes models.Changes, appliedBy string) error {
+	return m.write(func(d *data) error {
+		bound := &Memory{mu: m.mu, data: d, tx: true}
+
+		if err := bound.RemoveRelations(ctx, changes.RemoveRelations, appliedBy); err != nil {
+			return err
+		}
+
+		if len(changes.RemoveEvents) > 0 {
+			if err := bound.RemoveEvent(ctx, appliedBy, changes.RemoveEvents...); err != nil {
+				return err
+			}
+		}
+
+		if len(changes.AddEvents) > 0 {
+			events := slices.Clone(changes.AddEvents)
+			for i := range events {
+				events[i].UpdatedBy = appliedBy
+			}
+
+			if err := bound.AddEvents(ctx, events); err != nil {
+				return err
+			}
+		}
+
+		for _, event := range changes.UpdateEvents {
+			if event.Version == 0 {
+				stored, err := bound.GetEvent(ctx, event.ID)
+				if err != nil {
+					return err
+				}
+
+				if stored == nil {
+					return fmt.Errorf("%w: %s", domain.ErrEventNotFound, event.ID)
+				}
+
+				event.Version = stored.Version
+			}
+
+			event.UpdatedBy = appliedBy
+			if err := bound.UpdateEvent(ctx, event.ID, &event); err != nil {
+				return err
+			}
+		}
+
+		if len(changes.AddRelations) > 0 {
+			relations := slices.Clone(changes.AddRelations)
+			for i := range relations {
+				relations[i].UpdatedBy = appliedBy
+			}
+
+			if err := bound.AddRelations(ctx, relations); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/worldline-go/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/worldline-go/calendar/internal/core/port"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// DBType is the db_type of the in-memory calendar, db_datasource is its optional file.
+const DBType = "memory"
+
+// Memory keeps the calendar in memory without a database, it is the same port as the repository.
+// With a file the calendar is loaded from it and every change is written back, the file is YAML or JSON by its extension.
+type Memory struct {
+	// mu serializes the changes, a transaction holds it until it ends.
+	mu   *sync.RWMutex
+	data *data
+	path string
+
+	// tx is set when the memory is bound to a transaction, the changes are kept when the transaction ends without an error.
+	tx bool
+}
+
+var _ port.CalendarPort = (*Memory)(nil)
+
+// data is the stored calendar, it is also the content of the file.
+type data struct {
+	Events          []models.Event         `json:"events"`
+	Relations       []models.Relation      `json:"relations"`
+	Joints          []models.Joint         `json:"joints"`
+	Hours           []models.BusinessHours `json:"hours"`
+	Weekends        []models.Weekend       `json:"weekends"`
+	ChangeSets      []models.ChangeSet     `json:"change_sets"`
+	History         []models.EventHistory  `json:"history"`
+	RelationHistory []relationHistory      `json:"relation_history"`
+}
+
+// relationHistory is a recorded change of a relation, relations are replayed with their snapshots for as_of.
+type relationHistory struct {
+	Action string `json:"action"`
+
+	Before types.JSON[models.Relation] `json:"before"`
+	After  types.JSON[models.Relation] `json:"after"`
+
+	ChangedAt types.Time `json:"changed_at"`
+	ChangedBy string     `json:"changed_by"`
+}
+
+// New returns the in-memory calendar, an empty path keeps it only in memory.
+// An existing file is loaded, otherwise it is created with the first change.
+func New(path string) (*Memory, error) {
+	m := &Memory{
+		mu:   &sync.RWMutex{},
+		data: &data{},
+		path: path,
+	}
+
+	if path == "" {
+		return m, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+
+		return nil, fmt.Errorf("read calendar file: %w", err)
+	}
+
+	if isYAML(path) {
+		content, err = yaml.YAMLToJSON(content)
+		if err != nil {
+			return nil, fmt.Errorf("parse calendar file %s: %w", path, err)
+		}
+	}
+
+	if err := json.Unmarshal(content, m.data); err != nil {
+		return nil, fmt.Errorf("parse calendar file %s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// save writes the calendar to the file, the file is replaced at once.
+func (m *Memory) save(d *data) error {
+	if m.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if isYAML(m.path) {
+		content, err = yaml.JSONToYAML(content)
+		if err != nil {
+			return err
+		}
+	}
+
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return fmt.Errorf("write calendar file: %w", err)
+	}
+
+	if err := os.Rename(tmp, m.path); err != nil {
+		return fmt.Errorf("write calendar file: %w", err)
+	}
+
+	return nil
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// clone copies the lists of the calendar, the items are replaced and never changed in place.
+func (d *data) clone() *data {
+	return &data{
+		Events:          slices.Clone(d.Events),
+		Relations:       slices.Clone(d.Relations),
+		Joints:          slices.Clone(d.Joints),
+		Hours:           slices.Clone(d.Hours),
+		Weekends:        slices.Clone(d.Weekends),
+		ChangeSets:      slices.Clone(d.ChangeSets),
+		History:         slices.Clone(d.History),
+		RelationHistory: slices.Clone(d.RelationHistory),
+	}
+}
+
+// read runs fn with the calendar, it waits for the running changes.
+func (m *Memory) read(fn func(d *data) error) error {
+	if !m.tx {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+	}
+
+	return fn(m.data)
+}
+
+// write runs fn on a copy of the calendar and keeps the copy when fn succeeds, a failing fn changes nothing.
+func (m *Memory) write(fn func(d *data) error) error {
+	if !m.tx {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+	}
+
+	d := m.data.clone()
+	if err := fn(d); err != nil {
+		return err
+	}
+
+	if !m.tx {
+		if err := m.save(d); err != nil {
+			return err
+		}
+	}
+
+	*m.data = *d
+
+	return nil
+}
+
+// Transaction runs fn with the memory bound to one transaction.
+// Changes of fn are kept together, an error of fn rolls back all of them.
+func (m *Memory) Transaction(_ context.Context, fn func(port.CalendarPort) error) error {
+	if m.tx {
+		return fn(m)
+	}
+
+	return m.write(func(d *data) error {
+		return fn(&Memory{mu: m.mu, data: d, tx: true})
+	})
+}
+
+// Preview runs fn like Transaction and drops all of its changes, fn reads the calendar with the changes.
+func (m *Memory) Preview(_ context.Context, fn func(port.CalendarPort) error) error {
+	if m.tx {
+		return errors.New("preview is not supported in a transaction")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return fn(&Memory{mu: m.mu, data: m.data.clone(), tx: true})
+}
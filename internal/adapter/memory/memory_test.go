@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/core/port"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func testEvents(t *testing.T, m *Memory) {
+	t.Helper()
+
+	day := func(d int) types.Time {
+		return types.Time{Time: time.Date(2026, time.January, d, 0, 0, 0, 0, time.UTC)}
+	}
+
+	events := []models.Event{
+		{ID: "new-year", Name: "New Year", DateFrom: day(1), DateTo: day(2), AllDay: true},
+		{ID: "epiphany", Name: "Epiphany", DateFrom: day(6), DateTo: day(7), EventGroup: types.NewNull("church")},
+		{ID: "meeting", Name: "Board meeting", DateFrom: day(15), DateTo: day(15), Type: "event"},
+	}
+
+	if err := m.AddEvents(t.Context(), events); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	m, err := New("")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	testEvents(t, m)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "all", query: "sort=id", want: []string{"epiphany", "meeting", "new-year"}},
+		{name: "eq", query: "name=Epiphany", want: []string{"epiphany"}},
+		{name: "in", query: "id=meeting,new-year&sort=-id", want: []string{"new-year", "meeting"}},
+		{name: "or", query: "id=meeting|name=Epiphany&sort=id", want: []string{"epiphany", "meeting"}},
+		{name: "time", query: "date_from[gte]=2026-01-06&sort=date_from", want: []string{"epiphany", "meeting"}},
+		{name: "bool", query: "all_day=true", want: []string{"new-year"}},
+		{name: "ilike", query: "name[ilike]=%25YEAR", want: []string{"new-year"}},
+		{name: "null", query: "event_group[is]=&sort=id", want: []string{"meeting", "new-year"}},
+		{name: "null sorted last", query: "sort=event_group,id", want: []string{"epiphany", "meeting", "new-year"}},
+		{name: "page", query: "sort=date_from&offset=1&limit=1", want: []string{"epiphany"}},
+		{name: "unknown column", query: "color=red", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := query.Parse(tt.query)
+			if err != nil {
+				t.Fatalf("query.Parse() error = %v", err)
+			}
+
+			events, err := m.GetEvents(t.Context(), q)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var ids []string
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("GetEvents() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	m, err := New("")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	testEvents(t, m)
+
+	errRollback := errors.New("rollback")
+	if err := m.Transaction(t.Context(), func(tx port.CalendarPort) error {
+		if err := tx.RemoveEvent(t.Context(), "test", "new-year"); err != nil {
+			return err
+		}
+
+		return errRollback
+	}); !errors.Is(err, errRollback) {
+		t.Fatalf("Transaction() error = %v, want %v", err, errRollback)
+	}
+
+	if event, _ := m.GetEvent(t.Context(), "new-year"); event == nil {
+		t.Error("Transaction() kept the removed event of the rolled back transaction")
+	}
+
+	if err := m.Preview(t.Context(), func(tx port.CalendarPort) error {
+		if err := tx.RemoveEvent(t.Context(), "test", "new-year"); err != nil {
+			return err
+		}
+
+		event, err := tx.GetEvent(t.Context(), "new-year")
+		if event != nil {
+			t.Error("Preview() does not read its own changes")
+		}
+
+		return err
+	}); err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+
+	if event, _ := m.GetEvent(t.Context(), "new-year"); event == nil {
+		t.Error("Preview() kept the removed event")
+	}
+}
+
+func TestFile(t *testing.T) {
+	for _, name := range []string{"calendar.json", "calendar.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+
+			m, err := New(path)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			testEvents(t, m)
+
+			if err := m.AddRelations(t.Context(), []models.Relation{
+				{Entity: "TR", Type: "include", EventID: types.NewNull("new-year")},
+				{Entity: "TR-IST", Type: "parent", Parent: types.NewNull("TR")},
+			}); err != nil {
+				t.Fatalf("AddRelations() error = %v", err)
+			}
+
+			loaded, err := New(path)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			q, err := query.Parse("entity=TR-IST")
+			if err != nil {
+				t.Fatalf("query.Parse() error = %v", err)
+			}
+
+			events, err := loaded.GetEvents(t.Context(), q)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+
+			if len(events) != 1 || events[0].ID != "new-year" || !events[0].DateFrom.Equal(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("GetEvents() = %v, want the new-year event of the parent", events)
+			}
+
+			history, err := loaded.GetHistoryCount(t.Context(), &query.Query{})
+			if err != nil {
+				t.Fatalf("GetHistoryCount() error = %v", err)
+			}
+
+			if history != 3 {
+				t.Errorf("GetHistoryCount() = %d, want 3", history)
+			}
+		})
+	}
+}
@@ -0,0 +1,431 @@
+package memory
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+)
+
+// find returns the items matching the query, sorted and paged like adaptergoqu.Select of the repository.
+// Without a sort in the query the items are sorted with the defaultSort.
+func find[T any](q *query.Query, items []T, defaultSort ...query.ExpressionSort) ([]T, error) {
+	found, err := filter(q, items)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrange(q, found, defaultSort...)
+}
+
+// arrange sorts and pages the items with the query, they are already filtered.
+func arrange[T any](q *query.Query, items []T, defaultSort ...query.ExpressionSort) ([]T, error) {
+	if q != nil && len(q.Sort) > 0 {
+		defaultSort = q.Sort
+	}
+
+	if err := sortItems(items, defaultSort); err != nil {
+		return nil, err
+	}
+
+	return page(q, items), nil
+}
+
+// count returns the count of the items matching the query.
+func count[T any](q *query.Query, items []T) (uint64, error) {
+	found, err := filter(q, items)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(found)), nil
+}
+
+// filter returns the items matching the where of the query.
+func filter[T any](q *query.Query, items []T) ([]T, error) {
+	var found []T
+	for _, item := range items {
+		ok, err := match(q, columns(item))
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			found = append(found, item)
+		}
+	}
+
+	return found, nil
+}
+
+// page returns the items of the offset and the limit of the query, a zero limit returns all of them.
+func page[T any](q *query.Query, items []T) []T {
+	if q == nil {
+		return items
+	}
+
+	offset := q.GetOffset()
+	if offset >= uint64(len(items)) {
+		return nil
+	}
+
+	items = items[offset:]
+
+	if limit := q.GetLimit(); limit > 0 && limit < uint64(len(items)) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// sortItems sorts the items by the columns, nulls are last in ascending order like postgres.
+func sortItems[T any](items []T, sorts []query.ExpressionSort) error {
+	if len(sorts) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]any, len(items))
+	for i := range items {
+		rows[i] = columns(items[i])
+	}
+
+	for _, s := range sorts {
+		if len(rows) > 0 {
+			if _, ok := rows[0][column(s.Field)]; !ok {
+				return fmt.Errorf("column %q does not exist", s.Field)
+			}
+		}
+	}
+
+	index := make([]int, len(items))
+	for i := range index {
+		index[i] = i
+	}
+
+	slices.SortStableFunc(index, func(a, b int) int {
+		for _, s := range sorts {
+			c := order(rows[a][column(s.Field)], rows[b][column(s.Field)])
+			if s.Desc {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	sorted := make([]T, len(items))
+	for i, j := range index {
+		sorted[i] = items[j]
+	}
+
+	copy(items, sorted)
+
+	return nil
+}
+
+// order compares two values of a column, null is greater than any value.
+func order(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	c, err := compare(a, b)
+	if err != nil {
+		return 0
+	}
+
+	return c
+}
+
+// columns returns the values of the db columns of the struct, null values are nil.
+func columns(v any) map[string]any {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+
+	values := make(map[string]any, rt.NumField())
+	for i := range rt.NumField() {
+		name, _, _ := strings.Cut(rt.Field(i).Tag.Get("db"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		values[name] = value(rv.Field(i).Interface())
+	}
+
+	return values
+}
+
+// value returns the plain value of the field like it is stored in the database.
+func value(v any) any {
+	for {
+		switch val := v.(type) {
+		case nil, string, bool, int64, float64, time.Time:
+			return val
+		case int:
+			return int64(val)
+		case []byte:
+			return string(val)
+		case driver.Valuer:
+			next, err := val.Value()
+			if err != nil {
+				return nil
+			}
+
+			v = next
+		default:
+			return val
+		}
+	}
+}
+
+// column returns the column of a field qualified with a table.
+func column(field string) string {
+	return field[strings.LastIndex(field, ".")+1:]
+}
+
+// match reports the columns match the where of the query, the expressions are combined with AND.
+func match(q *query.Query, values map[string]any) (bool, error) {
+	if q == nil {
+		return true, nil
+	}
+
+	return matchAll(q.Where, values)
+}
+
+func matchAll(expressions []query.Expression, values map[string]any) (bool, error) {
+	for _, e := range expressions {
+		ok, err := matchExpression(e, values)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchExpression(e query.Expression, values map[string]any) (bool, error) {
+	switch e := e.(type) {
+	case query.ExpressionCmp:
+		return matchCmp(e, values)
+	case query.ExpressionLogic:
+		switch e.Operator {
+		case query.OperatorAnd:
+			return matchAll(e.List, values)
+		case query.OperatorOr:
+			for _, item := range e.List {
+				ok, err := matchExpression(item, values)
+				if err != nil || ok {
+					return ok, err
+				}
+			}
+
+			return len(e.List) == 0, nil
+		}
+
+		return false, fmt.Errorf("unsupported operator: [%s]", e.Operator)
+	}
+
+	return false, fmt.Errorf("unexpected expression type: %T", e)
+}
+
+// matchCmp compares the column like SQL, a null column matches only IS NULL.
+func matchCmp(e query.ExpressionCmp, values map[string]any) (bool, error) {
+	stored, ok := values[column(e.Field)]
+	if !ok {
+		return false, fmt.Errorf("column %q does not exist", e.Field)
+	}
+
+	operator := e.Operator
+	if list(e.Value) != nil {
+		switch operator {
+		case query.OperatorEq:
+			operator = query.OperatorIn
+		case query.OperatorNe:
+			operator = query.OperatorNIn
+		}
+	}
+
+	switch {
+	case operator == query.OperatorIs, operator == query.OperatorEq && e.Value == nil:
+		return stored == nil, nil
+	case operator == query.OperatorIsNot, operator == query.OperatorNe && e.Value == nil:
+		return stored != nil, nil
+	case stored == nil:
+		return false, nil
+	}
+
+	switch operator {
+	case query.OperatorEq, query.OperatorNe, query.OperatorGt, query.OperatorLt, query.OperatorGte, query.OperatorLte:
+		c, err := compare(stored, e.Value)
+		if err != nil {
+			return false, err
+		}
+
+		switch operator {
+		case query.OperatorEq:
+			return c == 0, nil
+		case query.OperatorNe:
+			return c != 0, nil
+		case query.OperatorGt:
+			return c > 0, nil
+		case query.OperatorLt:
+			return c < 0, nil
+		case query.OperatorGte:
+			return c >= 0, nil
+		default:
+			return c <= 0, nil
+		}
+	case query.OperatorLike, query.OperatorNLike, query.OperatorILike, query.OperatorNILike:
+		insensitive := operator == query.OperatorILike || operator == query.OperatorNILike
+		ok, err := like(fmt.Sprint(stored), fmt.Sprint(value(e.Value)), insensitive)
+		if err != nil {
+			return false, err
+		}
+
+		return ok == (operator == query.OperatorLike || operator == query.OperatorILike), nil
+	case query.OperatorIn, query.OperatorNIn:
+		items := list(e.Value)
+		if items == nil {
+			items = []any{e.Value}
+		}
+
+		found := false
+		for _, item := range items {
+			c, err := compare(stored, item)
+			if err != nil {
+				return false, err
+			}
+
+			if c == 0 {
+				found = true
+
+				break
+			}
+		}
+
+		return found == (operator == query.OperatorIn), nil
+	}
+
+	return false, fmt.Errorf("unsupported operator: [%s]", operator)
+}
+
+// list returns the items of a list value, nil for a single value.
+func list(v any) []any {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil
+	}
+
+	items := make([]any, 0, rv.Len())
+	for i := range rv.Len() {
+		items = append(items, rv.Index(i).Interface())
+	}
+
+	return items
+}
+
+// compare compares the stored value with the value of the query converted to the type of the stored one.
+func compare(stored, v any) (int, error) {
+	v = value(v)
+
+	switch s := stored.(type) {
+	case time.Time:
+		t, ok := v.(time.Time)
+		if !ok {
+			var parsed types.Time
+			if err := parsed.Parse(fmt.Sprint(v)); err != nil {
+				return 0, err
+			}
+
+			t = parsed.Time
+		}
+
+		return s.Compare(t), nil
+	case bool:
+		b, ok := v.(bool)
+		if !ok {
+			var err error
+			if b, err = strconv.ParseBool(fmt.Sprint(v)); err != nil {
+				return 0, fmt.Errorf("invalid boolean %q", v)
+			}
+		}
+
+		switch {
+		case s == b:
+			return 0, nil
+		case b:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	case int64:
+		n, ok := v.(int64)
+		if !ok {
+			var err error
+			if n, err = strconv.ParseInt(fmt.Sprint(v), 10, 64); err != nil {
+				return 0, fmt.Errorf("invalid integer %q", v)
+			}
+		}
+
+		return cmp.Compare(s, n), nil
+	case float64:
+		f, ok := v.(float64)
+		if !ok {
+			var err error
+			if f, err = strconv.ParseFloat(fmt.Sprint(v), 64); err != nil {
+				return 0, fmt.Errorf("invalid number %q", v)
+			}
+		}
+
+		return cmp.Compare(s, f), nil
+	}
+
+	return strings.Compare(fmt.Sprint(stored), fmt.Sprint(v)), nil
+}
+
+// like matches the SQL LIKE pattern, % is any text and _ is any character.
+func like(s, pattern string, insensitive bool) (bool, error) {
+	var b strings.Builder
+	if insensitive {
+		b.WriteString("(?is)")
+	} else {
+		b.WriteString("(?s)")
+	}
+
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return false, err
+	}
+
+	return re.MatchString(s), nil
+}
@@ -2,15 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	// Register pgx driver for SQL.
 	"github.com/doug-martin/goqu/v9"
-	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 
+	// Register pgx and sqlite drivers for SQL.
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
 	"github.com/worldline-go/calendar/internal/config"
+	"github.com/worldline-go/calendar/internal/core/port"
 )
 
 var (
@@ -20,9 +24,27 @@ var (
 )
 
 type Database struct {
-	q *goqu.Database
+	q querier
+
+	// dialect is the goqu dialect of the connection, postgres or sqlite.
+	dialect string
+
+	// conn starts the transactions, tx is set when the database is bound to a transaction.
+	conn *goqu.Database
+	tx   *goqu.TxDatabase
+}
+
+// querier runs the statements on the connection or in the bound transaction.
+type querier interface {
+	From(from ...any) *goqu.SelectDataset
+	Insert(table any) *goqu.InsertDataset
+	Update(table any) *goqu.UpdateDataset
+	Delete(table any) *goqu.DeleteDataset
 }
 
+// errPreview rolls back the transaction of a preview.
+var errPreview = errors.New("preview")
+
 // New attempts to connect to database server and returns a new Database instance.
 func New(ctx context.Context, cfg *config.Config) (*Database, error) {
 	db, err := sqlx.ConnectContext(ctx, cfg.DBType, cfg.DBDataSource)
@@ -34,13 +56,73 @@ func New(ctx context.Context, cfg *config.Config) (*Database, error) {
 	db.SetMaxIdleConns(MaxIdleConns)
 	db.SetMaxOpenConns(MaxOpenConns)
 
-	return newDB(db, cfg.DBSchema), nil
+	schema := cfg.DBSchema
+	if IsSQLite(cfg.DBType) {
+		// writes of sqlite are serialized, one connection avoids busy errors of the concurrent transactions
+		db.SetMaxOpenConns(1)
+		schema = SQLiteSchema
+	}
+
+	return newDB(db, schema), nil
 }
 
 func newDB(db *sqlx.DB, schema string) *Database {
 	setSchema(schema)
 
+	dialect := "postgres"
+	if IsSQLite(db.DriverName()) {
+		dialect = DialectSQLite
+	}
+
+	conn := goqu.New(dialect, db)
+
 	return &Database{
-		q: goqu.New("postgres", db),
+		q:       conn,
+		dialect: dialect,
+		conn:    conn,
 	}
 }
+
+// transaction runs fn in a new transaction, a database bound to a transaction runs it in that one.
+func (db *Database) transaction(ctx context.Context, fn func(tx *goqu.TxDatabase) error) error {
+	if db.tx != nil {
+		return fn(db.tx)
+	}
+
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Wrap(func() error {
+		return fn(tx)
+	})
+}
+
+// Transaction runs fn with the database bound to one transaction.
+// Changes of fn are committed together, an error of fn rolls back all of them.
+func (db *Database) Transaction(ctx context.Context, fn func(port.CalendarPort) error) error {
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		return fn(&Database{q: tx, dialect: db.dialect, conn: db.conn, tx: tx})
+	})
+}
+
+// Preview runs fn like Transaction and rolls back all of its changes, fn reads the calendar with the changes.
+func (db *Database) Preview(ctx context.Context, fn func(port.CalendarPort) error) error {
+	if db.tx != nil {
+		return errors.New("preview is not supported in a transaction")
+	}
+
+	err := db.Transaction(ctx, func(bound port.CalendarPort) error {
+		if err := fn(bound); err != nil {
+			return err
+		}
+
+		return errPreview
+	})
+	if errors.Is(err, errPreview) {
+		return nil
+	}
+
+	return err
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/doug-martin/goqu/v9"
@@ -12,6 +15,7 @@ import (
 	"github.com/worldline-go/query/adapter/adaptergoqu"
 	"github.com/worldline-go/types"
 
+	"github.com/worldline-go/calendar/internal/core/domain"
 	"github.com/worldline-go/calendar/pkg/models"
 )
 
@@ -22,73 +26,202 @@ type QeuryHoliday struct {
 }
 
 var (
-	TableEventsStr    = "calendar_events"
-	TableRelationsStr = "calendar_relations"
+	TableEventsStr          = "calendar_events"
+	TableRelationsStr       = "calendar_relations"
+	TableEntityTreeStr      = "calendar_entity_tree"
+	TableJointsStr          = "calendar_joints"
+	TableHoursStr           = "calendar_hours"
+	TableWeekendsStr        = "calendar_weekends"
+	TableEventHistoryStr    = "calendar_event_history"
+	TableRelationHistoryStr = "calendar_relation_history"
+	TableChangeSetsStr      = "calendar_change_sets"
 
-	TableEvents   exp.IdentifierExpression
-	TableRelation exp.IdentifierExpression
+	TableEvents          exp.IdentifierExpression
+	TableRelation        exp.IdentifierExpression
+	TableJoints          exp.IdentifierExpression
+	TableHours           exp.IdentifierExpression
+	TableWeekends        exp.IdentifierExpression
+	TableEventHistory    exp.IdentifierExpression
+	TableRelationHistory exp.IdentifierExpression
+	TableChangeSets      exp.IdentifierExpression
 
-	Schema          exp.IdentifierExpression
-	TableEventsAs   exp.AliasedExpression
-	TableRelationAs exp.AliasedExpression
+	Schema exp.IdentifierExpression
 )
 
 var ErrStopLoop = errors.New("stop loop")
 
+var (
+	// notDeleted matches the events and relations not in the trash.
+	notDeleted = goqu.C("deleted_at").IsNull()
+	// deleted matches the events and relations in the trash.
+	deleted = goqu.C("deleted_at").IsNotNull()
+)
+
+// eventsRename qualifies the query fields, relations are joined with the same column names.
+var eventsRename = map[string]string{
+	"entity":      TableEntityTreeStr + ".root",
+	"id":          TableEventsStr + ".id",
+	"name":        TableEventsStr + ".name",
+	"description": TableEventsStr + ".description",
+	"event_group": TableEventsStr + ".event_group",
+	"disabled":    TableEventsStr + ".disabled",
+	"type":        TableEventsStr + ".type",
+	"date_from":   TableEventsStr + ".date_from",
+	"date_to":     TableEventsStr + ".date_to",
+	"updated_at":  TableEventsStr + ".updated_at",
+	"updated_by":  TableEventsStr + ".updated_by",
+}
+
 func setSchema(schema string) {
 	Schema = goqu.S(schema)
 	TableEvents = Schema.Table(TableEventsStr)
 	TableRelation = Schema.Table(TableRelationsStr)
-
-	TableEventsAs = TableEvents.As(TableEventsStr)
-	TableRelationAs = TableRelation.As(TableRelationsStr)
+	TableJoints = Schema.Table(TableJointsStr)
+	TableHours = Schema.Table(TableHoursStr)
+	TableWeekends = Schema.Table(TableWeekendsStr)
+	TableEventHistory = Schema.Table(TableEventHistoryStr)
+	TableRelationHistory = Schema.Table(TableRelationHistoryStr)
+	TableChangeSets = Schema.Table(TableChangeSetsStr)
 }
 
+// AddEvents adds the events, existing IDs are skipped and an event in the trash is replaced with the new one.
 func (db *Database) AddEvents(ctx context.Context, events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
 	updatedAt := types.Time{Time: time.Now()}
 
 	for i := range events {
 		if events[i].ID == "" {
 			events[i].ID = ulid.Make().String()
 		}
+		if events[i].Type == "" {
+			events[i].Type = domain.EventTypeHoliday
+		}
+		events[i].Version = 1
 		events[i].UpdatedAt = updatedAt
 	}
 
-	_, err := db.q.Insert(TableEvents).
-		Rows(events).
-		OnConflict(goqu.DoNothing()).
-		Executor().ExecContext(ctx)
+	replace, err := replaceDeleted(events[0])
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		var inserted []models.Event
+		if err := tx.Insert(TableEvents).
+			Rows(events).
+			OnConflict(replace).
+			Returning(goqu.Star()).
+			Executor().ScanStructsContext(ctx, &inserted); err != nil {
+			return err
+		}
+
+		history := make([]models.EventHistory, 0, len(inserted))
+		for i := range inserted {
+			history = append(history, newEventHistory(domain.HistoryActionInsert, nil, &inserted[i], inserted[i].UpdatedBy))
+		}
+
+		return addHistory(ctx, tx, history)
+	})
+}
+
+// replaceDeleted updates the conflicting event with the inserted values only when it is in the trash.
+func replaceDeleted(event models.Event) (exp.ConflictExpression, error) {
+	record, err := exp.NewRecordFromStruct(event, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for k := range record {
+		record[k] = goqu.I("excluded." + k)
+	}
+	record["deleted_at"] = nil
+	record["deleted_by"] = nil
+
+	return goqu.DoUpdate("id", record).Where(goqu.I(TableEventsStr + ".deleted_at").IsNotNull()), nil
 }
 
-func (db *Database) getEventsSelect(q *query.Query) *goqu.SelectDataset {
-	selectDataSet := adaptergoqu.Select(q, db.q.From(TableEventsAs),
+func (db *Database) getEventsSelect(q *query.Query) (*goqu.SelectDataset, error) {
+	at, err := asOf(q)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := db.tables(at)
+
+	selectDataSet := adaptergoqu.Select(db.query(q), db.q.From(aliased(tables.events, TableEventsStr)),
 		adaptergoqu.WithDefaultSelect(TableEventsStr+".*"),
-		adaptergoqu.WithRename(map[string]string{
-			"entity": TableRelationsStr + ".entity",
-		}),
+		adaptergoqu.WithRename(eventsRename),
 	).Distinct()
 
 	if q.HasAny("entity") {
-		selectDataSet = selectDataSet.RightJoin(TableRelationAs, goqu.On(
-			goqu.Or(
-				goqu.Ex{TableRelationsStr + ".event_id": goqu.I(TableEventsStr + ".id")},
-				goqu.Ex{TableRelationsStr + ".event_group": goqu.I(TableEventsStr + ".event_group")},
-			),
-		))
+		selectDataSet = selectDataSet.
+			WithRecursive(TableEntityTreeStr+"(root, entity, path)", db.entityTree(tables.relations)).
+			Join(aliased(tables.relations, TableRelationsStr), goqu.On(
+				goqu.Or(
+					goqu.Ex{TableRelationsStr + ".event_id": goqu.I(TableEventsStr + ".id")},
+					goqu.Ex{TableRelationsStr + ".event_group": goqu.I(TableEventsStr + ".event_group")},
+				),
+				goqu.Ex{TableRelationsStr + ".type": domain.RelationTypeInclude},
+			)).
+			Join(goqu.T(TableEntityTreeStr), goqu.On(
+				goqu.Ex{TableEntityTreeStr + ".entity": goqu.I(TableRelationsStr + ".entity")},
+			)).
+			Where(goqu.L("NOT EXISTS ?", db.q.From(aliased(tables.relations, "excluded")).
+				Select(goqu.L("1")).
+				Where(
+					goqu.Ex{"excluded.type": domain.RelationTypeExclude},
+					goqu.Ex{"excluded.occurrence": nil},
+					db.inPath(goqu.I("excluded.entity"), goqu.I(TableEntityTreeStr+".path")),
+					goqu.Or(
+						goqu.Ex{"excluded.event_id": goqu.I(TableEventsStr + ".id")},
+						goqu.Ex{"excluded.event_group": goqu.I(TableEventsStr + ".event_group")},
+					),
+				),
+			))
+	}
+
+	return selectDataSet, nil
+}
+
+// entityTree resolves every entity to itself and to all of its ancestors through the parent relations.
+//   - root is the entity asked for, entity is the one holding the relations.
+//   - path is the chain from root to entity, it stops the recursion on cycles.
+func (db *Database) entityTree(relations exp.Expression) exp.Expression {
+	if db.dialect == DialectSQLite {
+		return sqliteEntityTree(relations)
+	}
+
+	return goqu.L(`(SELECT DISTINCT entity, entity, ARRAY[entity] FROM ? AS relations
+UNION ALL
+SELECT tree.root, parents.parent, tree.path || parents.parent
+FROM ? AS parents JOIN ? AS tree ON parents.entity = tree.entity
+WHERE parents.type = ? AND NOT parents.parent = ANY(tree.path))`,
+		relations, relations, goqu.T(TableEntityTreeStr), domain.RelationTypeParent,
+	)
+}
+
+// inPath checks the entity is in the path of the entity tree.
+func (db *Database) inPath(entity, path exp.Expression) exp.Expression {
+	if db.dialect == DialectSQLite {
+		return sqliteInPath(entity, path)
 	}
 
-	return selectDataSet
+	return goqu.L("? = ANY(?)", entity, path)
 }
 
 func (db *Database) GetEventsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	selectDataSet, err := db.getEventsSelect(q)
+	if err != nil {
+		return 0, err
+	}
+
 	var count uint64
-	_, err := db.getEventsSelect(q).
-		Select(goqu.COUNT(goqu.DISTINCT("id"))).
+	_, err = selectDataSet.
+		ClearOrder().ClearLimit().ClearOffset().
+		Select(goqu.COUNT(goqu.DISTINCT(goqu.I(TableEventsStr+".id")))).
 		Executor().ScanValContext(ctx, &count)
 	if err != nil {
 		return 0, err
@@ -98,9 +231,14 @@ func (db *Database) GetEventsCount(ctx context.Context, q *query.Query) (uint64,
 }
 
 func (db *Database) GetEvents(ctx context.Context, q *query.Query) ([]models.Event, error) {
+	selectDataSet, err := db.getEventsSelect(q)
+	if err != nil {
+		return nil, err
+	}
+
 	var events []models.Event
 
-	if err := db.getEventsSelect(q).Executor().ScanStructsContext(ctx, &events); err != nil {
+	if err := selectDataSet.Executor().ScanStructsContext(ctx, &events); err != nil {
 		return nil, err
 	}
 
@@ -108,7 +246,12 @@ func (db *Database) GetEvents(ctx context.Context, q *query.Query) ([]models.Eve
 }
 
 func (db *Database) GetEventsWithFunc(ctx context.Context, q *query.Query, fn func(models.Event) error) error {
-	scanner, err := db.getEventsSelect(q).Executor().ScannerContext(ctx)
+	selectDataSet, err := db.getEventsSelect(q)
+	if err != nil {
+		return err
+	}
+
+	scanner, err := selectDataSet.Executor().ScannerContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -135,13 +278,107 @@ func (db *Database) GetEventsWithFunc(ctx context.Context, q *query.Query, fn fu
 	return nil
 }
 
+// aggregate is the number of rows and their last change.
+type aggregate struct {
+	Count     int      `db:"count"`
+	UpdatedAt lastTime `db:"updated_at"`
+}
+
+// lastTime is the maximum of a time column, sqlite returns it as the text of the time without the column type.
+type lastTime struct {
+	types.Time
+}
+
+func (t *lastTime) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return t.Scan(string(v))
+	case string:
+		parsed, err := time.Parse(sqliteTimeFormat, v)
+		if err != nil {
+			return fmt.Errorf("cannot scan [%v] into time: %w", v, err)
+		}
+
+		t.Time.Time = parsed
+
+		return nil
+	}
+
+	return t.Time.Scan(value)
+}
+
+// GetModified aggregates the events of the query, all relations and the weekends of the entities without reading them.
+// The last change also covers the removals, the events and relations in the trash are not in the counts.
+func (db *Database) GetModified(ctx context.Context, q *query.Query, entities []string) (*models.Modified, error) {
+	selected := []any{goqu.COUNT(goqu.Star()).As("count"), goqu.MAX("updated_at").As("updated_at")}
+
+	selectDataSet, err := db.getEventsSelect(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var events, relations, weekends aggregate
+	if _, err := selectDataSet.
+		ClearOrder().ClearLimit().ClearOffset().
+		Select(
+			goqu.COUNT(goqu.DISTINCT(goqu.I(TableEventsStr+".id"))).As("count"),
+			goqu.MAX(goqu.I(TableEventsStr+".updated_at")).As("updated_at"),
+		).
+		Executor().ScanStructContext(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to aggregate events: %w", err)
+	}
+
+	if _, err := db.q.From(TableRelation).Where(notDeleted).
+		Select(selected...).
+		Executor().ScanStructContext(ctx, &relations); err != nil {
+		return nil, fmt.Errorf("failed to aggregate relations: %w", err)
+	}
+
+	// removals change the calendars without an update, the last one in the trash is a change too
+	var removed []lastTime
+	for _, table := range []exp.IdentifierExpression{TableEvents, TableRelation} {
+		var trash aggregate
+		if _, err := db.q.From(table).Where(deleted).
+			Select(goqu.COUNT(goqu.Star()).As("count"), goqu.MAX("deleted_at").As("updated_at")).
+			Executor().ScanStructContext(ctx, &trash); err != nil {
+			return nil, fmt.Errorf("failed to aggregate the trash: %w", err)
+		}
+
+		removed = append(removed, trash.UpdatedAt)
+	}
+
+	if len(entities) > 0 {
+		if _, err := db.q.From(TableWeekends).Where(goqu.C("entity").In(entities)).
+			Select(selected...).
+			Executor().ScanStructContext(ctx, &weekends); err != nil {
+			return nil, fmt.Errorf("failed to aggregate weekends: %w", err)
+		}
+	}
+
+	modified := &models.Modified{
+		Events:    events.Count,
+		Relations: relations.Count,
+		Weekends:  weekends.Count,
+	}
+
+	for _, t := range append([]lastTime{events.UpdatedAt, relations.UpdatedAt, weekends.UpdatedAt}, removed...) {
+		if t.After(modified.UpdatedAt.Time) {
+			modified.UpdatedAt = t.Time
+		}
+	}
+
+	return modified, nil
+}
+
 func (db *Database) GetEvent(ctx context.Context, id string) (*models.Event, error) {
 	var event models.Event
 
 	found, err := db.q.From(TableEvents).
 		Where(goqu.Ex{
 			"id": id,
-		}).
+		}, notDeleted).
 		Executor().ScanStructContext(ctx, &event)
 	if err != nil {
 		return nil, err
@@ -154,70 +391,169 @@ func (db *Database) GetEvent(ctx context.Context, id string) (*models.Event, err
 	return &event, nil
 }
 
+// UpdateEvent replaces the event when its stored version is the version of the event, the version is increased.
 func (db *Database) UpdateEvent(ctx context.Context, id string, event *models.Event) error {
+	version := event.Version
+
 	event.UpdatedAt = types.Time{Time: time.Now()}
+	event.Version = version + 1
+	if event.Type == "" {
+		event.Type = domain.EventTypeHoliday
+	}
+
+	if err := db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		before, err := lockEvent(ctx, tx, id, version)
+		if err != nil {
+			return err
+		}
+
+		var after models.Event
+		if _, err := tx.Update(TableEvents).
+			Set(event).
+			Where(goqu.Ex{
+				"id": id,
+			}).
+			Returning(goqu.Star()).
+			Executor().ScanStructContext(ctx, &after); err != nil {
+			return err
+		}
+
+		return addHistory(ctx, tx, []models.EventHistory{
+			newEventHistory(domain.HistoryActionUpdate, before, &after, event.UpdatedBy),
+		})
+	}); err != nil {
+		event.Version = version
 
-	_, err := db.q.Update(TableEvents).
-		Set(event).
-		Where(goqu.Ex{
-			"id": id,
-		}).
-		Executor().ExecContext(ctx)
-	if err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (db *Database) RemoveEvent(ctx context.Context, id ...string) error {
-	_, err := db.q.Delete(TableEvents).
+// RemoveEventVersion moves the event to the trash when its stored version is the version.
+func (db *Database) RemoveEventVersion(ctx context.Context, id string, version int64, removedBy string) error {
+	deletedAt := time.Now()
+
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		before, err := lockEvent(ctx, tx, id, version)
+		if err != nil {
+			return err
+		}
+
+		if err := removeRelations(ctx, tx, []exp.Expression{goqu.Ex{"event_id": id}}, deletedAt, removedBy); err != nil {
+			return err
+		}
+
+		if _, err := tx.Update(TableEvents).
+			Set(deletedRecord(deletedAt, removedBy)).
+			Where(goqu.Ex{
+				"id": id,
+			}).
+			Executor().ExecContext(ctx); err != nil {
+			return err
+		}
+
+		return addHistory(ctx, tx, []models.EventHistory{
+			newEventHistory(domain.HistoryActionDelete, before, nil, removedBy),
+		})
+	})
+}
+
+// lockEvent returns the stored event locked for the transaction when it has the version.
+func lockEvent(ctx context.Context, tx *goqu.TxDatabase, id string, version int64) (*models.Event, error) {
+	var event models.Event
+
+	found, err := tx.From(TableEvents).
 		Where(goqu.Ex{
-			"id": goqu.Op{"in": id},
-		}).
-		Executor().ExecContext(ctx)
+			"id": id,
+		}, notDeleted).
+		ForUpdate(exp.Wait).
+		Executor().ScanStructContext(ctx, &event)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	if !found {
+		return nil, fmt.Errorf("%w: %s", domain.ErrEventNotFound, id)
+	}
+
+	if event.Version != version {
+		return nil, fmt.Errorf("%w: %s has version %d", domain.ErrEventVersion, id, event.Version)
+	}
+
+	return &event, nil
+}
+
+// RemoveEvent moves the events to the trash, their relations are moved with them and restored together.
+func (db *Database) RemoveEvent(ctx context.Context, removedBy string, id ...string) error {
+	deletedAt := time.Now()
+
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		if err := removeRelations(ctx, tx, []exp.Expression{goqu.Ex{"event_id": goqu.Op{"in": id}}}, deletedAt, removedBy); err != nil {
+			return err
+		}
+
+		var removed []models.Event
+		if err := tx.Update(TableEvents).
+			Set(deletedRecord(deletedAt, removedBy)).
+			Where(goqu.Ex{
+				"id": goqu.Op{"in": id},
+			}, notDeleted).
+			Returning(goqu.Star()).
+			Executor().ScanStructsContext(ctx, &removed); err != nil {
+			return err
+		}
+
+		history := make([]models.EventHistory, 0, len(removed))
+		for i := range removed {
+			history = append(history, newEventHistory(domain.HistoryActionDelete, &removed[i], nil, removedBy))
+		}
+
+		return addHistory(ctx, tx, history)
+	})
 }
 
 // /////////////////////////////////////////////////////////////
-// Relation
+// Trash
 // /////////////////////////////////////////////////////////////
 
-func (db *Database) AddRelations(ctx context.Context, relations []models.Relation) error {
-	updatedAt := types.Time{Time: time.Now()}
+// deletedRecord moves the rows to the trash.
+func deletedRecord(deletedAt time.Time, deletedBy string) goqu.Record {
+	return goqu.Record{"deleted_at": deletedAt, "deleted_by": deletedBy}
+}
 
-	for i := range relations {
-		relations[i].UpdatedAt = updatedAt
-	}
+// restoredRecord takes the rows back from the trash, the restore is the last update of them.
+func restoredRecord() goqu.Record {
+	return goqu.Record{"deleted_at": nil, "deleted_by": nil, "updated_at": time.Now()}
+}
 
-	_, err := db.q.Insert(TableRelation).
-		Rows(relations).
-		OnConflict(goqu.DoNothing()).
-		Executor().ExecContext(ctx)
+func (db *Database) GetTrashEventsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := adaptergoqu.Select(db.query(q), db.q.From(TableEvents).Where(deleted)).CountContext(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return uint64(count), nil
 }
 
-func (db *Database) RemoveRelation(ctx context.Context, q *query.Query) error {
-	_, err := db.q.Delete(TableRelation).
-		Where(adaptergoqu.Expression(q)...).
-		Executor().ExecContext(ctx)
-	if err != nil {
-		return err
+// GetTrashEvents returns the events in the trash, the latest removed is first without a sort.
+func (db *Database) GetTrashEvents(ctx context.Context, q *query.Query) ([]models.Event, error) {
+	var events []models.Event
+
+	selectDataSet := adaptergoqu.Select(db.query(q), db.q.From(TableEvents).Where(deleted))
+	if len(q.Sort) == 0 {
+		selectDataSet = selectDataSet.Order(goqu.I("deleted_at").Desc(), goqu.I("id").Asc())
 	}
 
-	return nil
+	if err := selectDataSet.Executor().ScanStructsContext(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
 }
 
-func (db *Database) GetRelationsCount(ctx context.Context, q *query.Query) (uint64, error) {
-	count, err := adaptergoqu.Select(q, db.q.From(TableRelation)).CountContext(ctx)
+func (db *Database) GetTrashRelationsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := adaptergoqu.Select(db.query(q), db.q.From(TableRelation).Where(deleted)).CountContext(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -225,12 +561,961 @@ func (db *Database) GetRelationsCount(ctx context.Context, q *query.Query) (uint
 	return uint64(count), nil
 }
 
-func (db *Database) GetRelations(ctx context.Context, q *query.Query) ([]models.Relation, error) {
+// GetTrashRelations returns the relations in the trash, the latest removed is first without a sort.
+func (db *Database) GetTrashRelations(ctx context.Context, q *query.Query) ([]models.Relation, error) {
 	var relations []models.Relation
 
-	if err := adaptergoqu.Select(q, db.q.From(TableRelation)).Executor().ScanStructsContext(ctx, &relations); err != nil {
+	selectDataSet := adaptergoqu.Select(db.query(q), db.q.From(TableRelation).Where(deleted))
+	if len(q.Sort) == 0 {
+		selectDataSet = selectDataSet.Order(goqu.I("deleted_at").Desc(), goqu.I("entity").Asc())
+	}
+
+	if err := selectDataSet.Executor().ScanStructsContext(ctx, &relations); err != nil {
 		return nil, err
 	}
 
 	return relations, nil
 }
+
+// RestoreEvent takes the event back from the trash with the relations removed together with it.
+func (db *Database) RestoreEvent(ctx context.Context, id string, restoredBy string) error {
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		var event models.Event
+		found, err := tx.From(TableEvents).
+			Where(goqu.Ex{
+				"id": id,
+			}, deleted).
+			ForUpdate(exp.Wait).
+			Executor().ScanStructContext(ctx, &event)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return fmt.Errorf("%w: %s is not in the trash", domain.ErrEventNotFound, id)
+		}
+
+		var restored models.Event
+		if _, err := tx.Update(TableEvents).
+			Set(restoredRecord()).
+			Where(goqu.Ex{
+				"id": id,
+			}).
+			Returning(goqu.Star()).
+			Executor().ScanStructContext(ctx, &restored); err != nil {
+			return err
+		}
+
+		if err := restoreRelations(ctx, tx, []exp.Expression{goqu.Ex{
+			"event_id":   id,
+			"deleted_at": event.DeletedAt.V.Time,
+		}}, restoredBy); err != nil {
+			return err
+		}
+
+		return addHistory(ctx, tx, []models.EventHistory{
+			newEventHistory(domain.HistoryActionRestore, nil, &restored, restoredBy),
+		})
+	})
+}
+
+// RestoreRelations takes the matching relations back from the trash.
+// Relations of an event in the trash are skipped, they are restored with the event.
+func (db *Database) RestoreRelations(ctx context.Context, q *query.Query, restoredBy string) error {
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		return restoreRelations(ctx, tx, append(adaptergoqu.Expression(db.query(q)),
+			goqu.L("NOT EXISTS ?", tx.From(TableEvents).
+				Select(goqu.L("1")).
+				Where(
+					goqu.Ex{"id": goqu.I(TableRelationsStr + ".event_id")},
+					deleted,
+				),
+			),
+		), restoredBy)
+	})
+}
+
+// restoreRelations takes the matching relations back from the trash in the transaction and records them.
+func restoreRelations(ctx context.Context, tx *goqu.TxDatabase, where []exp.Expression, restoredBy string) error {
+	var restored []models.Relation
+	if err := tx.Update(TableRelation).
+		Set(restoredRecord()).
+		Where(where...).
+		Where(deleted).
+		Returning(goqu.Star()).
+		Executor().ScanStructsContext(ctx, &restored); err != nil {
+		return err
+	}
+
+	history := make([]relationHistory, 0, len(restored))
+	for i := range restored {
+		history = append(history, newRelationHistory(domain.HistoryActionRestore, nil, &restored[i], restoredBy))
+	}
+
+	return addRelationHistory(ctx, tx, history)
+}
+
+// PurgeTrash removes the events and relations moved to the trash before the time permanently.
+// It returns the count of the removed events and relations, their history is kept.
+func (db *Database) PurgeTrash(ctx context.Context, before time.Time) (uint64, error) {
+	var purged int64
+
+	if err := db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		for _, table := range []exp.IdentifierExpression{TableRelation, TableEvents} {
+			result, err := tx.Delete(table).
+				Where(goqu.C("deleted_at").Lt(before)).
+				Executor().ExecContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			n, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+
+			purged += n
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return uint64(purged), nil
+}
+
+// /////////////////////////////////////////////////////////////
+// History
+// /////////////////////////////////////////////////////////////
+
+// newEventHistory records a change of an event, before is nil for inserts and after is nil for deletes.
+func newEventHistory(action string, before, after *models.Event, changedBy string) models.EventHistory {
+	history := models.EventHistory{
+		Action:    action,
+		ChangedAt: types.Time{Time: time.Now()},
+		ChangedBy: changedBy,
+	}
+
+	if before != nil {
+		history.EventID = before.ID
+		history.Version = before.Version
+		history.Before = types.NewJSON(*before)
+	}
+
+	if after != nil {
+		history.EventID = after.ID
+		history.Version = after.Version
+		history.After = types.NewJSON(*after)
+	}
+
+	return history
+}
+
+func addHistory(ctx context.Context, tx *goqu.TxDatabase, history []models.EventHistory) error {
+	if len(history) == 0 {
+		return nil
+	}
+
+	_, err := tx.Insert(TableEventHistory).
+		Rows(history).
+		Executor().ExecContext(ctx)
+
+	return err
+}
+
+func (db *Database) GetHistoryCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := adaptergoqu.Select(db.query(q), db.q.From(TableEventHistory)).CountContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+// GetHistory returns the recorded changes of the events, the latest change is first without a sort.
+func (db *Database) GetHistory(ctx context.Context, q *query.Query) ([]models.EventHistory, error) {
+	var history []models.EventHistory
+
+	selectDataSet := adaptergoqu.Select(db.query(q), db.q.From(TableEventHistory))
+	if len(q.Sort) == 0 {
+		selectDataSet = selectDataSet.Order(goqu.I("id").Desc())
+	}
+
+	if err := selectDataSet.Executor().ScanStructsContext(ctx, &history); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// relationHistory is a recorded change of a relation, relations are replayed with their snapshots for as_of.
+type relationHistory struct {
+	Action string `db:"action"`
+
+	Before types.JSON[models.Relation] `db:"before"`
+	After  types.JSON[models.Relation] `db:"after"`
+
+	ChangedAt types.Time `db:"changed_at"`
+	ChangedBy string     `db:"changed_by"`
+}
+
+// newRelationHistory records a change of a relation, before is nil for inserts and after is nil for deletes.
+func newRelationHistory(action string, before, after *models.Relation, changedBy string) relationHistory {
+	history := relationHistory{
+		Action:    action,
+		ChangedAt: types.Time{Time: time.Now()},
+		ChangedBy: changedBy,
+	}
+
+	if before != nil {
+		history.Before = types.NewJSON(*before)
+	}
+
+	if after != nil {
+		history.After = types.NewJSON(*after)
+	}
+
+	return history
+}
+
+func addRelationHistory(ctx context.Context, tx *goqu.TxDatabase, history []relationHistory) error {
+	if len(history) == 0 {
+		return nil
+	}
+
+	_, err := tx.Insert(TableRelationHistory).
+		Rows(history).
+		Executor().ExecContext(ctx)
+
+	return err
+}
+
+// /////////////////////////////////////////////////////////////
+// As of
+// /////////////////////////////////////////////////////////////
+
+// calendarTables are the sources of the events and relations resolving the calendar.
+type calendarTables struct {
+	events    exp.Expression
+	relations exp.Expression
+}
+
+// tables returns the events and relations not in the trash, non-zero asOf returns their state at that time from the history.
+func (db *Database) tables(asOf time.Time) calendarTables {
+	if asOf.IsZero() {
+		return calendarTables{
+			events:    db.q.From(TableEvents).Where(notDeleted),
+			relations: db.q.From(TableRelation).Where(notDeleted),
+		}
+	}
+
+	return calendarTables{events: db.eventsAsOf(asOf), relations: db.relationsAsOf(asOf)}
+}
+
+// eventsAsOf returns the events with their latest change at or before the time, removed events are skipped.
+func (db *Database) eventsAsOf(asOf time.Time) *goqu.SelectDataset {
+	if db.dialect == DialectSQLite {
+		return db.sqliteEventsAsOf(asOf)
+	}
+
+	latest := db.q.From(TableEventHistory).
+		Select("event_id", "action", "after").
+		Distinct("event_id").
+		Where(goqu.C("changed_at").Lte(asOf)).
+		Order(goqu.I("event_id").Asc(), goqu.I("id").Desc())
+
+	return db.q.From(latest.As("latest")).
+		Select(goqu.L("(jsonb_populate_record(NULL::?, latest.after)).*", TableEvents)).
+		Where(goqu.C("action").Neq(domain.HistoryActionDelete))
+}
+
+// relationsAsOf replays the relation changes at or before the time.
+// Relations have no ID, a relation exists when it is added more than removed.
+func (db *Database) relationsAsOf(asOf time.Time) *goqu.SelectDataset {
+	if db.dialect == DialectSQLite {
+		return db.sqliteRelationsAsOf(asOf)
+	}
+
+	changes := func(snapshot, n string) *goqu.SelectDataset {
+		return db.q.From(TableRelationHistory).
+			Select(
+				goqu.L("(jsonb_populate_record(NULL::?, ?)).*", TableRelation, goqu.I(snapshot)),
+				goqu.L(n).As("n"),
+			).
+			Where(
+				goqu.C("changed_at").Lte(asOf),
+				goqu.C(snapshot).IsNotNull(),
+			)
+	}
+
+	key := []any{"entity", "type", "event_id", "event_group", "parent", "occurrence"}
+
+	return db.q.From(changes("after", "1").UnionAll(changes("before", "-1")).As("changes")).
+		Select(append(key,
+			goqu.MAX("updated_at").As("updated_at"),
+			goqu.MAX("updated_by").As("updated_by"),
+		)...).
+		GroupBy(key...).
+		Having(goqu.SUM("n").Gt(0))
+}
+
+// aliased names the table or the subquery in a FROM or JOIN.
+func aliased(table exp.Expression, alias string) exp.Expression {
+	return goqu.L("? AS ?", table, goqu.I(alias))
+}
+
+// asOf returns the as_of time of the query, the current state is the zero time.
+func asOf(q *query.Query) (time.Time, error) {
+	var t types.Time
+	if v := q.GetValue("as_of"); v != "" {
+		if err := t.Parse(v); err != nil {
+			return time.Time{}, fmt.Errorf("%w %q: %w", domain.ErrAsOf, v, err)
+		}
+	}
+
+	return t.Time, nil
+}
+
+// /////////////////////////////////////////////////////////////
+// Relation
+// /////////////////////////////////////////////////////////////
+
+// AddRelations adds the relations, a relation with the same fields is skipped and one in the trash is replaced.
+func (db *Database) AddRelations(ctx context.Context, relations []models.Relation) error {
+	if len(relations) == 0 {
+		return nil
+	}
+
+	updatedAt := types.Time{Time: time.Now()}
+
+	for i := range relations {
+		relations[i].UpdatedAt = updatedAt
+	}
+
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		// a relation in the trash is replaced with the new one like the events
+		keys := make([]exp.Expression, 0, len(relations))
+		for _, r := range relations {
+			keys = append(keys, db.relationKey(r))
+		}
+
+		if _, err := tx.Delete(TableRelation).
+			Where(goqu.Or(keys...), deleted).
+			Executor().ExecContext(ctx); err != nil {
+			return err
+		}
+
+		var inserted []models.Relation
+		if err := tx.Insert(TableRelation).
+			Rows(relations).
+			OnConflict(goqu.DoNothing()).
+			Returning(goqu.Star()).
+			Executor().ScanStructsContext(ctx, &inserted); err != nil {
+			return err
+		}
+
+		history := make([]relationHistory, 0, len(inserted))
+		for i := range inserted {
+			history = append(history, newRelationHistory(domain.HistoryActionInsert, nil, &inserted[i], inserted[i].UpdatedBy))
+		}
+
+		return addRelationHistory(ctx, tx, history)
+	})
+}
+
+func (db *Database) RemoveRelation(ctx context.Context, q *query.Query, removedBy string) error {
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		return removeRelations(ctx, tx, adaptergoqu.Expression(db.query(q)), time.Now(), removedBy)
+	})
+}
+
+// RemoveRelations moves the relations matched with all of their fields to the trash, missing ones are skipped.
+func (db *Database) RemoveRelations(ctx context.Context, relations []models.Relation, removedBy string) error {
+	deletedAt := time.Now()
+
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		for _, r := range relations {
+			if err := removeRelations(ctx, tx, []exp.Expression{db.relationKey(r)}, deletedAt, removedBy); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// removeRelations moves the matching relations to the trash in the transaction and records them.
+func removeRelations(ctx context.Context, tx *goqu.TxDatabase, where []exp.Expression, deletedAt time.Time, removedBy string) error {
+	var removed []models.Relation
+	if err := tx.Update(TableRelation).
+		Set(deletedRecord(deletedAt, removedBy)).
+		Where(where...).
+		Where(notDeleted).
+		Returning(goqu.Star()).
+		Executor().ScanStructsContext(ctx, &removed); err != nil {
+		return err
+	}
+
+	history := make([]relationHistory, 0, len(removed))
+	for i := range removed {
+		history = append(history, newRelationHistory(domain.HistoryActionDelete, &removed[i], nil, removedBy))
+	}
+
+	return addRelationHistory(ctx, tx, history)
+}
+
+// UpdateRelations replaces the relations in one transaction, a missing relation fails all of them.
+func (db *Database) UpdateRelations(ctx context.Context, updates []models.RelationUpdate) error {
+	updatedAt := types.Time{Time: time.Now()}
+
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		history := make([]relationHistory, 0, len(updates))
+		for i := range updates {
+			updates[i].To.UpdatedAt = updatedAt
+
+			var before []models.Relation
+			if err := tx.From(TableRelation).
+				Where(db.relationKey(updates[i].From), notDeleted).
+				ForUpdate(exp.Wait).
+				Executor().ScanStructsContext(ctx, &before); err != nil {
+				return err
+			}
+
+			if len(before) == 0 {
+				return fmt.Errorf("%w: %s %s", domain.ErrRelationNotFound, updates[i].From.Entity, updates[i].From.Type)
+			}
+
+			var after []models.Relation
+			if err := tx.Update(TableRelation).
+				Set(updates[i].To).
+				Where(db.relationKey(updates[i].From), notDeleted).
+				Returning(goqu.Star()).
+				Executor().ScanStructsContext(ctx, &after); err != nil {
+				return err
+			}
+
+			for j := range min(len(before), len(after)) {
+				history = append(history, newRelationHistory(domain.HistoryActionUpdate, &before[j], &after[j], updates[i].To.UpdatedBy))
+			}
+		}
+
+		return addRelationHistory(ctx, tx, history)
+	})
+}
+
+// relationKey matches the relation with all of its fields, null ones are matched with IS NULL.
+func (db *Database) relationKey(r models.Relation) goqu.Ex {
+	key := goqu.Ex{
+		"entity":      r.Entity,
+		"type":        r.Type,
+		"event_id":    nil,
+		"event_group": nil,
+		"parent":      nil,
+		"occurrence":  nil,
+	}
+
+	if r.EventID.Valid {
+		key["event_id"] = r.EventID.V
+	}
+	if r.EventGroup.Valid {
+		key["event_group"] = r.EventGroup.V
+	}
+	if r.Parent.Valid {
+		key["parent"] = r.Parent.V
+	}
+	if r.Occurrence.Valid {
+		if db.dialect == DialectSQLite {
+			// sqlite has no date type, the occurrence is stored like the other times
+			key["occurrence"] = r.Occurrence.V
+		} else {
+			key["occurrence"] = goqu.Cast(goqu.V(r.Occurrence.V.Format(time.DateOnly)), "DATE")
+		}
+	}
+
+	return key
+}
+
+// GetExclusions returns the occurrence exclusions of the entities, also the ones defined on their ancestors.
+// Non-zero asOf returns the exclusions of that time.
+func (db *Database) GetExclusions(ctx context.Context, entities []string, asOf time.Time) ([]models.Relation, error) {
+	var relations []models.Relation
+
+	tables := db.tables(asOf)
+
+	if err := db.q.From(aliased(tables.relations, TableRelationsStr)).
+		WithRecursive(TableEntityTreeStr+"(root, entity, path)", db.entityTree(tables.relations)).
+		Join(goqu.T(TableEntityTreeStr), goqu.On(
+			goqu.Ex{TableEntityTreeStr + ".entity": goqu.I(TableRelationsStr + ".entity")},
+		)).
+		Select(goqu.I(TableRelationsStr+".*")).
+		Distinct().
+		Where(
+			goqu.Ex{TableEntityTreeStr + ".root": entities},
+			goqu.Ex{TableRelationsStr + ".type": domain.RelationTypeExclude},
+			goqu.I(TableRelationsStr+".occurrence").IsNotNull(),
+		).
+		Executor().ScanStructsContext(ctx, &relations); err != nil {
+		return nil, err
+	}
+
+	return relations, nil
+}
+
+func (db *Database) GetRelationsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := adaptergoqu.Select(db.query(q), db.q.From(TableRelation).Where(notDeleted)).CountContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+func (db *Database) GetRelations(ctx context.Context, q *query.Query) ([]models.Relation, error) {
+	var relations []models.Relation
+
+	if err := adaptergoqu.Select(db.query(q), db.q.From(TableRelation).Where(notDeleted)).Executor().ScanStructsContext(ctx, &relations); err != nil {
+		return nil, err
+	}
+
+	return relations, nil
+}
+
+// /////////////////////////////////////////////////////////////
+// Joint
+// /////////////////////////////////////////////////////////////
+
+func (db *Database) AddJoints(ctx context.Context, joints []models.Joint) error {
+	updatedAt := types.Time{Time: time.Now()}
+
+	for i := range joints {
+		joints[i].UpdatedAt = updatedAt
+	}
+
+	_, err := db.q.Insert(TableJoints).
+		Rows(joints).
+		OnConflict(goqu.DoNothing()).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *Database) GetJointsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := adaptergoqu.Select(db.query(q), db.q.From(TableJoints)).CountContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+func (db *Database) GetJoints(ctx context.Context, q *query.Query) ([]models.Joint, error) {
+	var joints []models.Joint
+
+	if err := adaptergoqu.Select(db.query(q), db.q.From(TableJoints)).Executor().ScanStructsContext(ctx, &joints); err != nil {
+		return nil, err
+	}
+
+	return joints, nil
+}
+
+func (db *Database) GetJoint(ctx context.Context, name string) (*models.Joint, error) {
+	var joint models.Joint
+
+	found, err := db.q.From(TableJoints).
+		Where(goqu.Ex{
+			"name": name,
+		}).
+		Executor().ScanStructContext(ctx, &joint)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return &joint, nil
+}
+
+func (db *Database) UpdateJoint(ctx context.Context, name string, joint *models.Joint) error {
+	joint.UpdatedAt = types.Time{Time: time.Now()}
+
+	_, err := db.q.Update(TableJoints).
+		Set(joint).
+		Where(goqu.Ex{
+			"name": name,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *Database) RemoveJoint(ctx context.Context, name ...string) error {
+	_, err := db.q.Delete(TableJoints).
+		Where(goqu.Ex{
+			"name": goqu.Op{"in": name},
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////
+// Business Hours
+// /////////////////////////////////////////////////////////////
+
+// SetHours adds or replaces the business hours of the entity.
+func (db *Database) SetHours(ctx context.Context, hours *models.BusinessHours) error {
+	hours.UpdatedAt = types.Time{Time: time.Now()}
+
+	_, err := db.q.Insert(TableHours).
+		Rows(hours).
+		OnConflict(goqu.DoUpdate("entity", goqu.Record{
+			"tz":         goqu.L("EXCLUDED.tz"),
+			"hours":      goqu.L("EXCLUDED.hours"),
+			"updated_at": goqu.L("EXCLUDED.updated_at"),
+			"updated_by": goqu.L("EXCLUDED.updated_by"),
+		})).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *Database) GetHoursCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := adaptergoqu.Select(db.query(q), db.q.From(TableHours)).CountContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+func (db *Database) GetHours(ctx context.Context, q *query.Query) ([]models.BusinessHours, error) {
+	var hours []models.BusinessHours
+
+	if err := adaptergoqu.Select(db.query(q), db.q.From(TableHours)).Executor().ScanStructsContext(ctx, &hours); err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+func (db *Database) GetEntityHours(ctx context.Context, entity string) (*models.BusinessHours, error) {
+	var hours models.BusinessHours
+
+	found, err := db.q.From(TableHours).
+		Where(goqu.Ex{
+			"entity": entity,
+		}).
+		Executor().ScanStructContext(ctx, &hours)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return &hours, nil
+}
+
+func (db *Database) RemoveHours(ctx context.Context, entity ...string) error {
+	_, err := db.q.Delete(TableHours).
+		Where(goqu.Ex{
+			"entity": goqu.Op{"in": entity},
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////
+// Weekend
+// /////////////////////////////////////////////////////////////
+
+// AddWeekends adds the weekend patterns, existing effective days of the entities are replaced.
+func (db *Database) AddWeekends(ctx context.Context, weekends []models.Weekend) error {
+	updatedAt := types.Time{Time: time.Now()}
+
+	for i := range weekends {
+		weekends[i].UpdatedAt = updatedAt
+	}
+
+	_, err := db.q.Insert(TableWeekends).
+		Rows(weekends).
+		OnConflict(goqu.DoUpdate("entity, effective_from", goqu.Record{
+			"days":       goqu.L("EXCLUDED.days"),
+			"updated_at": goqu.L("EXCLUDED.updated_at"),
+			"updated_by": goqu.L("EXCLUDED.updated_by"),
+		})).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *Database) RemoveWeekends(ctx context.Context, q *query.Query) error {
+	_, err := db.q.Delete(TableWeekends).
+		Where(adaptergoqu.Expression(db.query(q))...).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *Database) GetWeekendsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := adaptergoqu.Select(db.query(q), db.q.From(TableWeekends)).CountContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+func (db *Database) GetWeekends(ctx context.Context, q *query.Query) ([]models.Weekend, error) {
+	var weekends []models.Weekend
+
+	if err := adaptergoqu.Select(db.query(q), db.q.From(TableWeekends)).Executor().ScanStructsContext(ctx, &weekends); err != nil {
+		return nil, err
+	}
+
+	return weekends, nil
+}
+
+// GetEntityWeekends returns the weekend history of the entity ordered by the effective day.
+func (db *Database) GetEntityWeekends(ctx context.Context, entity string) ([]models.Weekend, error) {
+	var weekends []models.Weekend
+
+	if err := db.q.From(TableWeekends).
+		Where(goqu.Ex{
+			"entity": entity,
+		}).
+		Order(goqu.I("effective_from").Asc()).
+		Executor().ScanStructsContext(ctx, &weekends); err != nil {
+		return nil, err
+	}
+
+	return weekends, nil
+}
+
+// /////////////////////////////////////////////////////////////
+// Change-set
+// /////////////////////////////////////////////////////////////
+
+func (db *Database) AddChangeSet(ctx context.Context, changeSet *models.ChangeSet) error {
+	if changeSet.ID == "" {
+		changeSet.ID = ulid.Make().String()
+	}
+
+	changeSet.CreatedAt = types.Time{Time: time.Now()}
+	changeSet.UpdatedAt = changeSet.CreatedAt
+	changeSet.UpdatedBy = changeSet.CreatedBy
+	changeSet.Changes.Valid = true
+
+	_, err := db.q.Insert(TableChangeSets).
+		Rows(changeSet).
+		Executor().ExecContext(ctx)
+
+	return err
+}
+
+func (db *Database) GetChangeSetsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := adaptergoqu.Select(db.query(q), db.q.From(TableChangeSets)).CountContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+// GetChangeSets returns the change-sets, the latest created is first without a sort.
+func (db *Database) GetChangeSets(ctx context.Context, q *query.Query) ([]models.ChangeSet, error) {
+	var changeSets []models.ChangeSet
+
+	selectDataSet := adaptergoqu.Select(db.query(q), db.q.From(TableChangeSets))
+	if len(q.Sort) == 0 {
+		selectDataSet = selectDataSet.Order(goqu.I("created_at").Desc(), goqu.I("id").Desc())
+	}
+
+	if err := selectDataSet.Executor().ScanStructsContext(ctx, &changeSets); err != nil {
+		return nil, err
+	}
+
+	return changeSets, nil
+}
+
+// GetDueChangeSets returns the approved change-sets with an effective time at or before the time, the earliest is first.
+func (db *Database) GetDueChangeSets(ctx context.Context, at time.Time) ([]models.ChangeSet, error) {
+	var changeSets []models.ChangeSet
+
+	if err := db.q.From(TableChangeSets).
+		Where(
+			goqu.Ex{"status": domain.ChangeSetStatusApproved},
+			goqu.C("effective_at").Lte(at),
+		).
+		Order(goqu.I("effective_at").Asc(), goqu.I("id").Asc()).
+		Executor().ScanStructsContext(ctx, &changeSets); err != nil {
+		return nil, err
+	}
+
+	return changeSets, nil
+}
+
+func (db *Database) GetChangeSet(ctx context.Context, id string) (*models.ChangeSet, error) {
+	var changeSet models.ChangeSet
+
+	found, err := db.q.From(TableChangeSets).
+		Where(goqu.Ex{
+			"id": id,
+		}).
+		Executor().ScanStructContext(ctx, &changeSet)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return &changeSet, nil
+}
+
+// UpdateChangeSet replaces the change-set when its stored status is the status.
+// Concurrent approvals or applications change it only once, the others get ErrChangeSetStatus.
+func (db *Database) UpdateChangeSet(ctx context.Context, id string, status string, changeSet *models.ChangeSet) error {
+	changeSet.UpdatedAt = types.Time{Time: time.Now()}
+	changeSet.Changes.Valid = true
+
+	result, err := db.q.Update(TableChangeSets).
+		Set(changeSet).
+		Where(goqu.Ex{
+			"id":     id,
+			"status": status,
+		}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.changeSetResult(ctx, id, result)
+}
+
+// RemoveChangeSet removes the change-set when it is not applied yet.
+func (db *Database) RemoveChangeSet(ctx context.Context, id string) error {
+	result, err := db.q.Delete(TableChangeSets).
+		Where(
+			goqu.Ex{"id": id},
+			goqu.C("status").Neq(domain.ChangeSetStatusApplied),
+		).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.changeSetResult(ctx, id, result)
+}
+
+// changeSetResult returns the reason of a change-set write without a row.
+func (db *Database) changeSetResult(ctx context.Context, id string, result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return nil
+	}
+
+	changeSet, err := db.GetChangeSet(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if changeSet == nil {
+		return fmt.Errorf("%w: %s", domain.ErrChangeSetNotFound, id)
+	}
+
+	return fmt.Errorf("%w: %s is %s", domain.ErrChangeSetStatus, id, changeSet.Status)
+}
+
+// ApplyChanges applies the changes in one transaction, a failing change rolls back all of them.
+// Removing a missing event or relation is skipped like the removals of the API.
+func (db *Database) ApplyChanges(ctx context.Context, changes models.Changes, appliedBy string) error {
+	return db.transaction(ctx, func(tx *goqu.TxDatabase) error {
+		bound := &Database{q: tx, dialect: db.dialect, conn: db.conn, tx: tx}
+
+		if err := bound.RemoveRelations(ctx, changes.RemoveRelations, appliedBy); err != nil {
+			return err
+		}
+
+		if len(changes.RemoveEvents) > 0 {
+			if err := bound.RemoveEvent(ctx, appliedBy, changes.RemoveEvents...); err != nil {
+				return err
+			}
+		}
+
+		if len(changes.AddEvents) > 0 {
+			events := slices.Clone(changes.AddEvents)
+			for i := range events {
+				events[i].UpdatedBy = appliedBy
+			}
+
+			if err := bound.AddEvents(ctx, events); err != nil {
+				return err
+			}
+		}
+
+		for _, event := range changes.UpdateEvents {
+			if event.Version == 0 {
+				stored, err := bound.GetEvent(ctx, event.ID)
+				if err != nil {
+					return err
+				}
+
+				if stored == nil {
+					return fmt.Errorf("%w: %s", domain.ErrEventNotFound, event.ID)
+				}
+
+				event.Version = stored.Version
+			}
+
+			event.UpdatedBy = appliedBy
+			if err := bound.UpdateEvent(ctx, event.ID, &event); err != nil {
+				return err
+			}
+		}
+
+		if len(changes.AddRelations) > 0 {
+			relations := slices.Clone(changes.AddRelations)
+			for i := range relations {
+				relations[i].UpdatedBy = appliedBy
+			}
+
+			if err := bound.AddRelations(ctx, relations); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
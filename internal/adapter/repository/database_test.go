@@ -1,10 +1,16 @@
 package repository
 
 import (
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/suite"
+	"github.com/worldline-go/calendar/internal/adapter/memory"
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/internal/core/port"
 	"github.com/worldline-go/calendar/pkg/models"
 	"github.com/worldline-go/query"
 	"github.com/worldline-go/test/container/containerpostgres"
@@ -14,12 +20,22 @@ import (
 var migrations = []string{
 	"migrations/01_events.sql",
 	"migrations/02_relations.sql",
+	"migrations/03_entity_hierarchy.sql",
+	"migrations/04_relation_occurrence.sql",
+	"migrations/05_joints.sql",
+	"migrations/06_business_hours.sql",
+	"migrations/07_weekends.sql",
+	"migrations/08_event_version.sql",
+	"migrations/09_event_history.sql",
+	"migrations/10_relation_history.sql",
+	"migrations/11_soft_delete.sql",
+	"migrations/12_change_sets.sql",
 }
 
 type DatabaseSuite struct {
 	suite.Suite
 	container *containerpostgres.Container
-	db        *Database
+	db        port.CalendarPort
 }
 
 func (s *DatabaseSuite) SetupSuite() {
@@ -37,6 +53,49 @@ func (s *DatabaseSuite) TearDownSuite() {
 	s.container.Stop(s.T())
 }
 
+// SQLiteSuite runs the tests of DatabaseSuite on a sqlite database.
+type SQLiteSuite struct {
+	DatabaseSuite
+	sqlite *sqlx.DB
+}
+
+func (s *SQLiteSuite) SetupSuite() {
+	db, err := sqlx.Connect("sqlite", "file:"+filepath.Join(s.T().TempDir(), "calendar.db")+"?_pragma=foreign_keys(1)")
+	s.Require().NoError(err)
+
+	db.SetMaxOpenConns(1)
+	s.Require().NoError(migrateSQLite(s.T().Context(), db))
+
+	s.sqlite = db
+	s.db = newDB(db, SQLiteSchema)
+}
+
+func TestDatabaseSQLite(t *testing.T) {
+	suite.Run(t, new(SQLiteSuite))
+}
+
+func (s *SQLiteSuite) TearDownSuite() {
+	s.Require().NoError(s.sqlite.Close())
+}
+
+// MemorySuite runs the tests of DatabaseSuite on the in-memory calendar, it must behave like the databases.
+type MemorySuite struct {
+	DatabaseSuite
+}
+
+func (s *MemorySuite) SetupSuite() {
+	db, err := memory.New("")
+	s.Require().NoError(err)
+
+	s.db = db
+}
+
+func TestDatabaseMemory(t *testing.T) {
+	suite.Run(t, new(MemorySuite))
+}
+
+func (s *MemorySuite) TearDownSuite() {}
+
 func (s *DatabaseSuite) TestAddEvents() {
 	events := []models.Event{
 		{
@@ -68,11 +127,11 @@ func (s *DatabaseSuite) TestAddEvents() {
 	s.Require().Equal(events[0].DateTo.Local(), result[0].DateTo.Local(), "DateTo wrong")
 	s.Require().Equal(events[0].RRule, result[0].RRule)
 	s.Require().Equal(events[0].Disabled, result[0].Disabled)
-	s.Require().Equal(events[0].UpdatedAt.Truncate(time.Millisecond), result[0].UpdatedAt.Truncate(time.Millisecond), "UpdatedAt wrong")
+	s.Require().Equal(events[0].UpdatedAt.Local().Truncate(time.Millisecond), result[0].UpdatedAt.Local().Truncate(time.Millisecond), "UpdatedAt wrong")
 	s.Require().Equal(events[0].UpdatedBy, result[0].UpdatedBy)
 
 	// remove events
-	err = s.db.RemoveEvent(s.T().Context(), events[0].ID)
+	err = s.db.RemoveEvent(s.T().Context(), "tester", events[0].ID)
 	s.Require().NoError(err)
 	// check if removed
 	parse, err = query.Parse("", query.WithExpressionCmp("id", query.ExpressionCmp{
@@ -125,7 +184,121 @@ func (s *DatabaseSuite) TestUpdateEvent() {
 	s.Require().Equal("Updated Description", got.Description)
 
 	// Cleanup
-	_ = s.db.RemoveEvent(s.T().Context(), eventID)
+	_ = s.db.RemoveEvent(s.T().Context(), "tester", eventID)
+}
+
+func (s *DatabaseSuite) TestEventVersion() {
+	event := models.Event{
+		Name:     "Versioned Event",
+		DateFrom: types.Time{Time: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+		DateTo:   types.Time{Time: time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), []models.Event{event}))
+
+	parse, err := query.Parse("", query.WithExpressionCmp("name", query.ExpressionCmp{
+		Operator: query.OperatorEq,
+		Field:    "name",
+		Value:    event.Name,
+	}))
+	s.Require().NoError(err)
+	result, err := s.db.GetEvents(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Len(result, 1)
+	s.Require().Equal(int64(1), result[0].Version)
+
+	// update with the stored version increases it
+	updated := result[0]
+	updated.Name = "Versioned Event 2"
+	s.Require().NoError(s.db.UpdateEvent(s.T().Context(), updated.ID, &updated))
+	s.Require().Equal(int64(2), updated.Version)
+
+	// stale version doesn't change the event
+	stale := result[0]
+	stale.Name = "Stale"
+	err = s.db.UpdateEvent(s.T().Context(), stale.ID, &stale)
+	s.Require().ErrorIs(err, domain.ErrEventVersion)
+	s.Require().Equal(int64(1), stale.Version)
+
+	got, err := s.db.GetEvent(s.T().Context(), updated.ID)
+	s.Require().NoError(err)
+	s.Require().Equal("Versioned Event 2", got.Name)
+	s.Require().Equal(int64(2), got.Version)
+
+	err = s.db.UpdateEvent(s.T().Context(), "non-existent-id", &stale)
+	s.Require().ErrorIs(err, domain.ErrEventNotFound)
+
+	// remove only with the stored version
+	s.Require().ErrorIs(s.db.RemoveEventVersion(s.T().Context(), updated.ID, 1, "tester"), domain.ErrEventVersion)
+	s.Require().NoError(s.db.RemoveEventVersion(s.T().Context(), updated.ID, 2, "tester"))
+	s.Require().ErrorIs(s.db.RemoveEventVersion(s.T().Context(), updated.ID, 2, "tester"), domain.ErrEventNotFound)
+}
+
+func (s *DatabaseSuite) TestEventHistory() {
+	events := []models.Event{{
+		Name:      "History Event",
+		DateFrom:  types.Time{Time: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)},
+		DateTo:    types.Time{Time: time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC)},
+		UpdatedBy: "creator",
+	}}
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), events))
+	id := events[0].ID
+
+	updated := events[0]
+	updated.Name = "History Event 2"
+	updated.UpdatedBy = "editor"
+	s.Require().NoError(s.db.UpdateEvent(s.T().Context(), id, &updated))
+
+	// a failed update is not recorded
+	stale := events[0]
+	s.Require().ErrorIs(s.db.UpdateEvent(s.T().Context(), id, &stale), domain.ErrEventVersion)
+
+	s.Require().NoError(s.db.RemoveEventVersion(s.T().Context(), id, updated.Version, "remover"))
+
+	parse, err := query.Parse("event_id=" + id)
+	s.Require().NoError(err)
+
+	history, err := s.db.GetHistory(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Len(history, 3)
+
+	// latest change is first
+	s.Require().Equal(models.HistoryActionDelete, history[0].Action)
+	s.Require().Equal("remover", history[0].ChangedBy)
+	s.Require().Equal(int64(2), history[0].Version)
+	s.Require().False(history[0].After.Valid)
+	s.Require().Equal("History Event 2", history[0].Before.V.Name)
+
+	s.Require().Equal(models.HistoryActionUpdate, history[1].Action)
+	s.Require().Equal("editor", history[1].ChangedBy)
+	s.Require().Equal("History Event", history[1].Before.V.Name)
+	s.Require().Equal("History Event 2", history[1].After.V.Name)
+	s.Require().Equal(int64(2), history[1].After.V.Version)
+
+	s.Require().Equal(models.HistoryActionInsert, history[2].Action)
+	s.Require().Equal("creator", history[2].ChangedBy)
+	s.Require().False(history[2].Before.Valid)
+	s.Require().Equal(int64(1), history[2].Version)
+
+	// audit of a user in a time range
+	parse, err = query.Parse("changed_by=editor&changed_at[gte]=" + history[2].ChangedAt.UTC().Format(time.RFC3339Nano))
+	s.Require().NoError(err)
+
+	count, err := s.db.GetHistoryCount(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(1), count)
+
+	// bulk removal records only the removed events
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), events))
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "bulk", id, "non-existent-id"))
+
+	parse, err = query.Parse("changed_by=bulk")
+	s.Require().NoError(err)
+
+	history, err = s.db.GetHistory(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Len(history, 1)
+	s.Require().Equal(id, history[0].EventID)
+	s.Require().Equal(models.HistoryActionDelete, history[0].Action)
 }
 
 func (s *DatabaseSuite) TestGetEventNotFound() {
@@ -164,13 +337,13 @@ func (s *DatabaseSuite) TestAddMultipleEvents() {
 		s.Require().NoError(err)
 		s.Require().Len(result, 1)
 		// Cleanup
-		_ = s.db.RemoveEvent(s.T().Context(), result[0].ID)
+		_ = s.db.RemoveEvent(s.T().Context(), "tester", result[0].ID)
 	}
 }
 
 func (s *DatabaseSuite) TestRemoveEventNotFound() {
 	// Should not error even if event does not exist
-	err := s.db.RemoveEvent(s.T().Context(), "non-existent-id")
+	err := s.db.RemoveEvent(s.T().Context(), "tester", "non-existent-id")
 	s.Require().NoError(err)
 }
 
@@ -211,5 +384,685 @@ func (s *DatabaseSuite) TestAddEventWithAllFields() {
 	s.Require().Equal(event.UpdatedBy, got.UpdatedBy)
 
 	// Cleanup
-	_ = s.db.RemoveEvent(s.T().Context(), got.ID)
+	_ = s.db.RemoveEvent(s.T().Context(), "tester", got.ID)
+}
+
+func (s *DatabaseSuite) TestEntityHierarchy() {
+	events := []models.Event{
+		{
+			ID:         "hierarchy-country",
+			Name:       "Country Day",
+			EventGroup: types.NewNull("hierarchy-country-group"),
+			DateFrom:   types.Time{Time: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+			DateTo:     types.Time{Time: time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			ID:         "hierarchy-liberation",
+			Name:       "Liberation Day",
+			EventGroup: types.NewNull("hierarchy-country-group"),
+			DateFrom:   types.Time{Time: time.Date(2023, 5, 5, 0, 0, 0, 0, time.UTC)},
+			DateTo:     types.Time{Time: time.Date(2023, 5, 6, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			ID:       "hierarchy-region",
+			Name:     "Region Day",
+			DateFrom: types.Time{Time: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+			DateTo:   types.Time{Time: time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			ID:       "hierarchy-branch",
+			Name:     "Branch Day",
+			DateFrom: types.Time{Time: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)},
+			DateTo:   types.Time{Time: time.Date(2023, 7, 2, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), events))
+
+	relations := []models.Relation{
+		{Entity: "h-country", Type: models.RelationTypeInclude, EventGroup: types.NewNull("hierarchy-country-group")},
+		{Entity: "h-region", Type: models.RelationTypeInclude, EventID: types.NewNull("hierarchy-region")},
+		{Entity: "h-region", Type: models.RelationTypeParent, Parent: types.NewNull("h-country")},
+		{Entity: "h-branch", Type: models.RelationTypeInclude, EventID: types.NewNull("hierarchy-branch")},
+		{Entity: "h-branch", Type: models.RelationTypeParent, Parent: types.NewNull("h-region")},
+		{Entity: "h-branch", Type: models.RelationTypeExclude, EventID: types.NewNull("hierarchy-liberation")},
+		// cycle, should not loop forever
+		{Entity: "h-country", Type: models.RelationTypeParent, Parent: types.NewNull("h-branch")},
+	}
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), relations))
+
+	// relations with empty fields are added once
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), relations))
+
+	entities, err := query.Parse("entity=h-country,h-region,h-branch")
+	s.Require().NoError(err)
+
+	stored, err := s.db.GetRelations(s.T().Context(), entities)
+	s.Require().NoError(err)
+	s.Require().Len(stored, len(relations))
+
+	entityIDs := func(entity string) []string {
+		parse, err := query.Parse("", query.WithExpressionCmp("entity", query.ExpressionCmp{
+			Operator: query.OperatorEq,
+			Field:    "entity",
+			Value:    entity,
+		}))
+		s.Require().NoError(err)
+
+		result, err := s.db.GetEvents(s.T().Context(), parse)
+		s.Require().NoError(err)
+
+		ids := make([]string, 0, len(result))
+		for _, e := range result {
+			ids = append(ids, e.ID)
+		}
+
+		count, err := s.db.GetEventsCount(s.T().Context(), parse)
+		s.Require().NoError(err)
+		s.Require().Equal(uint64(len(ids)), count)
+
+		return ids
+	}
+
+	s.Require().ElementsMatch([]string{"hierarchy-country", "hierarchy-liberation", "hierarchy-region", "hierarchy-branch"}, entityIDs("h-country"))
+	s.Require().ElementsMatch([]string{"hierarchy-country", "hierarchy-liberation", "hierarchy-region", "hierarchy-branch"}, entityIDs("h-region"))
+	s.Require().ElementsMatch([]string{"hierarchy-country", "hierarchy-region", "hierarchy-branch"}, entityIDs("h-branch"))
+
+	// Cleanup
+	s.Require().NoError(s.db.RemoveRelation(s.T().Context(), entities, "tester"))
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "tester", "hierarchy-country", "hierarchy-liberation", "hierarchy-region", "hierarchy-branch"))
+}
+
+func (s *DatabaseSuite) TestOccurrenceExclusions() {
+	events := []models.Event{
+		{
+			ID:       "occurrence-kings-day",
+			Name:     "Kings Day",
+			DateFrom: types.Time{Time: time.Date(2023, 4, 27, 0, 0, 0, 0, time.UTC)},
+			DateTo:   types.Time{Time: time.Date(2023, 4, 28, 0, 0, 0, 0, time.UTC)},
+			RRule:    "FREQ=YEARLY;BYMONTH=4;BYMONTHDAY=27",
+		},
+	}
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), events))
+
+	occurrence := types.NewNull(types.Time{Time: time.Date(2025, 4, 27, 0, 0, 0, 0, time.UTC)})
+	relations := []models.Relation{
+		{Entity: "o-country", Type: models.RelationTypeInclude, EventID: types.NewNull("occurrence-kings-day")},
+		{Entity: "o-country", Type: models.RelationTypeExclude, EventID: types.NewNull("occurrence-kings-day"), Occurrence: occurrence},
+		{Entity: "o-branch", Type: models.RelationTypeParent, Parent: types.NewNull("o-country")},
+	}
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), relations))
+
+	// occurrence exclusion keeps the event in the entity calendar
+	parse, err := query.Parse("entity=o-branch")
+	s.Require().NoError(err)
+
+	result, err := s.db.GetEvents(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Len(result, 1)
+
+	exclusions, err := s.db.GetExclusions(s.T().Context(), []string{"o-branch"}, time.Time{})
+	s.Require().NoError(err)
+	s.Require().Len(exclusions, 1)
+	s.Require().Equal("occurrence-kings-day", exclusions[0].EventID.V)
+	s.Require().True(exclusions[0].Occurrence.Valid)
+	s.Require().Equal(occurrence.V.Format(time.DateOnly), exclusions[0].Occurrence.V.UTC().Format(time.DateOnly))
+
+	exclusions, err = s.db.GetExclusions(s.T().Context(), []string{"o-other"}, time.Time{})
+	s.Require().NoError(err)
+	s.Require().Empty(exclusions)
+
+	// Cleanup
+	parse, err = query.Parse("entity=o-country,o-branch")
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.RemoveRelation(s.T().Context(), parse, "tester"))
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "tester", "occurrence-kings-day"))
+}
+
+func (s *DatabaseSuite) TestAsOf() {
+	events := []models.Event{{
+		ID:       "asof-kings-day",
+		Name:     "Kings Day",
+		DateFrom: types.Time{Time: time.Date(2025, 4, 26, 0, 0, 0, 0, time.UTC)},
+		DateTo:   types.Time{Time: time.Date(2025, 4, 27, 0, 0, 0, 0, time.UTC)},
+		RRule:    "RRULE:FREQ=YEARLY",
+	}}
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), events))
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), []models.Relation{
+		{Entity: "asof-country", Type: models.RelationTypeInclude, EventID: types.NewNull("asof-kings-day")},
+		{Entity: "asof-branch", Type: models.RelationTypeParent, Parent: types.NewNull("asof-country")},
+	}))
+
+	before := time.Now()
+
+	// rename, exclude an occurrence and unlink the branch after the time
+	updated := events[0]
+	updated.Name = "King's Day"
+	s.Require().NoError(s.db.UpdateEvent(s.T().Context(), updated.ID, &updated))
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), []models.Relation{
+		{Entity: "asof-country", Type: models.RelationTypeExclude, EventID: types.NewNull("asof-kings-day"), Occurrence: types.NewNull(types.Time{Time: time.Date(2026, 4, 26, 0, 0, 0, 0, time.UTC)})},
+	}))
+	parent, err := query.Parse("entity=asof-branch")
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.RemoveRelation(s.T().Context(), parent, "tester"))
+
+	asOf := "as_of=" + before.UTC().Format(time.RFC3339Nano)
+
+	q, err := query.Parse("entity=asof-branch&"+asOf, query.WithSkipExpressionCmp("as_of"))
+	s.Require().NoError(err)
+	result, err := s.db.GetEvents(s.T().Context(), q)
+	s.Require().NoError(err)
+	s.Require().Len(result, 1)
+	s.Require().Equal("Kings Day", result[0].Name)
+	s.Require().Equal(int64(1), result[0].Version)
+
+	q, err = query.Parse("entity=asof-branch")
+	s.Require().NoError(err)
+	result, err = s.db.GetEvents(s.T().Context(), q)
+	s.Require().NoError(err)
+	s.Require().Empty(result)
+
+	exclusions, err := s.db.GetExclusions(s.T().Context(), []string{"asof-country"}, before)
+	s.Require().NoError(err)
+	s.Require().Empty(exclusions)
+
+	exclusions, err = s.db.GetExclusions(s.T().Context(), []string{"asof-country"}, time.Now())
+	s.Require().NoError(err)
+	s.Require().Len(exclusions, 1)
+
+	// a removed event is still resolved before its removal
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "tester", "asof-kings-day"))
+
+	q, err = query.Parse("entity=asof-country&"+asOf, query.WithSkipExpressionCmp("as_of"))
+	s.Require().NoError(err)
+	result, err = s.db.GetEvents(s.T().Context(), q)
+	s.Require().NoError(err)
+	s.Require().Len(result, 1)
+
+	q, err = query.Parse("entity=asof-country&as_of="+time.Now().UTC().Format(time.RFC3339Nano), query.WithSkipExpressionCmp("as_of"))
+	s.Require().NoError(err)
+	result, err = s.db.GetEvents(s.T().Context(), q)
+	s.Require().NoError(err)
+	s.Require().Empty(result)
+
+	// an invalid time is not the current calendar
+	q, err = query.Parse("entity=asof-country&as_of=yesterday", query.WithSkipExpressionCmp("as_of"))
+	s.Require().NoError(err)
+	_, err = s.db.GetEvents(s.T().Context(), q)
+	s.Require().ErrorIs(err, domain.ErrAsOf)
+}
+
+func (s *DatabaseSuite) TestTrash() {
+	events := []models.Event{{
+		ID:       "trash-kings-day",
+		Name:     "Kings Day",
+		DateFrom: types.Time{Time: time.Date(2025, 4, 26, 0, 0, 0, 0, time.UTC)},
+		DateTo:   types.Time{Time: time.Date(2025, 4, 27, 0, 0, 0, 0, time.UTC)},
+	}}
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), events))
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), []models.Relation{
+		{Entity: "trash-country", Type: models.RelationTypeInclude, EventID: types.NewNull("trash-kings-day")},
+		{Entity: "trash-country", Type: models.RelationTypeInclude, EventGroup: types.NewNull("trash-group")},
+	}))
+
+	entity, err := query.Parse("entity=trash-country")
+	s.Require().NoError(err)
+
+	before, err := s.db.GetModified(s.T().Context(), entity, nil)
+	s.Require().NoError(err)
+
+	// the event moves to the trash with its relations, the group relation stays
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "remover", "trash-kings-day"))
+
+	// the removal is the last change of the calendar
+	removed, err := s.db.GetModified(s.T().Context(), entity, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(before.Events-1, removed.Events)
+	s.Require().True(removed.UpdatedAt.After(before.UpdatedAt.Time))
+
+	got, err := s.db.GetEvent(s.T().Context(), "trash-kings-day")
+	s.Require().NoError(err)
+	s.Require().Nil(got)
+	s.Require().ErrorIs(s.db.UpdateEvent(s.T().Context(), "trash-kings-day", &events[0]), domain.ErrEventNotFound)
+
+	relations, err := s.db.GetRelations(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Len(relations, 1)
+	s.Require().Equal("trash-group", relations[0].EventGroup.V)
+
+	id, err := query.Parse("id=trash-kings-day")
+	s.Require().NoError(err)
+	trashed, err := s.db.GetTrashEvents(s.T().Context(), id)
+	s.Require().NoError(err)
+	s.Require().Len(trashed, 1)
+	s.Require().Equal("remover", trashed[0].DeletedBy.V)
+
+	trashedRelations, err := s.db.GetTrashRelations(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Len(trashedRelations, 1)
+	s.Require().True(trashed[0].DeletedAt.V.Equal(trashedRelations[0].DeletedAt.V.Time))
+
+	// relations of an event in the trash are restored only with the event
+	s.Require().NoError(s.db.RestoreRelations(s.T().Context(), entity, "restorer"))
+	count, err := s.db.GetTrashRelationsCount(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(1), count)
+
+	s.Require().NoError(s.db.RestoreEvent(s.T().Context(), "trash-kings-day", "restorer"))
+	s.Require().ErrorIs(s.db.RestoreEvent(s.T().Context(), "trash-kings-day", "restorer"), domain.ErrEventNotFound)
+
+	got, err = s.db.GetEvent(s.T().Context(), "trash-kings-day")
+	s.Require().NoError(err)
+	s.Require().NotNil(got)
+	s.Require().False(got.DeletedAt.Valid)
+
+	restored, err := s.db.GetModified(s.T().Context(), entity, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(before.Events, restored.Events)
+	s.Require().True(restored.UpdatedAt.After(removed.UpdatedAt.Time))
+
+	relations, err = s.db.GetRelations(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Len(relations, 2)
+
+	eventID, err := query.Parse("event_id=trash-kings-day")
+	s.Require().NoError(err)
+	history, err := s.db.GetHistory(s.T().Context(), eventID)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(history)
+	s.Require().Equal(models.HistoryActionRestore, history[0].Action)
+	s.Require().Equal("restorer", history[0].ChangedBy)
+
+	// a removed relation is restored alone
+	group, err := query.Parse("entity=trash-country&event_group=trash-group")
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.RemoveRelation(s.T().Context(), group, "remover"))
+	s.Require().NoError(s.db.RestoreRelations(s.T().Context(), group, "restorer"))
+
+	relations, err = s.db.GetRelations(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Len(relations, 2)
+
+	// adding a removed relation again replaces the one in the trash
+	s.Require().NoError(s.db.RemoveRelation(s.T().Context(), group, "remover"))
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), []models.Relation{
+		{Entity: "trash-country", Type: models.RelationTypeInclude, EventGroup: types.NewNull("trash-group")},
+	}))
+
+	relations, err = s.db.GetRelations(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Len(relations, 2)
+
+	count, err = s.db.GetTrashRelationsCount(s.T().Context(), group)
+	s.Require().NoError(err)
+	s.Require().Zero(count)
+
+	// the purge removes only the items older than the retention
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "remover", "trash-kings-day"))
+
+	purged, err := s.db.PurgeTrash(s.T().Context(), time.Now().Add(-time.Hour))
+	s.Require().NoError(err)
+	s.Require().Zero(purged)
+
+	purged, err = s.db.PurgeTrash(s.T().Context(), time.Now().Add(time.Second))
+	s.Require().NoError(err)
+	s.Require().GreaterOrEqual(purged, uint64(2))
+
+	count, err = s.db.GetTrashEventsCount(s.T().Context(), id)
+	s.Require().NoError(err)
+	s.Require().Zero(count)
+
+	history, err = s.db.GetHistory(s.T().Context(), eventID)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(history)
+
+	s.Require().NoError(s.db.RemoveRelation(s.T().Context(), entity, "tester"))
+}
+
+func (s *DatabaseSuite) TestChangeSets() {
+	changes := models.Changes{
+		AddEvents: []models.Event{{
+			ID:       "cs-liberation-day",
+			Name:     "Liberation Day",
+			Type:     models.EventTypeHoliday,
+			DateFrom: types.Time{Time: time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)},
+			DateTo:   types.Time{Time: time.Date(2025, 5, 6, 0, 0, 0, 0, time.UTC)},
+		}},
+		AddRelations: []models.Relation{
+			{Entity: "cs-country", Type: models.RelationTypeInclude, EventID: types.NewNull("cs-liberation-day")},
+		},
+	}
+
+	changeSet := &models.ChangeSet{
+		Name:        "liberation",
+		Status:      models.ChangeSetStatusDraft,
+		Changes:     types.NewJSON(changes),
+		EffectiveAt: types.NewNull(types.Time{Time: time.Now().Add(-time.Minute)}),
+		CreatedBy:   "author",
+	}
+	s.Require().NoError(s.db.AddChangeSet(s.T().Context(), changeSet))
+
+	stored, err := s.db.GetChangeSet(s.T().Context(), changeSet.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(stored)
+	s.Require().Equal("author", stored.UpdatedBy)
+	s.Require().Len(stored.Changes.V.AddEvents, 1)
+
+	// the status is compared on the update
+	stored.Status = models.ChangeSetStatusApproved
+	s.Require().ErrorIs(s.db.UpdateChangeSet(s.T().Context(), stored.ID, models.ChangeSetStatusApproved, stored), domain.ErrChangeSetStatus)
+	s.Require().ErrorIs(s.db.UpdateChangeSet(s.T().Context(), "cs-missing", models.ChangeSetStatusDraft, stored), domain.ErrChangeSetNotFound)
+	s.Require().NoError(s.db.UpdateChangeSet(s.T().Context(), stored.ID, models.ChangeSetStatusDraft, stored))
+
+	due, err := s.db.GetDueChangeSets(s.T().Context(), time.Now())
+	s.Require().NoError(err)
+	s.Require().True(slices.ContainsFunc(due, func(cs models.ChangeSet) bool { return cs.ID == stored.ID }))
+
+	// the preview is rolled back
+	s.Require().NoError(s.db.Preview(s.T().Context(), func(db port.CalendarPort) error {
+		if err := db.ApplyChanges(s.T().Context(), changes, "author"); err != nil {
+			return err
+		}
+
+		event, err := db.GetEvent(s.T().Context(), "cs-liberation-day")
+		s.Require().NoError(err)
+		s.Require().NotNil(event)
+
+		return nil
+	}))
+
+	event, err := s.db.GetEvent(s.T().Context(), "cs-liberation-day")
+	s.Require().NoError(err)
+	s.Require().Nil(event)
+
+	// a failing change rolls back the others
+	failing := changes
+	failing.UpdateEvents = []models.Event{{ID: "cs-missing", Name: "Missing"}}
+	s.Require().ErrorIs(s.db.ApplyChanges(s.T().Context(), failing, "author"), domain.ErrEventNotFound)
+
+	event, err = s.db.GetEvent(s.T().Context(), "cs-liberation-day")
+	s.Require().NoError(err)
+	s.Require().Nil(event)
+
+	s.Require().NoError(s.db.ApplyChanges(s.T().Context(), changes, "author"))
+
+	event, err = s.db.GetEvent(s.T().Context(), "cs-liberation-day")
+	s.Require().NoError(err)
+	s.Require().NotNil(event)
+	s.Require().Equal("author", event.UpdatedBy)
+
+	entity, err := query.Parse("entity=cs-country")
+	s.Require().NoError(err)
+	relations, err := s.db.GetRelations(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Len(relations, 1)
+
+	stored.Status = models.ChangeSetStatusApplied
+	s.Require().NoError(s.db.UpdateChangeSet(s.T().Context(), stored.ID, models.ChangeSetStatusApproved, stored))
+	s.Require().ErrorIs(s.db.RemoveChangeSet(s.T().Context(), stored.ID), domain.ErrChangeSetStatus)
+
+	status, err := query.Parse("status=applied&created_by=author")
+	s.Require().NoError(err)
+	count, err := s.db.GetChangeSetsCount(s.T().Context(), status)
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(1), count)
+
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "tester", "cs-liberation-day"))
+}
+
+func (s *DatabaseSuite) TestTransaction() {
+	relations := []models.Relation{
+		{Entity: "tx-country", Type: models.RelationTypeInclude, EventGroup: types.NewNull("tx-group")},
+		{Entity: "tx-country", Type: models.RelationTypeInclude, EventGroup: types.NewNull("tx-other")},
+	}
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), relations))
+
+	entity, err := query.Parse("entity=tx-country")
+	s.Require().NoError(err)
+
+	// an error of the transaction rolls back its changes
+	err = s.db.Transaction(s.T().Context(), func(db port.CalendarPort) error {
+		if err := db.AddEvents(s.T().Context(), []models.Event{{
+			ID:       "tx-event",
+			Name:     "Transaction",
+			DateFrom: types.Time{Time: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+			DateTo:   types.Time{Time: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)},
+		}}); err != nil {
+			return err
+		}
+
+		if err := db.RemoveRelations(s.T().Context(), relations[:1], "tester"); err != nil {
+			return err
+		}
+
+		return db.RemoveEventVersion(s.T().Context(), "tx-missing", 1, "tester")
+	})
+	s.Require().ErrorIs(err, domain.ErrEventNotFound)
+
+	event, err := s.db.GetEvent(s.T().Context(), "tx-event")
+	s.Require().NoError(err)
+	s.Require().Nil(event)
+
+	stored, err := s.db.GetRelations(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Len(stored, 2)
+
+	// relations are removed only with all of their fields matching
+	s.Require().NoError(s.db.RemoveRelations(s.T().Context(), []models.Relation{
+		relations[0],
+		{Entity: "tx-country", Type: models.RelationTypeExclude, EventGroup: types.NewNull("tx-other")},
+	}, "tester"))
+
+	stored, err = s.db.GetRelations(s.T().Context(), entity)
+	s.Require().NoError(err)
+	s.Require().Len(stored, 1)
+	s.Require().Equal("tx-other", stored[0].EventGroup.V)
+
+	s.Require().NoError(s.db.RemoveRelation(s.T().Context(), entity, "tester"))
+}
+
+func (s *DatabaseSuite) TestUpdateRelations() {
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), []models.Event{{
+		ID:       "u-event",
+		Name:     "Kings Day",
+		DateFrom: types.Time{Time: time.Date(2025, 4, 27, 0, 0, 0, 0, time.UTC)},
+		DateTo:   types.Time{Time: time.Date(2025, 4, 28, 0, 0, 0, 0, time.UTC)},
+		RRule:    "RRULE:FREQ=YEARLY",
+	}}))
+
+	occurrence := types.NewNull(types.Time{Time: time.Date(2025, 4, 27, 0, 0, 0, 0, time.UTC)})
+	relations := []models.Relation{
+		{Entity: "u-country", Type: models.RelationTypeInclude, EventGroup: types.NewNull("u-group")},
+		{Entity: "u-country", Type: models.RelationTypeExclude, EventID: types.NewNull("u-event"), Occurrence: occurrence},
+	}
+	s.Require().NoError(s.db.AddRelations(s.T().Context(), relations))
+
+	parse, err := query.Parse("entity=u-country&sort=type")
+	s.Require().NoError(err)
+
+	stored, err := s.db.GetRelations(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Len(stored, 2)
+
+	// fields are matched with the null ones and the occurrence day
+	updates := make([]models.RelationUpdate, 0, len(stored))
+	for _, r := range stored {
+		to := r
+		if to.EventGroup.Valid {
+			to.EventGroup = types.NewNull("u-group-new")
+		} else {
+			to.Occurrence = types.NewNull(types.Time{Time: time.Date(2026, 4, 27, 0, 0, 0, 0, time.UTC)})
+		}
+
+		updates = append(updates, models.RelationUpdate{From: r, To: to})
+	}
+	s.Require().NoError(s.db.UpdateRelations(s.T().Context(), updates))
+
+	result, err := s.db.GetRelations(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Len(result, 2)
+	s.Require().Equal("2026-04-27", result[0].Occurrence.V.UTC().Format(time.DateOnly))
+	s.Require().Equal("u-group-new", result[1].EventGroup.V)
+
+	// a missing relation rolls back all updates
+	updates[0].From, updates[0].To = result[0], result[0]
+	updates[0].To.Occurrence = types.NewNull(types.Time{Time: time.Date(2027, 4, 27, 0, 0, 0, 0, time.UTC)})
+	err = s.db.UpdateRelations(s.T().Context(), updates)
+	s.Require().ErrorIs(err, domain.ErrRelationNotFound)
+
+	result, err = s.db.GetRelations(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Equal("2026-04-27", result[0].Occurrence.V.UTC().Format(time.DateOnly))
+
+	// Cleanup
+	s.Require().NoError(s.db.RemoveRelation(s.T().Context(), parse, "tester"))
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "tester", "u-event"))
+}
+
+func (s *DatabaseSuite) TestJoints() {
+	joints := []models.Joint{
+		{
+			Name:     "nld-target2",
+			Entities: types.Slice[string]{"NLD", "TARGET2"},
+			Mode:     models.JointModeUnion,
+		},
+	}
+	s.Require().NoError(s.db.AddJoints(s.T().Context(), joints))
+
+	joint, err := s.db.GetJoint(s.T().Context(), "nld-target2")
+	s.Require().NoError(err)
+	s.Require().NotNil(joint)
+	s.Require().Equal([]string{"NLD", "TARGET2"}, []string(joint.Entities))
+	s.Require().Equal(models.JointModeUnion, joint.Mode)
+
+	joint.Mode = models.JointModeIntersection
+	joint.Description = "open in any"
+	s.Require().NoError(s.db.UpdateJoint(s.T().Context(), "nld-target2", joint))
+
+	parse, err := query.Parse("mode=intersection")
+	s.Require().NoError(err)
+
+	result, err := s.db.GetJoints(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Len(result, 1)
+	s.Require().Equal("open in any", result[0].Description)
+
+	count, err := s.db.GetJointsCount(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(1), count)
+
+	s.Require().NoError(s.db.RemoveJoint(s.T().Context(), "nld-target2"))
+
+	joint, err = s.db.GetJoint(s.T().Context(), "nld-target2")
+	s.Require().NoError(err)
+	s.Require().Nil(joint)
+}
+
+func (s *DatabaseSuite) TestBusinessHours() {
+	hours := &models.BusinessHours{
+		Entity: "hours-desk",
+		Tz:     "Europe/Amsterdam",
+		Hours: types.Map[[]models.Window]{
+			"monday": {{From: "09:00", To: "12:00"}, {From: "13:00", To: "17:00"}},
+		},
+	}
+	s.Require().NoError(s.db.SetHours(s.T().Context(), hours))
+
+	got, err := s.db.GetEntityHours(s.T().Context(), "hours-desk")
+	s.Require().NoError(err)
+	s.Require().NotNil(got)
+	s.Require().Equal("Europe/Amsterdam", got.Tz)
+	s.Require().Equal(hours.Hours, got.Hours)
+
+	// set again replaces the hours
+	hours.Hours = types.Map[[]models.Window]{"friday": {{From: "09:00", To: "13:00"}}}
+	s.Require().NoError(s.db.SetHours(s.T().Context(), hours))
+
+	got, err = s.db.GetEntityHours(s.T().Context(), "hours-desk")
+	s.Require().NoError(err)
+	s.Require().Equal(hours.Hours, got.Hours)
+
+	s.Require().NoError(s.db.RemoveHours(s.T().Context(), "hours-desk"))
+
+	got, err = s.db.GetEntityHours(s.T().Context(), "hours-desk")
+	s.Require().NoError(err)
+	s.Require().Nil(got)
+}
+
+func (s *DatabaseSuite) TestEventType() {
+	events := []models.Event{
+		{
+			ID:       "type-default",
+			Name:     "Default Type",
+			DateFrom: types.Time{Time: time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC)},
+			DateTo:   types.Time{Time: time.Date(2023, 12, 26, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			ID:       "type-half-day",
+			Name:     "Christmas Eve",
+			Type:     models.EventTypeHalfDay,
+			DateFrom: types.Time{Time: time.Date(2023, 12, 24, 13, 0, 0, 0, time.UTC)},
+			DateTo:   types.Time{Time: time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	s.Require().NoError(s.db.AddEvents(s.T().Context(), events))
+
+	result, err := s.db.GetEvent(s.T().Context(), "type-default")
+	s.Require().NoError(err)
+	s.Require().Equal(models.EventTypeHoliday, result.Type)
+
+	parse, err := query.Parse("type=half-day")
+	s.Require().NoError(err)
+
+	list, err := s.db.GetEvents(s.T().Context(), parse)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Require().Equal("type-half-day", list[0].ID)
+
+	// Cleanup
+	s.Require().NoError(s.db.RemoveEvent(s.T().Context(), "tester", "type-default", "type-half-day"))
+}
+
+func (s *DatabaseSuite) TestWeekends() {
+	weekends := []models.Weekend{
+		{
+			Entity:        "weekend-are",
+			EffectiveFrom: types.Time{Time: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+			Days:          types.Slice[string]{"saturday", "sunday"},
+		},
+		{
+			Entity:        "weekend-are",
+			EffectiveFrom: types.Time{Time: time.Date(2006, 9, 1, 0, 0, 0, 0, time.UTC)},
+			Days:          types.Slice[string]{"friday", "saturday"},
+		},
+	}
+	s.Require().NoError(s.db.AddWeekends(s.T().Context(), weekends))
+
+	result, err := s.db.GetEntityWeekends(s.T().Context(), "weekend-are")
+	s.Require().NoError(err)
+	s.Require().Len(result, 2)
+	s.Require().Equal([]string{"friday", "saturday"}, []string(result[0].Days))
+	s.Require().Equal([]string{"saturday", "sunday"}, []string(result[1].Days))
+
+	// same effective day replaces the days
+	s.Require().NoError(s.db.AddWeekends(s.T().Context(), []models.Weekend{
+		{
+			Entity:        "weekend-are",
+			EffectiveFrom: types.Time{Time: time.Date(2006, 9, 1, 0, 0, 0, 0, time.UTC)},
+			Days:          types.Slice[string]{"thursday", "friday"},
+		},
+	}))
+
+	result, err = s.db.GetEntityWeekends(s.T().Context(), "weekend-are")
+	s.Require().NoError(err)
+	s.Require().Len(result, 2)
+	s.Require().Equal([]string{"thursday", "friday"}, []string(result[0].Days))
+
+	// Cleanup
+	parse, err := query.Parse("entity=weekend-are")
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.RemoveWeekends(s.T().Context(), parse))
+
+	result, err = s.db.GetEntityWeekends(s.T().Context(), "weekend-are")
+	s.Require().NoError(err)
+	s.Require().Empty(result)
 }
@@ -5,6 +5,8 @@ import (
 	"embed"
 	"fmt"
 	"io/fs"
+	"strconv"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
@@ -16,6 +18,9 @@ import (
 //go:embed migrations/*
 var migrationFS embed.FS
 
+//go:embed migrations_sqlite/*.sql
+var migrationSQLiteFS embed.FS
+
 func MigrateDB(ctx context.Context, cfg *config.Config) error {
 	if cfg.Migrate.DBDatasource == "" {
 		return fmt.Errorf("migrate database datasource is empty")
@@ -33,6 +38,10 @@ func MigrateDB(ctx context.Context, cfg *config.Config) error {
 
 	defer db.Close()
 
+	if IsSQLite(cfg.Migrate.DBType) {
+		return migrateSQLite(ctx, db)
+	}
+
 	result, err := igmigrator.Migrate(ctx, db, &igmigrator.Config{
 		Migrations:     migration,
 		Schema:         cfg.Migrate.DBSchema,
@@ -50,3 +59,61 @@ func MigrateDB(ctx context.Context, cfg *config.Config) error {
 
 	return nil
 }
+
+// migrateSQLite runs the sqlite migrations not applied yet in order, each one in its own transaction.
+// The user_version of the database is the number of the last applied migration.
+func migrateSQLite(ctx context.Context, db *sqlx.DB) error {
+	var version int
+	if err := db.GetContext(ctx, &version, "PRAGMA user_version"); err != nil {
+		return fmt.Errorf("migrate database version: %w", err)
+	}
+
+	files, err := fs.Glob(migrationSQLiteFS, "migrations_sqlite/*.sql")
+	if err != nil {
+		return fmt.Errorf("migrate database files: %w", err)
+	}
+
+	for _, file := range files {
+		number, _, _ := strings.Cut(strings.TrimPrefix(file, "migrations_sqlite/"), "_")
+		fileVersion, err := strconv.Atoi(number)
+		if err != nil {
+			return fmt.Errorf("migration [%s] version: %w", file, err)
+		}
+
+		if fileVersion <= version {
+			continue
+		}
+
+		content, err := migrationSQLiteFS.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("migration [%s] read: %w", file, err)
+		}
+
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("migration [%s] begin: %w", file, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+			_ = tx.Rollback()
+
+			return fmt.Errorf("run migration [%s]: %w", file, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "PRAGMA user_version = "+strconv.Itoa(fileVersion)); err != nil {
+			_ = tx.Rollback()
+
+			return fmt.Errorf("migration [%s] version: %w", file, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration [%s] commit: %w", file, err)
+		}
+
+		log.Info().Msgf("ran migration [%s] from version [%d] to [%d]", file, version, fileVersion)
+
+		version = fileVersion
+	}
+
+	return nil
+}
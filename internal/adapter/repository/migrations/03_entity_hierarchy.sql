@@ -0,0 +1,32 @@
+ALTER TABLE calendar_relations ADD COLUMN IF NOT EXISTS type text NOT NULL DEFAULT 'include';
+ALTER TABLE calendar_relations ADD COLUMN IF NOT EXISTS parent text;
+
+ALTER TABLE calendar_relations DROP CONSTRAINT IF EXISTS unique_calendar_entity;
+
+-- a unique constraint treats nulls as distinct and never matched the relations, keep the first of the duplicates
+DELETE FROM calendar_relations a USING calendar_relations b
+WHERE a.ctid > b.ctid
+    AND a.entity = b.entity
+    AND a.type = b.type
+    AND a.parent IS NOT DISTINCT FROM b.parent
+    AND a.event_group IS NOT DISTINCT FROM b.event_group
+    AND a.event_id IS NOT DISTINCT FROM b.event_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_calendar_entity ON calendar_relations (
+    entity, type, COALESCE(parent, ''), COALESCE(event_group, ''), COALESCE(event_id, '')
+);
+
+ALTER TABLE calendar_relations ADD CONSTRAINT check_calendar_relation_type CHECK (type IN ('include', 'parent', 'exclude'));
+
+CREATE INDEX IF NOT EXISTS idx_calendar_relations_parent ON calendar_relations (entity) WHERE type = 'parent';
+
+-- comments
+COMMENT ON COLUMN calendar_relations.type IS
+$$Type of the relation.
+`include` links the entity to an event_id or event_group.
+`parent` makes the entity inherit everything of the parent entity.
+`exclude` removes an event_id or event_group from the entity and its inherited calendars.
+$$;
+
+COMMENT ON COLUMN calendar_relations.parent IS
+'Parent entity for relations with the parent type.';
@@ -0,0 +1,10 @@
+ALTER TABLE calendar_relations ADD COLUMN IF NOT EXISTS occurrence date;
+
+DROP INDEX IF EXISTS unique_calendar_entity;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_calendar_entity ON calendar_relations (
+    entity, type, COALESCE(parent, ''), COALESCE(event_group, ''), COALESCE(event_id, ''), COALESCE(occurrence, 'infinity'::date)
+);
+
+-- comments
+COMMENT ON COLUMN calendar_relations.occurrence IS
+'Day of the excluded occurrence for exclude relations, when empty the whole event is excluded.';
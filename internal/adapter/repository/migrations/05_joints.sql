@@ -0,0 +1,23 @@
+CREATE TABLE if NOT EXISTS calendar_joints (
+    name text NOT NULL PRIMARY KEY,
+    description text NOT NULL DEFAULT '',
+
+    entities jsonb NOT NULL DEFAULT '[]',
+    mode text NOT NULL DEFAULT 'union',
+
+    -- metadata
+    updated_at timestamp with time zone default now(),
+    updated_by varchar(255) not null default '',
+
+    CONSTRAINT check_calendar_joint_mode CHECK (mode IN ('union', 'intersection'))
+);
+
+-- comments
+COMMENT ON COLUMN calendar_joints.entities IS
+'Entities combined in the joint calendar, as a JSON array of strings.';
+
+COMMENT ON COLUMN calendar_joints.mode IS
+$$How the calendars of the entities are combined.
+`union` closes a day when any of the entities has a holiday.
+`intersection` closes a day only when all of the entities have a holiday.
+$$;
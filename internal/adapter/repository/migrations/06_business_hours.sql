@@ -0,0 +1,29 @@
+ALTER TABLE calendar_events ADD COLUMN IF NOT EXISTS type text NOT NULL DEFAULT 'holiday';
+
+ALTER TABLE calendar_events ADD CONSTRAINT check_calendar_event_type CHECK (type IN ('holiday', 'half-day'));
+
+CREATE TABLE if NOT EXISTS calendar_hours (
+    entity text NOT NULL PRIMARY KEY,
+    tz text NOT NULL DEFAULT '',
+
+    hours jsonb NOT NULL DEFAULT '{}',
+
+    -- metadata
+    updated_at timestamp with time zone default now(),
+    updated_by varchar(255) not null default ''
+);
+
+-- comments
+COMMENT ON COLUMN calendar_events.type IS
+$$Type of the event.
+`holiday` closes all days covered by the event.
+`half-day` closes only the time window of the event, like an early close.
+$$;
+
+COMMENT ON COLUMN calendar_hours.hours IS
+$$Weekly business hours, weekday names to list of open windows.
+{"monday": [{"from": "09:00", "to": "17:00"}]}, a weekday without windows is closed.
+$$;
+
+COMMENT ON COLUMN calendar_hours.tz IS
+'Timezone of the business hours, default is UTC.';
@@ -0,0 +1,19 @@
+CREATE TABLE if NOT EXISTS calendar_weekends (
+    entity text NOT NULL,
+    effective_from date NOT NULL,
+
+    days jsonb NOT NULL DEFAULT '[]',
+
+    -- metadata
+    updated_at timestamp with time zone default now(),
+    updated_by varchar(255) not null default '',
+
+    PRIMARY KEY (entity, effective_from)
+);
+
+-- comments
+COMMENT ON COLUMN calendar_weekends.effective_from IS
+'First day of the weekend pattern, it is valid until the next pattern of the entity.';
+
+COMMENT ON COLUMN calendar_weekends.days IS
+'Weekend days as a JSON array of lowercase weekday names, like ["friday", "saturday"].';
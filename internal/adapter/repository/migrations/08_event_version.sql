@@ -0,0 +1,5 @@
+ALTER TABLE calendar_events ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+
+-- comments
+COMMENT ON COLUMN calendar_events.version IS
+'Version of the event, it is increased on every update and checked with If-Match.';
@@ -0,0 +1,29 @@
+CREATE TABLE if NOT EXISTS calendar_event_history (
+    id bigserial NOT NULL PRIMARY KEY,
+    event_id text NOT NULL,
+    action text NOT NULL,
+    version bigint NOT NULL,
+
+    before jsonb,
+    after jsonb,
+
+    changed_at timestamp with time zone NOT NULL default now(),
+    changed_by varchar(255) NOT NULL default ''
+);
+
+CREATE INDEX IF NOT EXISTS calendar_event_history_event_id_idx ON calendar_event_history (event_id, id);
+CREATE INDEX IF NOT EXISTS calendar_event_history_changed_at_idx ON calendar_event_history (changed_at);
+CREATE INDEX IF NOT EXISTS calendar_event_history_changed_by_idx ON calendar_event_history (changed_by, changed_at);
+
+-- comments
+COMMENT ON COLUMN calendar_event_history.action IS
+'Change of the event, one of insert, update or delete.';
+
+COMMENT ON COLUMN calendar_event_history.version IS
+'Version of the event after the change, the removed version for deletes.';
+
+COMMENT ON COLUMN calendar_event_history.before IS
+'Snapshot of the event before the change, null for inserts.';
+
+COMMENT ON COLUMN calendar_event_history.after IS
+'Snapshot of the event after the change, null for deletes.';
@@ -0,0 +1,30 @@
+CREATE TABLE if NOT EXISTS calendar_relation_history (
+    id bigserial NOT NULL PRIMARY KEY,
+    action text NOT NULL,
+
+    before jsonb,
+    after jsonb,
+
+    changed_at timestamp with time zone NOT NULL default now(),
+    changed_by varchar(255) NOT NULL default ''
+);
+
+CREATE INDEX IF NOT EXISTS calendar_relation_history_changed_at_idx ON calendar_relation_history (changed_at);
+
+-- existing events and relations are recorded as added at their last update
+INSERT INTO calendar_event_history (event_id, action, version, after, changed_at, changed_by)
+SELECT e.id, 'insert', e.version, to_jsonb(e), COALESCE(e.updated_at, now()), e.updated_by
+FROM calendar_events e
+WHERE NOT EXISTS (SELECT 1 FROM calendar_event_history h WHERE h.event_id = e.id);
+
+INSERT INTO calendar_relation_history (action, after, changed_at, changed_by)
+SELECT 'insert', to_jsonb(r), COALESCE(r.updated_at, now()), r.updated_by
+FROM calendar_relations r
+WHERE NOT EXISTS (SELECT 1 FROM calendar_relation_history);
+
+-- comments
+COMMENT ON COLUMN calendar_relation_history.before IS
+'Snapshot of the relation before the change, null for inserts.';
+
+COMMENT ON COLUMN calendar_relation_history.after IS
+'Snapshot of the relation after the change, null for deletes. Relations at a time are replayed from the snapshots.';
@@ -0,0 +1,15 @@
+ALTER TABLE calendar_events ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+ALTER TABLE calendar_events ADD COLUMN IF NOT EXISTS deleted_by varchar(255);
+
+ALTER TABLE calendar_relations ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+ALTER TABLE calendar_relations ADD COLUMN IF NOT EXISTS deleted_by varchar(255);
+
+CREATE INDEX IF NOT EXISTS calendar_events_deleted_at_idx ON calendar_events (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS calendar_relations_deleted_at_idx ON calendar_relations (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- comments
+COMMENT ON COLUMN calendar_events.deleted_at IS
+'Time the event is moved to the trash, null for active events. Trashed events are purged after the retention.';
+
+COMMENT ON COLUMN calendar_relations.deleted_at IS
+'Time the relation is moved to the trash, null for active relations. Relations removed with their event have the deleted_at of the event.';
@@ -0,0 +1,34 @@
+CREATE TABLE if NOT EXISTS calendar_change_sets (
+    id text NOT NULL PRIMARY KEY,
+    name text NOT NULL DEFAULT '',
+    description text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'draft',
+    changes jsonb NOT NULL DEFAULT '{}',
+
+    effective_at timestamp with time zone,
+    error text NOT NULL DEFAULT '',
+
+    created_at timestamp with time zone NOT NULL default now(),
+    created_by varchar(255) NOT NULL default '',
+    updated_at timestamp with time zone default now(),
+    updated_by varchar(255) NOT NULL default '',
+    approved_at timestamp with time zone,
+    approved_by varchar(255),
+    applied_at timestamp with time zone,
+
+    CONSTRAINT check_calendar_change_set_status CHECK (status IN ('draft', 'approved', 'applied', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS calendar_change_sets_effective_at_idx ON calendar_change_sets (effective_at) WHERE status = 'approved';
+
+-- comments
+COMMENT ON COLUMN calendar_change_sets.status IS
+$$Status of the change-set.
+`draft` waits for the approval of another user.
+`approved` waits for the effective_at time.
+`applied` is applied to the events and relations.
+`failed` could not be applied at the effective_at time, the reason is in the error.
+$$;
+
+COMMENT ON COLUMN calendar_change_sets.changes IS
+'Events and relations to add, update and remove, applied in one transaction.';
@@ -0,0 +1,27 @@
+-- calendar_events of the postgres migrations 01, 06, 08 and 11.
+CREATE TABLE IF NOT EXISTS calendar_events (
+    id text NOT NULL PRIMARY KEY,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    event_group text,
+
+    date_from timestamp NOT NULL,
+    date_to timestamp NOT NULL,
+    tz text NOT NULL DEFAULT '',
+    all_day boolean NOT NULL DEFAULT false,
+
+    rrule text NOT NULL DEFAULT '',
+
+    disabled boolean NOT NULL DEFAULT false,
+    type text NOT NULL DEFAULT 'holiday',
+    version bigint NOT NULL DEFAULT 1,
+
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_by varchar(255) NOT NULL DEFAULT '',
+    deleted_at timestamp,
+    deleted_by varchar(255),
+
+    CONSTRAINT check_calendar_event_type CHECK (type IN ('holiday', 'half-day'))
+);
+
+CREATE INDEX IF NOT EXISTS calendar_events_deleted_at_idx ON calendar_events (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,30 @@
+-- calendar_relations of the postgres migrations 02, 03, 04 and 11.
+CREATE TABLE IF NOT EXISTS calendar_relations (
+    entity text NOT NULL,
+    type text NOT NULL DEFAULT 'include',
+
+    -- relation with any
+    event_group text,
+    event_id text,
+    parent text,
+    occurrence date,
+
+    -- metadata
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_by varchar(255) NOT NULL DEFAULT '',
+    deleted_at timestamp,
+    deleted_by varchar(255),
+
+    -- foreign keys
+    FOREIGN KEY (event_id) REFERENCES calendar_events (id) ON DELETE CASCADE,
+
+    CONSTRAINT check_calendar_relation_type CHECK (type IN ('include', 'parent', 'exclude'))
+);
+
+-- a unique constraint treats nulls as distinct, the index compares the empty fields too
+CREATE UNIQUE INDEX IF NOT EXISTS unique_calendar_entity ON calendar_relations (
+    entity, type, COALESCE(parent, ''), COALESCE(event_group, ''), COALESCE(event_id, ''), COALESCE(occurrence, '')
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_relations_parent ON calendar_relations (entity) WHERE type = 'parent';
+CREATE INDEX IF NOT EXISTS calendar_relations_deleted_at_idx ON calendar_relations (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,14 @@
+-- calendar_joints of the postgres migration 05.
+CREATE TABLE IF NOT EXISTS calendar_joints (
+    name text NOT NULL PRIMARY KEY,
+    description text NOT NULL DEFAULT '',
+
+    entities text NOT NULL DEFAULT '[]',
+    mode text NOT NULL DEFAULT 'union',
+
+    -- metadata
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_by varchar(255) NOT NULL DEFAULT '',
+
+    CONSTRAINT check_calendar_joint_mode CHECK (mode IN ('union', 'intersection'))
+);
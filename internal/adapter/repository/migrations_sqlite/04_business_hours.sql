@@ -0,0 +1,24 @@
+-- calendar_hours and calendar_weekends of the postgres migrations 06 and 07.
+CREATE TABLE IF NOT EXISTS calendar_hours (
+    entity text NOT NULL PRIMARY KEY,
+    tz text NOT NULL DEFAULT '',
+
+    hours text NOT NULL DEFAULT '{}',
+
+    -- metadata
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_by varchar(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS calendar_weekends (
+    entity text NOT NULL,
+    effective_from date NOT NULL,
+
+    days text NOT NULL DEFAULT '[]',
+
+    -- metadata
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_by varchar(255) NOT NULL DEFAULT '',
+
+    PRIMARY KEY (entity, effective_from)
+);
@@ -0,0 +1,30 @@
+-- calendar_event_history and calendar_relation_history of the postgres migrations 09 and 10.
+CREATE TABLE IF NOT EXISTS calendar_event_history (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    event_id text NOT NULL,
+    action text NOT NULL,
+    version bigint NOT NULL,
+
+    before text,
+    after text,
+
+    changed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    changed_by varchar(255) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS calendar_event_history_event_id_idx ON calendar_event_history (event_id, id);
+CREATE INDEX IF NOT EXISTS calendar_event_history_changed_at_idx ON calendar_event_history (changed_at);
+CREATE INDEX IF NOT EXISTS calendar_event_history_changed_by_idx ON calendar_event_history (changed_by, changed_at);
+
+CREATE TABLE IF NOT EXISTS calendar_relation_history (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    action text NOT NULL,
+
+    before text,
+    after text,
+
+    changed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    changed_by varchar(255) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS calendar_relation_history_changed_at_idx ON calendar_relation_history (changed_at);
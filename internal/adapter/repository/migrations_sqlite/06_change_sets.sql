@@ -0,0 +1,23 @@
+-- calendar_change_sets of the postgres migration 12.
+CREATE TABLE IF NOT EXISTS calendar_change_sets (
+    id text NOT NULL PRIMARY KEY,
+    name text NOT NULL DEFAULT '',
+    description text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'draft',
+    changes text NOT NULL DEFAULT '{}',
+
+    effective_at timestamp,
+    error text NOT NULL DEFAULT '',
+
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by varchar(255) NOT NULL DEFAULT '',
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    updated_by varchar(255) NOT NULL DEFAULT '',
+    approved_at timestamp,
+    approved_by varchar(255),
+    applied_at timestamp,
+
+    CONSTRAINT check_calendar_change_set_status CHECK (status IN ('draft', 'approved', 'applied', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS calendar_change_sets_effective_at_idx ON calendar_change_sets (effective_at) WHERE status = 'approved';
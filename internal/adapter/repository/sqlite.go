@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/dialect/sqlite3"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+)
+
+const (
+	// DialectSQLite is the goqu dialect of sqlite with RETURNING and conditional upserts, sqlite supports them since 3.35.
+	DialectSQLite = "calendar_sqlite3"
+	// SQLiteSchema is the schema of the sqlite database, db_schema is not used.
+	SQLiteSchema = "main"
+	// sqliteTimeFormat keeps the text of the times sortable, sqlite compares the times as text.
+	sqliteTimeFormat = "2006-01-02 15:04:05.000000000Z07:00"
+	// sqlitePathSeparator separates the entities in the path of the entity tree.
+	sqlitePathSeparator = "char(31)"
+)
+
+var (
+	// eventColumns are the columns of the events, also the fields of their history snapshots.
+	eventColumns = []string{
+		"id", "name", "description", "event_group",
+		"date_from", "date_to", "tz", "all_day",
+		"rrule", "disabled", "type", "version",
+		"updated_at", "updated_by", "deleted_at", "deleted_by",
+	}
+	// relationColumns are the columns of the relations replayed from the history.
+	relationColumns = []string{"entity", "type", "event_id", "event_group", "parent", "occurrence", "updated_at", "updated_by"}
+
+	// sqliteTimeColumns and sqliteBoolColumns are compared with the values of the query converted to their types.
+	sqliteTimeColumns = map[string]bool{
+		"date_from": true, "date_to": true, "occurrence": true, "effective_from": true, "effective_at": true,
+		"updated_at": true, "deleted_at": true, "changed_at": true, "created_at": true, "approved_at": true, "applied_at": true,
+	}
+	sqliteBoolColumns = map[string]bool{"all_day": true, "disabled": true}
+)
+
+func init() {
+	opts := sqlite3.DialectOptions()
+	opts.SupportsReturn = true
+	opts.SupportsConflictUpdateWhere = true
+	opts.TimeFormat = sqliteTimeFormat
+
+	goqu.RegisterDialect(DialectSQLite, opts)
+}
+
+// IsSQLite reports the db_type is the sqlite driver.
+func IsSQLite(dbType string) bool {
+	return dbType == "sqlite"
+}
+
+// sqliteEntityTree is the entity tree of sqlite, the path is the text of the entities between separators.
+func sqliteEntityTree(relations exp.Expression) exp.Expression {
+	return goqu.L(`(SELECT DISTINCT entity, entity, `+sqlitePathSeparator+` || entity || `+sqlitePathSeparator+` FROM ? AS relations
+UNION ALL
+SELECT tree.root, parents.parent, tree.path || parents.parent || `+sqlitePathSeparator+`
+FROM ? AS parents JOIN ? AS tree ON parents.entity = tree.entity
+WHERE parents.type = ? AND NOT ?)`,
+		relations, relations, goqu.T(TableEntityTreeStr), domain.RelationTypeParent,
+		sqliteInPath(goqu.I("parents.parent"), goqu.I("tree.path")),
+	)
+}
+
+func sqliteInPath(entity, path exp.Expression) exp.Expression {
+	return goqu.L("instr(?, "+sqlitePathSeparator+" || ? || "+sqlitePathSeparator+") > 0", path, entity)
+}
+
+// sqliteEventsAsOf returns the events like eventsAsOf with the fields of the snapshots.
+func (db *Database) sqliteEventsAsOf(asOf time.Time) *goqu.SelectDataset {
+	latest := db.q.From(TableEventHistory).
+		Select(goqu.MAX("id")).
+		Where(goqu.C("changed_at").Lte(asOf)).
+		GroupBy("event_id")
+
+	return db.typed(TableEvents, eventColumns, db.q.From(TableEventHistory).
+		Select(snapshot("after", eventColumns)...).
+		Where(
+			goqu.C("id").In(latest),
+			goqu.C("action").Neq(domain.HistoryActionDelete),
+		),
+	)
+}
+
+// sqliteRelationsAsOf replays the relation changes like relationsAsOf with the fields of the snapshots.
+func (db *Database) sqliteRelationsAsOf(asOf time.Time) *goqu.SelectDataset {
+	changes := func(column, n string) *goqu.SelectDataset {
+		return db.q.From(TableRelationHistory).
+			Select(append(snapshot(column, relationColumns), goqu.L(n).As("n"))...).
+			Where(
+				goqu.C("changed_at").Lte(asOf),
+				goqu.C(column).IsNotNull(),
+			)
+	}
+
+	key := []any{"entity", "type", "event_id", "event_group", "parent", "occurrence"}
+
+	return db.typed(TableRelation, relationColumns, db.q.From(changes("after", "1").UnionAll(changes("before", "-1")).As("changes")).
+		Select(append(key,
+			goqu.MAX("updated_at").As("updated_at"),
+			goqu.MAX("updated_by").As("updated_by"),
+		)...).
+		GroupBy(key...).
+		Having(goqu.SUM("n").Gt(0)),
+	)
+}
+
+// typed adds an empty select of the table to the rows read from the snapshots.
+// Sqlite takes the column types of a subquery from its last select, the times of the snapshots are scanned as times with it.
+func (db *Database) typed(table exp.IdentifierExpression, columns []string, rows *goqu.SelectDataset) *goqu.SelectDataset {
+	selected := make([]any, 0, len(columns))
+	for _, column := range columns {
+		selected = append(selected, column)
+	}
+
+	return rows.UnionAll(db.q.From(table).Select(selected...).Where(goqu.L("0")))
+}
+
+// snapshot selects the fields of the JSON snapshot in the column as the columns.
+func snapshot(column string, columns []string) []any {
+	selected := make([]any, 0, len(columns))
+	for _, c := range columns {
+		selected = append(selected, goqu.Func("json_extract", goqu.I(column), "$."+c).As(c))
+	}
+
+	return selected
+}
+
+// query returns the query with the values of the time and boolean columns converted for sqlite.
+// Sqlite compares the text of the query values with the stored values, the converted ones are written like the stored ones.
+func (db *Database) query(q *query.Query) *query.Query {
+	if db.dialect != DialectSQLite {
+		return q
+	}
+
+	converted := *q
+	converted.Where = sqliteExpressions(q.Where)
+
+	return &converted
+}
+
+func sqliteExpressions(expressions []query.Expression) []query.Expression {
+	converted := make([]query.Expression, 0, len(expressions))
+	for _, e := range expressions {
+		switch v := e.(type) {
+		case query.ExpressionCmp:
+			column := v.Field[strings.LastIndex(v.Field, ".")+1:]
+			switch value := v.Value.(type) {
+			case string:
+				v.Value = sqliteValue(column, value)
+			case []string:
+				values := make([]any, 0, len(value))
+				for _, item := range value {
+					values = append(values, sqliteValue(column, item))
+				}
+				v.Value = values
+			}
+
+			e = v
+		case query.ExpressionLogic:
+			v.List = sqliteExpressions(v.List)
+			e = v
+		}
+
+		converted = append(converted, e)
+	}
+
+	return converted
+}
+
+// sqliteValue converts the value of the column, a value not in the type of the column is kept as it is.
+func sqliteValue(column, value string) any {
+	switch {
+	case sqliteTimeColumns[column]:
+		var t types.Time
+		if err := t.Parse(value); err == nil {
+			return t.Time
+		}
+	case sqliteBoolColumns[column]:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/rakunlabs/chu"
 	"github.com/worldline-go/logz"
@@ -28,7 +29,9 @@ type Config struct {
 	DBDataSource string `cfg:"db_datasource" log:"false"`
 	DBSchema     string `cfg:"db_schema"     default:"public"`
 
-	Migrate Migrate `cfg:"migrate"`
+	Migrate   Migrate   `cfg:"migrate"`
+	Trash     Trash     `cfg:"trash"`
+	ChangeSet ChangeSet `cfg:"change_set"`
 
 	Telemetry tell.Config
 }
@@ -41,6 +44,20 @@ type Migrate struct {
 	DBTable      string `cfg:"db_table"      default:"calendar_migrations"`
 }
 
+// Trash contains the purge of the removed events and relations.
+type Trash struct {
+	// Retention is how long the removed events and relations are kept to be restored.
+	Retention time.Duration `cfg:"retention"      default:"720h"`
+	// PurgeInterval is the time between purges, zero disables the purge.
+	PurgeInterval time.Duration `cfg:"purge_interval" default:"1h"`
+}
+
+// ChangeSet contains the application of the approved change-sets with an effective time.
+type ChangeSet struct {
+	// Interval is the time between the checks of the due change-sets, zero disables the scheduled application.
+	Interval time.Duration `cfg:"interval" default:"1m"`
+}
+
 func Load(ctx context.Context) (*Config, error) {
 	cfg := &Config{}
 
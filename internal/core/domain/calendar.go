@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/worldline-go/types"
 )
 
@@ -18,17 +21,401 @@ type Event struct {
 
 	RRule    string `db:"rrule"    json:"rrule"`
 	Disabled bool   `db:"disabled" json:"disabled"`
+	Type     string `db:"type"     json:"type"`
+
+	// ExDates are the excluded occurrence days of the event, resolved from the exclude relations.
+	ExDates []types.Time `db:"-" json:"exdates,omitempty" swaggertype:"array,string"`
+	// Weekend is set when the occurrence starts on a weekend day of the entity.
+	Weekend bool `db:"-" json:"weekend,omitempty"`
+
+	// Version is increased on every update, updates and removals of the event match it.
+	Version int64 `db:"version" json:"version"`
 
 	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
 	UpdatedBy string     `db:"updated_by" json:"updated_by"`
+
+	// DeletedAt is set when the event is in the trash, it is only changed by removing and restoring.
+	DeletedAt types.Null[types.Time] `db:"deleted_at" json:"deleted_at,omitzero" goqu:"skipinsert,skipupdate" swaggertype:"string"`
+	DeletedBy types.Null[string]     `db:"deleted_by" json:"deleted_by,omitzero" goqu:"skipinsert,skipupdate" swaggertype:"string"`
 }
 
+// EventVersion is an event with the version expected to be stored.
+type EventVersion struct {
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+}
+
+const (
+	// EventTypeHoliday closes all days covered by the event, it is the default type.
+	EventTypeHoliday = "holiday"
+	// EventTypeHalfDay closes only the time window of the event, the day stays as a workday.
+	EventTypeHalfDay = "half-day"
+)
+
+const (
+	// RelationTypeInclude links an entity to an event_id or event_group.
+	RelationTypeInclude = "include"
+	// RelationTypeParent makes an entity inherit the calendar of the parent entity.
+	RelationTypeParent = "parent"
+	// RelationTypeExclude removes an event_id or event_group from an entity, also the inherited ones.
+	// With an occurrence day only that occurrence of the event_id is removed.
+	RelationTypeExclude = "exclude"
+)
+
 type Relation struct {
 	Entity string `db:"entity" json:"entity"`
+	Type   string `db:"type"   json:"type"`
+
+	EventID    types.Null[string]     `db:"event_id"    json:"event_id"    swaggertype:"string"`
+	EventGroup types.Null[string]     `db:"event_group" json:"event_group" swaggertype:"string"`
+	Parent     types.Null[string]     `db:"parent"      json:"parent"      swaggertype:"string"`
+	Occurrence types.Null[types.Time] `db:"occurrence"  json:"occurrence"  swaggertype:"string"`
+
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+	UpdatedBy string     `db:"updated_by" json:"updated_by"`
+
+	// DeletedAt is set when the relation is in the trash, relations removed with their event have its time.
+	DeletedAt types.Null[types.Time] `db:"deleted_at" json:"deleted_at,omitzero" goqu:"skipinsert,skipupdate" swaggertype:"string"`
+	DeletedBy types.Null[string]     `db:"deleted_by" json:"deleted_by,omitzero" goqu:"skipinsert,skipupdate" swaggertype:"string"`
+}
+
+// RelationUpdate replaces the relation From with To, relations are identified with all of their fields.
+type RelationUpdate struct {
+	From Relation `json:"from"`
+	To   Relation `json:"to"`
+}
+
+const (
+	// HistoryActionInsert is recorded when an event is added.
+	HistoryActionInsert = "insert"
+	// HistoryActionUpdate is recorded when an event is replaced.
+	HistoryActionUpdate = "update"
+	// HistoryActionDelete is recorded when an event is removed, it is moved to the trash.
+	HistoryActionDelete = "delete"
+	// HistoryActionRestore is recorded when an event is restored from the trash.
+	HistoryActionRestore = "restore"
+)
+
+// EventHistory is a recorded change of an event with the snapshots before and after the change.
+type EventHistory struct {
+	ID      int64  `db:"id"       json:"id"       goqu:"skipinsert"`
+	EventID string `db:"event_id" json:"event_id"`
+	Action  string `db:"action"   json:"action"`
+	// Version is the version after the change, the removed version for deletes.
+	Version int64 `db:"version" json:"version"`
+
+	// Before is null for inserts and After is null for deletes.
+	Before types.JSON[Event] `db:"before" json:"before" swaggertype:"object"`
+	After  types.JSON[Event] `db:"after"  json:"after"  swaggertype:"object"`
+
+	ChangedAt types.Time `db:"changed_at" json:"changed_at"`
+	ChangedBy string     `db:"changed_by" json:"changed_by"`
+}
+
+const (
+	// ChangeSetStatusDraft is a change-set waiting for the approval, only drafts are edited.
+	ChangeSetStatusDraft = "draft"
+	// ChangeSetStatusApproved is an approved change-set waiting for its effective time.
+	ChangeSetStatusApproved = "approved"
+	// ChangeSetStatusApplied is a change-set applied to the calendar.
+	ChangeSetStatusApplied = "applied"
+	// ChangeSetStatusFailed is an approved change-set failed to be applied at its effective time.
+	ChangeSetStatusFailed = "failed"
+)
+
+// ChangeSet is a draft of event and relation changes, it is applied in one transaction after another user approves it.
+type ChangeSet struct {
+	ID string `db:"id" json:"id" goqu:"skipupdate"`
+
+	Name        string              `db:"name"        json:"name"`
+	Description string              `db:"description" json:"description"`
+	Status      string              `db:"status"      json:"status"`
+	Changes     types.JSON[Changes] `db:"changes"     json:"changes"     swaggertype:"object"`
+
+	// EffectiveAt applies the approved change-set at that time, null applies it on the approval.
+	EffectiveAt types.Null[types.Time] `db:"effective_at" json:"effective_at" swaggertype:"string"`
+	// Error is the reason of a failed change-set.
+	Error string `db:"error" json:"error,omitempty"`
+
+	CreatedAt  types.Time             `db:"created_at"  json:"created_at"  goqu:"skipupdate"`
+	CreatedBy  string                 `db:"created_by"  json:"created_by"  goqu:"skipupdate"`
+	UpdatedAt  types.Time             `db:"updated_at"  json:"updated_at"`
+	UpdatedBy  string                 `db:"updated_by"  json:"updated_by"`
+	ApprovedAt types.Null[types.Time] `db:"approved_at" json:"approved_at" swaggertype:"string"`
+	ApprovedBy types.Null[string]     `db:"approved_by" json:"approved_by" swaggertype:"string"`
+	AppliedAt  types.Null[types.Time] `db:"applied_at"  json:"applied_at"  swaggertype:"string"`
+}
+
+// Changes are the edits of a change-set.
+// They are applied in the order of removing relations and events, adding and updating events and adding relations.
+type Changes struct {
+	AddEvents []Event `json:"add_events,omitempty"`
+	// UpdateEvents replace the events with their ID when the version is not changed, zero version replaces any version.
+	UpdateEvents []Event  `json:"update_events,omitempty"`
+	RemoveEvents []string `json:"remove_events,omitempty"`
+
+	AddRelations []Relation `json:"add_relations,omitempty"`
+	// RemoveRelations are matched with all of their fields like the relation updates.
+	RemoveRelations []Relation `json:"remove_relations,omitempty"`
+}
+
+const (
+	BatchOpAddEvent       = "add_event"
+	BatchOpUpdateEvent    = "update_event"
+	BatchOpRemoveEvent    = "remove_event"
+	BatchOpAddRelation    = "add_relation"
+	BatchOpUpdateRelation = "update_relation"
+	BatchOpRemoveRelation = "remove_relation"
+)
+
+const (
+	// BatchStatusApplied is an operation committed with the batch.
+	BatchStatusApplied = "applied"
+	// BatchStatusFailed is the operation rolling back the batch.
+	BatchStatusFailed = "failed"
+	// BatchStatusRolledBack is an operation done before the failed one, its change is rolled back.
+	BatchStatusRolledBack = "rolled_back"
+	// BatchStatusSkipped is an operation after the failed one, it is not run.
+	BatchStatusSkipped = "skipped"
+)
+
+// BatchOperation is one change of a batch, the fields are used by the op.
+type BatchOperation struct {
+	Op string `json:"op"`
+
+	// ID is the event of update_event and remove_event.
+	ID string `json:"id,omitempty"`
+	// Version is the expected version of the event to update or remove, it is required for them.
+	Version int64 `json:"version,omitempty"`
+	// Event is the new event of add_event and update_event.
+	Event *Event `json:"event,omitempty"`
+
+	// Relation is added, removed or replaced with To, relations are matched with all of their fields.
+	Relation *Relation `json:"relation,omitempty"`
+	To       *Relation `json:"to,omitempty"`
+}
+
+// BatchResult is the result of the operation in the same index of the batch.
+type BatchResult struct {
+	Op     string `json:"op"`
+	Status string `json:"status"`
+	// ID is the event of the event operations, added events without an ID get it here.
+	ID string `json:"id,omitempty"`
+	// Version is the new version of an added or updated event.
+	Version int64  `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// WorkDay is a resolved workday with the holidays skipped to reach it.
+type WorkDay struct {
+	Date     types.Time `json:"date"     swaggertype:"string"`
+	Holidays []Event    `json:"holidays"`
+}
+
+const (
+	// JointModeUnion closes a day when any of the entities has a holiday, open days are open in all entities.
+	JointModeUnion = "union"
+	// JointModeIntersection closes a day only when all of the entities have a holiday, open days are open in any entity.
+	JointModeIntersection = "intersection"
+)
+
+// Joint is a named combination of entities resolved as one calendar.
+type Joint struct {
+	Name string `db:"name" json:"name" goqu:"skipupdate"`
 
-	EventID    types.Null[string] `db:"event_id"    json:"event_id"    swaggertype:"string"`
-	EventGroup types.Null[string] `db:"event_group" json:"event_group" swaggertype:"string"`
+	Description string              `db:"description" json:"description"`
+	Entities    types.Slice[string] `db:"entities"    json:"entities"    swaggertype:"array,string"`
+	Mode        string              `db:"mode"        json:"mode"`
 
 	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
 	UpdatedBy string     `db:"updated_by" json:"updated_by"`
 }
+
+// Window is an open period of a day, times are in "15:04" format and to could be "24:00".
+type Window struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Minutes returns the start and end of the window as minutes of the day.
+func (w Window) Minutes() (int, int, error) {
+	from, err := dayMinutes(w.From)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	to, err := dayMinutes(w.To)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if from >= to {
+		return 0, 0, fmt.Errorf("window from %s should be before to %s", w.From, w.To)
+	}
+
+	return from, to, nil
+}
+
+func dayMinutes(v string) (int, error) {
+	if v == "24:00" {
+		return 24 * 60, nil
+	}
+
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", v, err)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// BusinessHours are the weekly open windows of an entity.
+type BusinessHours struct {
+	Entity string `db:"entity" json:"entity" goqu:"skipupdate"`
+
+	Tz string `db:"tz" json:"tz"`
+	// Hours are the open windows with the lowercase weekday names, a weekday without windows is closed.
+	Hours types.Map[[]Window] `db:"hours" json:"hours" swaggertype:"object"`
+
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+	UpdatedBy string     `db:"updated_by" json:"updated_by"`
+}
+
+// Weekend is the weekend pattern of an entity starting from the effective day.
+type Weekend struct {
+	Entity        string     `db:"entity"         json:"entity"`
+	EffectiveFrom types.Time `db:"effective_from" json:"effective_from" swaggertype:"string"`
+
+	// Days are the lowercase weekday names of the weekend.
+	Days types.Slice[string] `db:"days" json:"days" swaggertype:"array,string"`
+
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+	UpdatedBy string     `db:"updated_by" json:"updated_by"`
+}
+
+// OpenAt is the open state of the calendar at a time.
+type OpenAt struct {
+	Time types.Time `json:"time" swaggertype:"string"`
+	Open bool       `json:"open"`
+	// Closed are the entities closed at the time.
+	Closed []string `json:"closed,omitempty"`
+	// Events are the holidays and the half-day events closing the entities at the time.
+	Events []Event `json:"events,omitempty"`
+}
+
+// SettlementRequest is a trade to settle after a number of workdays.
+type SettlementRequest struct {
+	Trade types.Time
+	Days  int
+	// CutOff is the time of the day in "15:04" format, trades at or after it are booked on the next workday.
+	CutOff string
+	// Tz is the timezone of the trade day and the cut-off.
+	Tz string
+}
+
+// Settlement is the settlement date of a trade with the holidays skipped to reach it.
+type Settlement struct {
+	Trade types.Time `json:"trade" swaggertype:"string"`
+	// TradeDate is the workday the trade is booked on.
+	TradeDate   types.Time `json:"trade_date"    swaggertype:"string"`
+	Date        types.Time `json:"date"          swaggertype:"string"`
+	AfterCutOff bool       `json:"after_cut_off"`
+	Holidays    []Event    `json:"holidays"`
+}
+
+const (
+	// ConventionFollowing moves to the next workday.
+	ConventionFollowing = "following"
+	// ConventionModifiedFollowing moves to the next workday, unless it is in the next month then to the previous workday.
+	ConventionModifiedFollowing = "modified_following"
+	// ConventionPreceding moves to the previous workday.
+	ConventionPreceding = "preceding"
+	// ConventionModifiedPreceding moves to the previous workday, unless it is in the previous month then to the next workday.
+	ConventionModifiedPreceding = "modified_preceding"
+	// ConventionEndOfMonth moves to the last workday of the month.
+	ConventionEndOfMonth = "end_of_month"
+)
+
+// Adjustment is a date adjusted to a workday with a business day convention.
+type Adjustment struct {
+	Date       types.Time `json:"date"       swaggertype:"string"`
+	Adjusted   types.Time `json:"adjusted"   swaggertype:"string"`
+	Convention string     `json:"convention"`
+	Holidays   []Event    `json:"holidays"`
+}
+
+// Span is a run of days from the first to the last day with the events causing it.
+type Span struct {
+	From   types.Time `json:"from"   swaggertype:"string"`
+	To     types.Time `json:"to"     swaggertype:"string"`
+	Days   int        `json:"days"`
+	Events []Event    `json:"events"`
+}
+
+// Bridges are the bridge days and long weekends of a year.
+type Bridges struct {
+	Year int `json:"year"`
+	// Bridges are workdays between a holiday and another closed day.
+	Bridges []Span `json:"bridges"`
+	// LongWeekends are closed runs longer than a weekend with a holiday in it.
+	LongWeekends []Span `json:"long_weekends"`
+}
+
+// DiffSide is the entity and year of a calendar to compare.
+type DiffSide struct {
+	Entity string `json:"entity"`
+	Year   int    `json:"year"`
+}
+
+// Move is a holiday of the base calendar found on another date in the target calendar.
+type Move struct {
+	From Event `json:"from"`
+	To   Event `json:"to"`
+}
+
+// Diff is the change of holidays from the base calendar to the target calendar.
+// Holidays are matched by event ID and then by name, dates are compared after shifting the base to the target year.
+type Diff struct {
+	Base      DiffSide `json:"base"`
+	Target    DiffSide `json:"target"`
+	Added     []Event  `json:"added"`
+	Removed   []Event  `json:"removed"`
+	Moved     []Move   `json:"moved"`
+	Unchanged int      `json:"unchanged"`
+}
+
+const (
+	// CoverageStatusOK has enough occurrences compared with the current year.
+	CoverageStatusOK = "ok"
+	// CoverageStatusLow has fewer occurrences than the ratio of the current year.
+	CoverageStatusLow = "low"
+	// CoverageStatusMissing has no occurrences.
+	CoverageStatusMissing = "missing"
+
+	// DefaultCoverageYears is the number of upcoming years checked for coverage.
+	DefaultCoverageYears = 2
+	// DefaultCoverageRatio is the ratio of the current year occurrences below which a year is low.
+	DefaultCoverageRatio = 0.5
+)
+
+// Coverage is the number of occurrences of an entity or event group in an upcoming year.
+type Coverage struct {
+	Entity      string `json:"entity,omitempty"`
+	EventGroup  string `json:"event_group,omitempty"`
+	Year        int    `json:"year"`
+	Occurrences int    `json:"occurrences"`
+	// Baseline is the number of occurrences in the current year.
+	Baseline int    `json:"baseline"`
+	Status   string `json:"status"`
+}
+
+// Modified is the last change of the events, relations, weekends and the joint calendar used by a query.
+// Counts change when one of them is removed, a removal doesn't change the time.
+type Modified struct {
+	UpdatedAt types.Time `json:"updated_at"`
+	Events    int        `json:"events"`
+	Relations int        `json:"relations"`
+	Weekends  int        `json:"weekends"`
+	// Mode combines the entities, it is the mode of the joint calendar when the query doesn't have one.
+	Mode string `json:"mode"`
+}
@@ -2,4 +2,23 @@ package domain
 
 import "errors"
 
-var ErrStopLoop = errors.New("stop loop")
+var (
+	ErrStopLoop      = errors.New("stop loop")
+	ErrJointNotFound = errors.New("joint calendar not found")
+	ErrEventNotFound = errors.New("event not found")
+	// ErrEventExists is returned when a new event has the ID of a stored one.
+	ErrEventExists  = errors.New("event already exists")
+	ErrEventVersion = errors.New("event version mismatch")
+	// ErrEventVersionRequired is returned when a change of an event doesn't have the version of the event.
+	ErrEventVersionRequired = errors.New("event version is required")
+	// ErrRelationNotFound is returned when a relation to update is changed or removed in the meantime.
+	ErrRelationNotFound = errors.New("relation not found")
+	// ErrAsOf is returned when the as_of time of the query cannot be parsed.
+	ErrAsOf = errors.New("invalid as_of")
+
+	ErrChangeSetNotFound = errors.New("change-set not found")
+	// ErrChangeSetStatus is returned when the change-set is not in the status for the operation, like editing an approved one.
+	ErrChangeSetStatus = errors.New("change-set status mismatch")
+	// ErrChangeSetApprover is returned when the creator of the change-set approves it.
+	ErrChangeSetApprover = errors.New("change-set must be approved by another user")
+)
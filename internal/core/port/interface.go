@@ -13,30 +13,122 @@ import (
 
 type CalendarPort interface {
 	AddRelations(ctx context.Context, relations []domain.Relation) error
-	RemoveRelation(ctx context.Context, q *query.Query) error
+	RemoveRelation(ctx context.Context, q *query.Query, removedBy string) error
+	RemoveRelations(ctx context.Context, relations []domain.Relation, removedBy string) error
+	UpdateRelations(ctx context.Context, updates []domain.RelationUpdate) error
 	GetRelations(ctx context.Context, q *query.Query) ([]domain.Relation, error)
 	GetRelationsCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetExclusions(ctx context.Context, entities []string, asOf time.Time) ([]domain.Relation, error)
 	AddEvents(ctx context.Context, events []domain.Event) error
 	GetEvents(ctx context.Context, q *query.Query) ([]domain.Event, error)
 	GetEventsCount(ctx context.Context, q *query.Query) (uint64, error)
 	GetEventsWithFunc(ctx context.Context, q *query.Query, fn func(domain.Event) error) error
+	// GetModified returns the last change and the counts of the events of the query, all relations and the weekends of the entities.
+	GetModified(ctx context.Context, q *query.Query, entities []string) (*domain.Modified, error)
 	GetEvent(ctx context.Context, id string) (*domain.Event, error)
 	UpdateEvent(ctx context.Context, id string, event *domain.Event) error
-	RemoveEvent(ctx context.Context, id ...string) error
+	RemoveEvent(ctx context.Context, removedBy string, id ...string) error
+	RemoveEventVersion(ctx context.Context, id string, version int64, removedBy string) error
+	GetHistory(ctx context.Context, q *query.Query) ([]domain.EventHistory, error)
+	GetHistoryCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetTrashEvents(ctx context.Context, q *query.Query) ([]domain.Event, error)
+	GetTrashEventsCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetTrashRelations(ctx context.Context, q *query.Query) ([]domain.Relation, error)
+	GetTrashRelationsCount(ctx context.Context, q *query.Query) (uint64, error)
+	RestoreEvent(ctx context.Context, id string, restoredBy string) error
+	RestoreRelations(ctx context.Context, q *query.Query, restoredBy string) error
+	PurgeTrash(ctx context.Context, before time.Time) (uint64, error)
+	AddChangeSet(ctx context.Context, changeSet *domain.ChangeSet) error
+	GetChangeSets(ctx context.Context, q *query.Query) ([]domain.ChangeSet, error)
+	GetChangeSetsCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetDueChangeSets(ctx context.Context, at time.Time) ([]domain.ChangeSet, error)
+	GetChangeSet(ctx context.Context, id string) (*domain.ChangeSet, error)
+	UpdateChangeSet(ctx context.Context, id string, status string, changeSet *domain.ChangeSet) error
+	RemoveChangeSet(ctx context.Context, id string) error
+	ApplyChanges(ctx context.Context, changes domain.Changes, appliedBy string) error
+	AddJoints(ctx context.Context, joints []domain.Joint) error
+	GetJoints(ctx context.Context, q *query.Query) ([]domain.Joint, error)
+	GetJointsCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetJoint(ctx context.Context, name string) (*domain.Joint, error)
+	UpdateJoint(ctx context.Context, name string, joint *domain.Joint) error
+	RemoveJoint(ctx context.Context, name ...string) error
+	SetHours(ctx context.Context, hours *domain.BusinessHours) error
+	GetHours(ctx context.Context, q *query.Query) ([]domain.BusinessHours, error)
+	GetHoursCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetEntityHours(ctx context.Context, entity string) (*domain.BusinessHours, error)
+	RemoveHours(ctx context.Context, entity ...string) error
+	AddWeekends(ctx context.Context, weekends []domain.Weekend) error
+	RemoveWeekends(ctx context.Context, q *query.Query) error
+	GetWeekends(ctx context.Context, q *query.Query) ([]domain.Weekend, error)
+	GetWeekendsCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetEntityWeekends(ctx context.Context, entity string) ([]domain.Weekend, error)
+
+	// Transaction runs fn with the port bound to one transaction, an error of fn rolls back all of its changes.
+	Transaction(ctx context.Context, fn func(CalendarPort) error) error
+	// Preview runs fn like Transaction and always rolls back, fn reads the calendar with its changes.
+	Preview(ctx context.Context, fn func(CalendarPort) error) error
 }
 
 type CalendarService interface {
 	AddRelations(ctx context.Context, relations []domain.Relation) error
-	RemoveRelation(ctx context.Context, q *query.Query) error
+	RemoveRelation(ctx context.Context, q *query.Query, removedBy string) error
+	UpdateRelations(ctx context.Context, updates []domain.RelationUpdate) error
 	GetRelations(ctx context.Context, q *query.Query) ([]domain.Relation, error)
 	GetRelationsCount(ctx context.Context, q *query.Query) (uint64, error)
 	AddEvents(ctx context.Context, events []domain.Event) error
+	AddEventWithRelations(ctx context.Context, event *domain.Event, relations []domain.Relation) error
 	GetEvents(ctx context.Context, q *query.Query) ([]domain.Event, error)
 	GetEventsCount(ctx context.Context, q *query.Query) (uint64, error)
 	GetEvent(ctx context.Context, id string) (*domain.Event, error)
 	UpdateEvent(ctx context.Context, id string, event *domain.Event) error
-	RemoveEvent(ctx context.Context, id ...string) error
+	RemoveEvents(ctx context.Context, events []domain.EventVersion, removedBy string) error
+	RemoveEventVersion(ctx context.Context, id string, version int64, removedBy string) error
+	GetHistory(ctx context.Context, q *query.Query) ([]domain.EventHistory, error)
+	GetHistoryCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetEventHistory(ctx context.Context, id string, q *query.Query) ([]domain.EventHistory, error)
+	GetEventHistoryCount(ctx context.Context, id string, q *query.Query) (uint64, error)
+	GetTrashEvents(ctx context.Context, q *query.Query) ([]domain.Event, error)
+	GetTrashEventsCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetTrashRelations(ctx context.Context, q *query.Query) ([]domain.Relation, error)
+	GetTrashRelationsCount(ctx context.Context, q *query.Query) (uint64, error)
+	RestoreEvent(ctx context.Context, id string, restoredBy string) error
+	RestoreRelations(ctx context.Context, q *query.Query, restoredBy string) error
+	AddChangeSet(ctx context.Context, changeSet *domain.ChangeSet) error
+	GetChangeSets(ctx context.Context, q *query.Query) ([]domain.ChangeSet, error)
+	GetChangeSetsCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetChangeSet(ctx context.Context, id string) (*domain.ChangeSet, error)
+	UpdateChangeSet(ctx context.Context, id string, changeSet *domain.ChangeSet) error
+	RemoveChangeSet(ctx context.Context, id string) error
+	ApproveChangeSet(ctx context.Context, id string, approvedBy string) (*domain.ChangeSet, error)
+	PreviewChangeSet(ctx context.Context, id string, q *query.Query) ([]domain.Event, error)
+	Batch(ctx context.Context, operations []domain.BatchOperation, updatedBy string) ([]domain.BatchResult, error)
+	AddJoints(ctx context.Context, joints []domain.Joint) error
+	GetJoints(ctx context.Context, q *query.Query) ([]domain.Joint, error)
+	GetJointsCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetJoint(ctx context.Context, name string) (*domain.Joint, error)
+	UpdateJoint(ctx context.Context, name string, joint *domain.Joint) error
+	RemoveJoint(ctx context.Context, name ...string) error
+	SetHours(ctx context.Context, hours *domain.BusinessHours) error
+	GetHours(ctx context.Context, q *query.Query) ([]domain.BusinessHours, error)
+	GetHoursCount(ctx context.Context, q *query.Query) (uint64, error)
+	GetEntityHours(ctx context.Context, entity string) (*domain.BusinessHours, error)
+	RemoveHours(ctx context.Context, entity ...string) error
+	AddWeekends(ctx context.Context, weekends []domain.Weekend) error
+	RemoveWeekends(ctx context.Context, q *query.Query) error
+	GetWeekends(ctx context.Context, q *query.Query) ([]domain.Weekend, error)
+	GetWeekendsCount(ctx context.Context, q *query.Query) (uint64, error)
 
 	AddIcal(ctx context.Context, data io.Reader, tz *time.Location, group types.Null[string], updatedBy string) error
 	GetEventsICS(ctx context.Context, q *query.Query) ([]domain.Event, error)
+	GetDefinitions(ctx context.Context, q *query.Query) ([]domain.Event, error)
+	Modified(ctx context.Context, q *query.Query) (*domain.Modified, error)
+
+	WorkDay(ctx context.Context, q *query.Query, date types.Time, days int) (*domain.WorkDay, error)
+	IsOpenAt(ctx context.Context, q *query.Query, t types.Time) (*domain.OpenAt, error)
+	Settlement(ctx context.Context, q *query.Query, req domain.SettlementRequest) (*domain.Settlement, error)
+	Adjust(ctx context.Context, q *query.Query, date types.Time, convention string) (*domain.Adjustment, error)
+	Bridges(ctx context.Context, q *query.Query, year, days int) (*domain.Bridges, error)
+	Diff(ctx context.Context, base, target domain.DiffSide) (*domain.Diff, error)
+	Coverage(ctx context.Context, years int, ratio float64) ([]domain.Coverage, error)
+	Schedule(ctx context.Context, q *query.Query, spec string, from, to types.Time) ([]types.Time, error)
 }
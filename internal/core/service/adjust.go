@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Adjust moves the date to a workday of the entities in the query with the business day convention.
+// Holidays are the ones skipped on the way to the adjusted date.
+func (s *CalendarService) Adjust(ctx context.Context, q *query.Query, date types.Time, convention string) (*models.Adjustment, error) {
+	day := civilDay(date.Time)
+
+	var (
+		adjusted time.Time
+		holidays []models.Event
+		err      error
+	)
+
+	switch convention {
+	case models.ConventionFollowing:
+		adjusted, holidays, err = s.walkWorkDays(ctx, q, day, 1, 1)
+	case models.ConventionPreceding:
+		adjusted, holidays, err = s.walkWorkDays(ctx, q, day, 1, -1)
+	case models.ConventionModifiedFollowing:
+		adjusted, holidays, err = s.walkWorkDays(ctx, q, day, 1, 1)
+		if err == nil && adjusted.Month() != day.Month() {
+			adjusted, holidays, err = s.walkWorkDays(ctx, q, day, 1, -1)
+		}
+	case models.ConventionModifiedPreceding:
+		adjusted, holidays, err = s.walkWorkDays(ctx, q, day, 1, -1)
+		if err == nil && adjusted.Month() != day.Month() {
+			adjusted, holidays, err = s.walkWorkDays(ctx, q, day, 1, 1)
+		}
+	case models.ConventionEndOfMonth:
+		lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		adjusted, holidays, err = s.walkWorkDays(ctx, q, lastDay, 1, -1)
+	default:
+		return nil, fmt.Errorf("invalid convention: %s", convention)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Adjustment{
+		Date:       types.Time{Time: day},
+		Adjusted:   types.Time{Time: adjusted},
+		Convention: convention,
+		Holidays:   holidays,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestAdjust(t *testing.T) {
+	svc, db := newTestService(t)
+
+	// friday 31 october is followed by the weekend, monday 1 september is after the weekend
+	addHolidays(t, db, "A", utcDay(2025, time.October, 15), utcDay(2025, time.October, 31), utcDay(2025, time.September, 1))
+
+	tests := []struct {
+		name       string
+		date       types.Time
+		convention string
+		want       types.Time
+		holidays   []string
+		wantErr    bool
+	}{
+		{
+			name:       "following",
+			date:       utcDay(2025, time.October, 15),
+			convention: models.ConventionFollowing,
+			want:       utcDay(2025, time.October, 16),
+			holidays:   []string{"A-2025-10-15"},
+		},
+		{
+			name:       "modified following in the month",
+			date:       utcDay(2025, time.October, 15),
+			convention: models.ConventionModifiedFollowing,
+			want:       utcDay(2025, time.October, 16),
+			holidays:   []string{"A-2025-10-15"},
+		},
+		{
+			name:       "following across the month end",
+			date:       utcDay(2025, time.October, 31),
+			convention: models.ConventionFollowing,
+			want:       utcDay(2025, time.November, 3),
+			holidays:   []string{"A-2025-10-31"},
+		},
+		{
+			name:       "modified following across the month end",
+			date:       utcDay(2025, time.October, 31),
+			convention: models.ConventionModifiedFollowing,
+			want:       utcDay(2025, time.October, 30),
+			holidays:   []string{"A-2025-10-31"},
+		},
+		{
+			name:       "preceding across the month start",
+			date:       utcDay(2025, time.September, 1),
+			convention: models.ConventionPreceding,
+			want:       utcDay(2025, time.August, 29),
+			holidays:   []string{"A-2025-09-01"},
+		},
+		{
+			name:       "modified preceding across the month start",
+			date:       utcDay(2025, time.September, 1),
+			convention: models.ConventionModifiedPreceding,
+			want:       utcDay(2025, time.September, 2),
+			holidays:   []string{"A-2025-09-01"},
+		},
+		{
+			name:       "end of month",
+			date:       utcDay(2025, time.October, 5),
+			convention: models.ConventionEndOfMonth,
+			want:       utcDay(2025, time.October, 30),
+			holidays:   []string{"A-2025-10-31"},
+		},
+		{
+			name:       "workday",
+			date:       utcDay(2025, time.October, 14),
+			convention: models.ConventionModifiedFollowing,
+			want:       utcDay(2025, time.October, 14),
+		},
+		{name: "unknown convention", date: utcDay(2025, time.October, 31), convention: "nearest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Adjust(t.Context(), testQuery(t, "entity=A"), tt.date, tt.convention)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Adjust() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !got.Adjusted.Equal(tt.want.Time) || got.Convention != tt.convention {
+				t.Errorf("Adjust() = %s %s, want %s %s", got.Adjusted, got.Convention, tt.want, tt.convention)
+			}
+
+			var holidays []string
+			for _, h := range got.Holidays {
+				holidays = append(holidays, h.ID)
+			}
+
+			if !slices.Equal(holidays, tt.holidays) {
+				t.Errorf("Adjust() holidays = %v, want %v", holidays, tt.holidays)
+			}
+		})
+	}
+}
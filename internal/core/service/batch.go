@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/internal/core/port"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Batch runs the operations in order in one transaction, a failing operation rolls back all of them.
+// The results are in the order of the operations, the returned error is the error of the failed operation.
+func (s *CalendarService) Batch(ctx context.Context, operations []models.BatchOperation, updatedBy string) ([]models.BatchResult, error) {
+	results := make([]models.BatchResult, len(operations))
+	for i := range operations {
+		results[i] = models.BatchResult{
+			Op:     operations[i].Op,
+			Status: domain.BatchStatusSkipped,
+		}
+	}
+
+	failed := -1
+	err := s.db.Transaction(ctx, func(db port.CalendarPort) error {
+		for i := range operations {
+			if err := batchOperation(ctx, db, &operations[i], &results[i], updatedBy); err != nil {
+				failed = i
+
+				return err
+			}
+
+			results[i].Status = domain.BatchStatusApplied
+		}
+
+		return nil
+	})
+	if err == nil {
+		return results, nil
+	}
+
+	for i := range results {
+		if results[i].Status == domain.BatchStatusApplied {
+			results[i].Status = domain.BatchStatusRolledBack
+		}
+	}
+
+	if failed < 0 {
+		return results, err
+	}
+
+	results[failed].Status = domain.BatchStatusFailed
+	results[failed].Error = err.Error()
+
+	return results, fmt.Errorf("operation %d %s: %w", failed, operations[failed].Op, err)
+}
+
+// batchOperation runs the operation on the port bound to the batch transaction and sets its result.
+func batchOperation(ctx context.Context, db port.CalendarPort, op *models.BatchOperation, result *models.BatchResult, updatedBy string) error {
+	switch op.Op {
+	case domain.BatchOpAddEvent:
+		events := []models.Event{*op.Event}
+		events[0].UpdatedBy = updatedBy
+
+		if err := db.AddEvents(ctx, events); err != nil {
+			return err
+		}
+
+		result.ID = events[0].ID
+		result.Version = events[0].Version
+	case domain.BatchOpUpdateEvent:
+		if err := checkVersion(op); err != nil {
+			return err
+		}
+
+		event := *op.Event
+		event.ID = op.ID
+		event.Version = op.Version
+		event.UpdatedBy = updatedBy
+
+		if err := db.UpdateEvent(ctx, op.ID, &event); err != nil {
+			return err
+		}
+
+		result.ID = op.ID
+		result.Version = event.Version
+	case domain.BatchOpRemoveEvent:
+		if err := checkVersion(op); err != nil {
+			return err
+		}
+
+		if err := db.RemoveEventVersion(ctx, op.ID, op.Version, updatedBy); err != nil {
+			return err
+		}
+
+		result.ID = op.ID
+	case domain.BatchOpAddRelation:
+		relation := *op.Relation
+		relation.UpdatedBy = updatedBy
+
+		return db.AddRelations(ctx, []models.Relation{relation})
+	case domain.BatchOpUpdateRelation:
+		to := *op.To
+		to.UpdatedBy = updatedBy
+
+		return db.UpdateRelations(ctx, []models.RelationUpdate{{From: *op.Relation, To: to}})
+	case domain.BatchOpRemoveRelation:
+		return db.RemoveRelations(ctx, []models.Relation{*op.Relation}, updatedBy)
+	default:
+		return fmt.Errorf("unknown operation: %s", op.Op)
+	}
+
+	return nil
+}
+
+// checkVersion checks the operation has the version of the event, a change without it could overwrite another one.
+func checkVersion(op *models.BatchOperation) error {
+	if op.Version == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrEventVersionRequired, op.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestBatch(t *testing.T) {
+	renamed := &models.Event{Name: "Renamed", DateFrom: utcDay(2025, time.May, 1), DateTo: utcDay(2025, time.May, 2), AllDay: true}
+	relation := &models.Relation{Entity: "A", Type: models.RelationTypeInclude, EventID: types.NewNull("batch")}
+
+	tests := []struct {
+		name       string
+		operations []models.BatchOperation
+		wantErr    error
+		wantStatus []string
+		wantName   string
+	}{
+		{
+			name: "update and relate",
+			operations: []models.BatchOperation{
+				{Op: domain.BatchOpUpdateEvent, ID: "batch", Version: 1, Event: renamed},
+				{Op: domain.BatchOpAddRelation, Relation: relation},
+			},
+			wantStatus: []string{domain.BatchStatusApplied, domain.BatchStatusApplied},
+			wantName:   "Renamed",
+		},
+		{
+			name:       "update without version",
+			operations: []models.BatchOperation{{Op: domain.BatchOpUpdateEvent, ID: "batch", Event: renamed}},
+			wantErr:    domain.ErrEventVersionRequired,
+			wantStatus: []string{domain.BatchStatusFailed},
+			wantName:   "Batch Day",
+		},
+		{
+			name:       "remove without version",
+			operations: []models.BatchOperation{{Op: domain.BatchOpRemoveEvent, ID: "batch"}},
+			wantErr:    domain.ErrEventVersionRequired,
+			wantStatus: []string{domain.BatchStatusFailed},
+			wantName:   "Batch Day",
+		},
+		{
+			name:       "remove",
+			operations: []models.BatchOperation{{Op: domain.BatchOpRemoveEvent, ID: "batch", Version: 1}},
+			wantStatus: []string{domain.BatchStatusApplied},
+		},
+		{
+			// the stale version rolls back the update before it and skips the operations after it
+			name: "stale version",
+			operations: []models.BatchOperation{
+				{Op: domain.BatchOpUpdateEvent, ID: "batch", Version: 1, Event: renamed},
+				{Op: domain.BatchOpRemoveEvent, ID: "batch", Version: 1},
+				{Op: domain.BatchOpAddRelation, Relation: relation},
+			},
+			wantErr:    domain.ErrEventVersion,
+			wantStatus: []string{domain.BatchStatusRolledBack, domain.BatchStatusFailed, domain.BatchStatusSkipped},
+			wantName:   "Batch Day",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, db := newTestService(t)
+
+			if err := db.AddEvents(t.Context(), []models.Event{
+				{ID: "batch", Name: "Batch Day", DateFrom: utcDay(2025, time.May, 1), DateTo: utcDay(2025, time.May, 2), AllDay: true},
+			}); err != nil {
+				t.Fatalf("AddEvents() error = %v", err)
+			}
+
+			results, err := svc.Batch(t.Context(), tt.operations, "admin")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Batch() error = %v, want %v", err, tt.wantErr)
+			}
+
+			status := make([]string, 0, len(results))
+			for _, r := range results {
+				status = append(status, r.Status)
+			}
+
+			if !slices.Equal(status, tt.wantStatus) {
+				t.Errorf("Batch() status = %v, want %v", status, tt.wantStatus)
+			}
+
+			event, err := db.GetEvent(t.Context(), "batch")
+			if err != nil {
+				t.Fatalf("GetEvent() error = %v", err)
+			}
+
+			var name string
+			if event != nil {
+				name = event.Name
+			}
+
+			if name != tt.wantName {
+				t.Errorf("Batch() event name = %q, want %q", name, tt.wantName)
+			}
+		})
+	}
+}
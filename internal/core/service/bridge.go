@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// bridgeMargin is the number of days checked around the year to see the spans crossing the year.
+var bridgeMargin = 14
+
+// span is a run of closed or open days.
+type span struct {
+	from, to time.Time
+	closed   bool
+}
+
+// Bridges returns the bridge days and long weekends starting in the year for the entities in the query.
+// A bridge is a run of at most days workdays between two closed runs where at least one of them has a holiday.
+// A long weekend is a closed run of more than two days with a weekend day and a holiday.
+func (s *CalendarService) Bridges(ctx context.Context, q *query.Query, year, days int) (*models.Bridges, error) {
+	yearFrom := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	yearTo := yearFrom.AddDate(1, 0, 0)
+
+	from, to := yearFrom.AddDate(0, 0, -bridgeMargin), yearTo.AddDate(0, 0, bridgeMargin)
+
+	closed, err := s.closedDays(ctx, q, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var spans []span
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		_, isClosed := closed[day]
+		if len(spans) > 0 && spans[len(spans)-1].closed == isClosed {
+			spans[len(spans)-1].to = day
+
+			continue
+		}
+
+		spans = append(spans, span{from: day, to: day, closed: isClosed})
+	}
+
+	result := &models.Bridges{
+		Year:         year,
+		Bridges:      []models.Span{},
+		LongWeekends: []models.Span{},
+	}
+
+	// first and last spans are cut by the range
+	for i := 1; i < len(spans)-1; i++ {
+		v := spans[i]
+		if v.from.Before(yearFrom) || !v.from.Before(yearTo) {
+			continue
+		}
+
+		length := int(v.to.Sub(v.from).Hours()/24) + 1
+
+		if !v.closed {
+			if length > days {
+				continue
+			}
+
+			before, after := spans[i-1], spans[i+1]
+			events := spanEvents(closed, before.from, before.to)
+			events = append(events, spanEvents(closed, after.from, after.to)...)
+			if len(events) == 0 {
+				continue
+			}
+
+			result.Bridges = append(result.Bridges, models.Span{
+				From:   types.Time{Time: v.from},
+				To:     types.Time{Time: v.to},
+				Days:   length,
+				Events: events,
+			})
+
+			continue
+		}
+
+		if length < 3 || !hasWeekend(closed, v.from, v.to) {
+			continue
+		}
+
+		events := spanEvents(closed, v.from, v.to)
+		if len(events) == 0 {
+			continue
+		}
+
+		result.LongWeekends = append(result.LongWeekends, models.Span{
+			From:   types.Time{Time: v.from},
+			To:     types.Time{Time: v.to},
+			Days:   length,
+			Events: events,
+		})
+	}
+
+	return result, nil
+}
+
+// spanEvents returns the events closing the days from the first to the last day, an event is listed once.
+func spanEvents(closed map[time.Time][]models.Event, from, to time.Time) []models.Event {
+	type key struct {
+		id   string
+		from time.Time
+	}
+
+	seen := make(map[key]struct{})
+
+	var events []models.Event
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, h := range closed[day] {
+			k := key{id: h.ID, from: h.DateFrom.Time}
+			if _, ok := seen[k]; ok {
+				continue
+			}
+
+			seen[k] = struct{}{}
+			events = append(events, h)
+		}
+	}
+
+	return events
+}
+
+// hasWeekend reports whether a day from the first to the last day is a weekend day.
+// Weekend days are closed without an event or closed by an event starting on a weekend day.
+func hasWeekend(closed map[time.Time][]models.Event, from, to time.Time) bool {
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		events, ok := closed[day]
+		if !ok {
+			continue
+		}
+
+		if len(events) == 0 {
+			return true
+		}
+
+		for _, h := range events {
+			if h.Weekend {
+				return true
+			}
+		}
+	}
+
+	return false
+}
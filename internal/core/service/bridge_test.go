@@ -0,0 +1,88 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestBridges(t *testing.T) {
+	svc, db := newTestService(t)
+
+	// thursday 14 may and monday 25 may 2026
+	addHolidays(t, db, "A", utcDay(2026, time.May, 14), utcDay(2026, time.May, 25))
+
+	spans := func(spans []models.Span) []string {
+		result := []string{}
+		for _, v := range spans {
+			result = append(result, v.From.Format(time.DateOnly)+"/"+v.To.Format(time.DateOnly))
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name         string
+		year         int
+		days         int
+		bridges      []string
+		longWeekends []string
+	}{
+		{
+			// friday after the holiday is a bridge, the weekend before the holiday on monday is a long weekend
+			name:         "one day",
+			year:         2026,
+			days:         1,
+			bridges:      []string{"2026-05-15/2026-05-15"},
+			longWeekends: []string{"2026-05-23/2026-05-25"},
+		},
+		{
+			// workdays between two plain weekends are not bridges
+			name:         "longer bridges",
+			year:         2026,
+			days:         4,
+			bridges:      []string{"2026-05-11/2026-05-13", "2026-05-15/2026-05-15", "2026-05-26/2026-05-29"},
+			longWeekends: []string{"2026-05-23/2026-05-25"},
+		},
+		{name: "other year", year: 2025, days: 4, bridges: []string{}, longWeekends: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Bridges(t.Context(), testQuery(t, "entity=A"), tt.year, tt.days)
+			if err != nil {
+				t.Fatalf("Bridges() error = %v", err)
+			}
+
+			if got.Year != tt.year {
+				t.Errorf("Bridges() year = %d, want %d", got.Year, tt.year)
+			}
+
+			if v := spans(got.Bridges); !slices.Equal(v, tt.bridges) {
+				t.Errorf("Bridges() bridges = %v, want %v", v, tt.bridges)
+			}
+
+			if v := spans(got.LongWeekends); !slices.Equal(v, tt.longWeekends) {
+				t.Errorf("Bridges() long weekends = %v, want %v", v, tt.longWeekends)
+			}
+		})
+	}
+
+	t.Run("events", func(t *testing.T) {
+		got, err := svc.Bridges(t.Context(), testQuery(t, "entity=A"), 2026, 1)
+		if err != nil {
+			t.Fatalf("Bridges() error = %v", err)
+		}
+
+		bridge, weekend := got.Bridges[0], got.LongWeekends[0]
+		if bridge.Days != 1 || len(bridge.Events) != 1 || bridge.Events[0].ID != "A-2026-05-14" {
+			t.Errorf("Bridges() bridge = %+v", bridge)
+		}
+
+		if weekend.Days != 3 || len(weekend.Events) != 1 || weekend.Events[0].ID != "A-2026-05-25" {
+			t.Errorf("Bridges() long weekend = %+v", weekend)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/internal/core/port"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// AddChangeSet stores the changes as a draft.
+// New events get their IDs here, relations of the same change-set could refer to them.
+func (s *CalendarService) AddChangeSet(ctx context.Context, changeSet *models.ChangeSet) error {
+	setChangeIDs(&changeSet.Changes.V)
+	changeSet.Status = domain.ChangeSetStatusDraft
+
+	return s.db.AddChangeSet(ctx, changeSet)
+}
+
+func (s *CalendarService) GetChangeSets(ctx context.Context, q *query.Query) ([]models.ChangeSet, error) {
+	return s.db.GetChangeSets(ctx, q)
+}
+
+func (s *CalendarService) GetChangeSetsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	return s.db.GetChangeSetsCount(ctx, q)
+}
+
+func (s *CalendarService) GetChangeSet(ctx context.Context, id string) (*models.ChangeSet, error) {
+	return s.db.GetChangeSet(ctx, id)
+}
+
+// UpdateChangeSet replaces the changes of a draft, approved change-sets are not changed anymore.
+func (s *CalendarService) UpdateChangeSet(ctx context.Context, id string, changeSet *models.ChangeSet) error {
+	setChangeIDs(&changeSet.Changes.V)
+	changeSet.Status = domain.ChangeSetStatusDraft
+
+	return s.db.UpdateChangeSet(ctx, id, domain.ChangeSetStatusDraft, changeSet)
+}
+
+// RemoveChangeSet removes a draft or a change-set waiting for its effective time.
+func (s *CalendarService) RemoveChangeSet(ctx context.Context, id string) error {
+	return s.db.RemoveChangeSet(ctx, id)
+}
+
+// ApproveChangeSet approves the draft of another user.
+// Without an effective time in the future the changes are applied in the same transaction.
+func (s *CalendarService) ApproveChangeSet(ctx context.Context, id string, approvedBy string) (*models.ChangeSet, error) {
+	var approved *models.ChangeSet
+
+	if err := s.db.Transaction(ctx, func(db port.CalendarPort) error {
+		changeSet, err := db.GetChangeSet(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if changeSet == nil {
+			return fmt.Errorf("%w: %s", domain.ErrChangeSetNotFound, id)
+		}
+
+		if changeSet.Status != domain.ChangeSetStatusDraft {
+			return fmt.Errorf("%w: %s is %s", domain.ErrChangeSetStatus, id, changeSet.Status)
+		}
+
+		if changeSet.CreatedBy == approvedBy {
+			return fmt.Errorf("%w: %s is created by %q", domain.ErrChangeSetApprover, id, approvedBy)
+		}
+
+		now := types.Time{Time: time.Now()}
+
+		changeSet.Status = domain.ChangeSetStatusApproved
+		changeSet.ApprovedAt = types.NewNull(now)
+		changeSet.ApprovedBy = types.NewNull(approvedBy)
+		changeSet.UpdatedBy = approvedBy
+
+		if !changeSet.EffectiveAt.Valid || !changeSet.EffectiveAt.V.After(now.Time) {
+			if err := db.ApplyChanges(ctx, changeSet.Changes.V, changeSet.CreatedBy); err != nil {
+				return err
+			}
+
+			changeSet.Status = domain.ChangeSetStatusApplied
+			changeSet.AppliedAt = types.NewNull(now)
+		}
+
+		approved = changeSet
+
+		return db.UpdateChangeSet(ctx, id, domain.ChangeSetStatusDraft, changeSet)
+	}); err != nil {
+		return nil, err
+	}
+
+	return approved, nil
+}
+
+// PreviewChangeSet returns the holidays of the query like GetEvents with the changes of the change-set applied.
+// The changes are rolled back after the holidays are resolved, an applied change-set shows the current calendar.
+func (s *CalendarService) PreviewChangeSet(ctx context.Context, id string, q *query.Query) ([]models.Event, error) {
+	changeSet, err := s.db.GetChangeSet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeSet == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrChangeSetNotFound, id)
+	}
+
+	var events []models.Event
+
+	if err := s.db.Preview(ctx, func(db port.CalendarPort) error {
+		if changeSet.Status != domain.ChangeSetStatusApplied {
+			if err := db.ApplyChanges(ctx, changeSet.Changes.V, changeSet.CreatedBy); err != nil {
+				return err
+			}
+		}
+
+		events, err = s.withDB(db).GetEvents(ctx, q)
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// ApplyChangeSets applies the approved change-sets reaching their effective time and returns the applied count.
+// A change-set failing to apply is marked as failed with the reason, the others are still applied.
+func (s *CalendarService) ApplyChangeSets(ctx context.Context) (int, error) {
+	changeSets, err := s.db.GetDueChangeSets(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, changeSet := range changeSets {
+		err := s.db.Transaction(ctx, func(db port.CalendarPort) error {
+			if err := db.ApplyChanges(ctx, changeSet.Changes.V, changeSet.CreatedBy); err != nil {
+				return err
+			}
+
+			appliedSet := changeSet
+			appliedSet.Status = domain.ChangeSetStatusApplied
+			appliedSet.AppliedAt = types.NewNull(types.Time{Time: time.Now()})
+
+			return db.UpdateChangeSet(ctx, changeSet.ID, domain.ChangeSetStatusApproved, &appliedSet)
+		})
+		if err == nil {
+			applied++
+
+			continue
+		}
+
+		// applied by another instance in the meantime
+		if errors.Is(err, domain.ErrChangeSetStatus) {
+			continue
+		}
+
+		log.Error().Err(err).Str("change_set", changeSet.ID).Msg("failed to apply change-set")
+
+		changeSet.Status = domain.ChangeSetStatusFailed
+		changeSet.Error = err.Error()
+		if err := s.db.UpdateChangeSet(ctx, changeSet.ID, domain.ChangeSetStatusApproved, &changeSet); err != nil && !errors.Is(err, domain.ErrChangeSetStatus) {
+			return applied, err
+		}
+	}
+
+	return applied, nil
+}
+
+// RunChangeSets applies the due change-sets in every interval until the context is done, a zero interval disables it.
+func (s *CalendarService) RunChangeSets(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := s.ApplyChangeSets(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to apply change-sets")
+		} else if applied > 0 {
+			log.Info().Int("applied", applied).Msg("applied change-sets")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// withDB returns the service reading the calendar from the port, like a preview transaction.
+func (s *CalendarService) withDB(db port.CalendarPort) *CalendarService {
+	return &CalendarService{
+		db:        db,
+		cacheRule: s.cacheRule,
+		cacheTZ:   s.cacheTZ,
+	}
+}
+
+// setChangeIDs sets the IDs of the new events without one.
+func setChangeIDs(changes *models.Changes) {
+	for i := range changes.AddEvents {
+		if changes.AddEvents[i].ID == "" {
+			changes.AddEvents[i].ID = ulid.Make().String()
+		}
+	}
+}
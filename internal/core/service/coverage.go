@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/ical"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Coverage returns the occurrence count of every entity and event group for the upcoming years.
+// Counts are compared with the current year, a year is low under the ratio of it and missing without occurrences.
+func (s *CalendarService) Coverage(ctx context.Context, years int, ratio float64) ([]models.Coverage, error) {
+	current := time.Now().Year()
+	from := time.Date(current, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(years+1, 0, 0)
+
+	relations, err := s.db.GetRelations(ctx, &query.Query{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relations: %w", err)
+	}
+
+	calendars := entityCalendars(relations)
+
+	entities := make([]string, 0, len(calendars))
+	for entity := range calendars {
+		entities = append(entities, entity)
+	}
+
+	slices.Sort(entities)
+
+	// all events are read once, entities and event groups are counted from the same pass
+	entityCounts := make(map[string]map[int]int, len(entities))
+	groupCounts := make(map[string]map[int]int)
+	err = s.eachEvent(ctx, &query.Query{}, func(h models.Event, icsRepeat *ical.Repeat) error {
+		if h.EventGroup.Valid && h.EventGroup.V != "" {
+			if groupCounts[h.EventGroup.V] == nil {
+				groupCounts[h.EventGroup.V] = make(map[int]int)
+			}
+
+			for _, occurrence := range ical.Occurrences(h, icsRepeat, from, to) {
+				groupCounts[h.EventGroup.V][occurrence.DateFrom.Year()]++
+			}
+		}
+
+		for _, entity := range entities {
+			exDates, ok := calendars[entity].event(h)
+			if !ok {
+				continue
+			}
+
+			if entityCounts[entity] == nil {
+				entityCounts[entity] = make(map[int]int)
+			}
+
+			h.ExDates = exDates
+			for _, occurrence := range ical.Occurrences(h, icsRepeat, from, to) {
+				entityCounts[entity][occurrence.DateFrom.Year()]++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count occurrences: %w", err)
+	}
+
+	// groups without events are missing
+	for _, r := range relations {
+		if r.EventGroup.Valid && r.EventGroup.V != "" && groupCounts[r.EventGroup.V] == nil {
+			groupCounts[r.EventGroup.V] = make(map[int]int)
+		}
+	}
+
+	var result []models.Coverage
+	for _, entity := range entities {
+		result = append(result, coverageYears(models.Coverage{Entity: entity}, entityCounts[entity], current, years, ratio)...)
+	}
+
+	groups := make([]string, 0, len(groupCounts))
+	for group := range groupCounts {
+		groups = append(groups, group)
+	}
+
+	slices.Sort(groups)
+
+	for _, group := range groups {
+		result = append(result, coverageYears(models.Coverage{EventGroup: group}, groupCounts[group], current, years, ratio)...)
+	}
+
+	return result, nil
+}
+
+// coverageYears returns the coverage of the upcoming years after the current year from the counts per year.
+func coverageYears(v models.Coverage, counts map[int]int, current, years int, ratio float64) []models.Coverage {
+	result := make([]models.Coverage, 0, years)
+	for year := current + 1; year <= current+years; year++ {
+		v.Year = year
+		v.Occurrences = counts[year]
+		v.Baseline = counts[current]
+
+		switch {
+		case v.Occurrences == 0:
+			v.Status = models.CoverageStatusMissing
+		case float64(v.Occurrences) < float64(v.Baseline)*ratio:
+			v.Status = models.CoverageStatusLow
+		default:
+			v.Status = models.CoverageStatusOK
+		}
+
+		result = append(result, v)
+	}
+
+	return result
+}
+
+// entityCalendar is the calendar of an entity resolved from the relations of the entity and its ancestors.
+type entityCalendar struct {
+	// branches are the entity itself and its ancestors with the path from the entity to them.
+	branches [][]string
+	// relations are the relations per entity in the branches.
+	relations map[string][]models.Relation
+}
+
+// entityCalendars resolves the calendar of every entity in the relations like the entity tree of the repository.
+// The ancestors are found through the parent relations, a path stops on cycles.
+func entityCalendars(relations []models.Relation) map[string]entityCalendar {
+	byEntity := make(map[string][]models.Relation)
+	parents := make(map[string][]string)
+	for _, r := range relations {
+		byEntity[r.Entity] = append(byEntity[r.Entity], r)
+
+		if r.Type == models.RelationTypeParent && r.Parent.Valid {
+			parents[r.Entity] = append(parents[r.Entity], r.Parent.V)
+		}
+	}
+
+	calendars := make(map[string]entityCalendar, len(byEntity))
+	for entity := range byEntity {
+		calendar := entityCalendar{relations: byEntity}
+
+		queue := [][]string{{entity}}
+		for len(queue) > 0 {
+			path := queue[0]
+			queue = queue[1:]
+			calendar.branches = append(calendar.branches, path)
+
+			for _, parent := range parents[path[len(path)-1]] {
+				if !slices.Contains(path, parent) {
+					queue = append(queue, append(slices.Clone(path), parent))
+				}
+			}
+		}
+
+		calendars[entity] = calendar
+	}
+
+	return calendars
+}
+
+// event reports the event is in the calendar and returns its excluded occurrence days.
+// The event is in the calendar when a branch includes it and no entity on the path of that branch excludes it.
+func (c entityCalendar) event(h models.Event) ([]types.Time, bool) {
+	included := slices.ContainsFunc(c.branches, func(path []string) bool {
+		if !slices.ContainsFunc(c.relations[path[len(path)-1]], func(r models.Relation) bool {
+			return r.Type == models.RelationTypeInclude && relates(r, h)
+		}) {
+			return false
+		}
+
+		return !slices.ContainsFunc(path, func(entity string) bool {
+			return slices.ContainsFunc(c.relations[entity], func(r models.Relation) bool {
+				return r.Type == models.RelationTypeExclude && !r.Occurrence.Valid && relates(r, h)
+			})
+		})
+	})
+	if !included {
+		return nil, false
+	}
+
+	var exDates []types.Time
+	for _, path := range c.branches {
+		for _, r := range c.relations[path[len(path)-1]] {
+			if r.Type == models.RelationTypeExclude && r.Occurrence.Valid && r.EventID.Valid && r.EventID.V == h.ID {
+				exDates = append(exDates, r.Occurrence.V)
+			}
+		}
+	}
+
+	return exDates, true
+}
+
+// relates reports the relation is for the event or for its group.
+func relates(r models.Relation, h models.Event) bool {
+	return (r.EventID.Valid && r.EventID.V == h.ID) ||
+		(r.EventGroup.Valid && h.EventGroup.Valid && r.EventGroup.V == h.EventGroup.V)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestCoverage(t *testing.T) {
+	svc, db := newTestService(t)
+	ctx := t.Context()
+
+	current := time.Now().Year()
+
+	// four holidays this year, two in the next year and none after it
+	addHolidays(t, db, "A",
+		utcDay(current, time.January, 10), utcDay(current, time.February, 10), utcDay(current, time.March, 10), utcDay(current, time.April, 10),
+		utcDay(current+1, time.January, 10), utcDay(current+1, time.February, 10),
+	)
+
+	if err := db.AddEvents(ctx, []models.Event{
+		{ID: "yearly", Name: "Group Day", EventGroup: types.NewNull("group"), DateFrom: utcDay(current, time.May, 10), DateTo: utcDay(current, time.May, 11), AllDay: true, RRule: "RRULE:FREQ=YEARLY"},
+	}); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+
+	if err := db.AddRelations(ctx, []models.Relation{
+		{Entity: "B", Type: models.RelationTypeInclude, EventGroup: types.NewNull("group")},
+		{Entity: "B", Type: models.RelationTypeInclude, EventGroup: types.NewNull("empty")},
+		// C inherits the holidays of A without one of the next year
+		{Entity: "C", Type: models.RelationTypeParent, Parent: types.NewNull("A")},
+		{Entity: "C", Type: models.RelationTypeExclude, EventID: types.NewNull(fmt.Sprintf("A-%d-01-10", current+1)), Occurrence: types.NewNull(utcDay(current+1, time.January, 10))},
+	}); err != nil {
+		t.Fatalf("AddRelations() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		ratio float64
+		want  map[string][]string
+	}{
+		{
+			// half of the current year is enough
+			name:  "half",
+			ratio: 0.5,
+			want: map[string][]string{
+				"A":           {"1:2/4:ok", "2:0/4:missing"},
+				"B":           {"1:1/1:ok", "2:1/1:ok"},
+				"C":           {"1:1/4:low", "2:0/4:missing"},
+				"group:group": {"1:1/1:ok", "2:1/1:ok"},
+				"group:empty": {"1:0/0:missing", "2:0/0:missing"},
+			},
+		},
+		{
+			// a higher ratio makes the next year low
+			name:  "higher ratio",
+			ratio: 0.75,
+			want: map[string][]string{
+				"A":           {"1:2/4:low", "2:0/4:missing"},
+				"B":           {"1:1/1:ok", "2:1/1:ok"},
+				"C":           {"1:1/4:low", "2:0/4:missing"},
+				"group:group": {"1:1/1:ok", "2:1/1:ok"},
+				"group:empty": {"1:0/0:missing", "2:0/0:missing"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.Coverage(ctx, 2, tt.ratio)
+			if err != nil {
+				t.Fatalf("Coverage() error = %v", err)
+			}
+
+			got := make(map[string][]string)
+			for _, v := range result {
+				key := v.Entity
+				if v.EventGroup != "" {
+					key = "group:" + v.EventGroup
+				}
+
+				got[key] = append(got[key], fmt.Sprintf("%d:%d/%d:%s", v.Year-current, v.Occurrences, v.Baseline, v.Status))
+			}
+
+			if !maps.EqualFunc(got, tt.want, slices.Equal[[]string]) {
+				t.Errorf("Coverage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/worldline-go/query"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Diff compares the holiday occurrences of the base calendar with the target calendar.
+// Holidays are matched by event ID first and then by name, matched ones on another date are moved.
+func (s *CalendarService) Diff(ctx context.Context, base, target models.DiffSide) (*models.Diff, error) {
+	baseEvents, err := s.yearOccurrences(ctx, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base occurrences: %w", err)
+	}
+
+	targetEvents, err := s.yearOccurrences(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target occurrences: %w", err)
+	}
+
+	result := &models.Diff{
+		Base:    base,
+		Target:  target,
+		Added:   []models.Event{},
+		Removed: []models.Event{},
+		Moved:   []models.Move{},
+	}
+
+	shift := target.Year - base.Year
+	sameDay := func(b, t models.Event) bool {
+		return civilDay(b.DateFrom.Time).AddDate(shift, 0, 0).Equal(civilDay(t.DateFrom.Time))
+	}
+
+	matchedBase := make([]bool, len(baseEvents))
+	matchedTarget := make([]bool, len(targetEvents))
+
+	match := func(same func(b, t models.Event) bool) {
+		for i, b := range baseEvents {
+			if matchedBase[i] {
+				continue
+			}
+
+			for j, t := range targetEvents {
+				if matchedTarget[j] || !same(b, t) {
+					continue
+				}
+
+				matchedBase[i], matchedTarget[j] = true, true
+
+				if sameDay(b, t) {
+					result.Unchanged++
+				} else {
+					result.Moved = append(result.Moved, models.Move{From: b, To: t})
+				}
+
+				break
+			}
+		}
+	}
+
+	// same event on the same day first, so repeated events are paired with their own occurrence
+	match(func(b, t models.Event) bool { return b.ID == t.ID && sameDay(b, t) })
+	match(func(b, t models.Event) bool { return b.ID == t.ID })
+	match(func(b, t models.Event) bool { return b.Name != "" && b.Name == t.Name })
+
+	for i, b := range baseEvents {
+		if !matchedBase[i] {
+			result.Removed = append(result.Removed, b)
+		}
+	}
+
+	for j, t := range targetEvents {
+		if !matchedTarget[j] {
+			result.Added = append(result.Added, t)
+		}
+	}
+
+	return result, nil
+}
+
+// yearOccurrences returns the occurrences of the entity in the year ordered by date.
+func (s *CalendarService) yearOccurrences(ctx context.Context, side models.DiffSide) ([]models.Event, error) {
+	q := entityQuery(&query.Query{Values: map[string][]query.ExpressionCmp{}}, side.Entity)
+
+	from := time.Date(side.Year, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var events []models.Event
+	err := s.eachOccurrence(ctx, q, from, from.AddDate(1, 0, 0), func(h models.Event) error {
+		events = append(events, h)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(events, func(a, b models.Event) int {
+		return a.DateFrom.Compare(b.DateFrom.Time)
+	})
+
+	return events, nil
+}
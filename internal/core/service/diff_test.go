@@ -0,0 +1,110 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestDiff(t *testing.T) {
+	svc, db := newTestService(t)
+	ctx := t.Context()
+
+	event := func(id, name string, d types.Time, rrule string) models.Event {
+		return models.Event{ID: id, Name: name, DateFrom: d, DateTo: types.Time{Time: d.AddDate(0, 0, 1)}, AllDay: true, RRule: rrule}
+	}
+
+	if err := db.AddEvents(ctx, []models.Event{
+		event("new-year", "New Year", utcDay(2025, time.January, 1), "RRULE:FREQ=YEARLY"),
+		event("easter-2025", "Easter Monday", utcDay(2025, time.April, 21), ""),
+		event("easter-2026", "Easter Monday", utcDay(2026, time.April, 6), ""),
+		event("jubilee", "Jubilee", utcDay(2025, time.June, 2), ""),
+		event("extra", "Extra Day", utcDay(2026, time.September, 1), ""),
+	}); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+
+	var relations []models.Relation
+	for _, id := range []string{"new-year", "easter-2025", "easter-2026", "jubilee", "extra"} {
+		relations = append(relations, models.Relation{Entity: "A", Type: models.RelationTypeInclude, EventID: types.NewNull(id)})
+	}
+	for _, id := range []string{"new-year", "easter-2025"} {
+		relations = append(relations, models.Relation{Entity: "B", Type: models.RelationTypeInclude, EventID: types.NewNull(id)})
+	}
+
+	if err := db.AddRelations(ctx, relations); err != nil {
+		t.Fatalf("AddRelations() error = %v", err)
+	}
+
+	addHolidays(t, db, "B", utcDay(2025, time.May, 5))
+
+	ids := func(events []models.Event) []string {
+		result := []string{}
+		for _, e := range events {
+			result = append(result, e.ID)
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		target    models.DiffSide
+		unchanged int
+		added     []string
+		removed   []string
+		moved     []string
+	}{
+		{
+			// easter is matched by its name on another day
+			name:      "next year",
+			target:    models.DiffSide{Entity: "A", Year: 2026},
+			unchanged: 1,
+			added:     []string{"extra"},
+			removed:   []string{"jubilee"},
+			moved:     []string{"easter-2025>easter-2026"},
+		},
+		{
+			name:      "other entity",
+			target:    models.DiffSide{Entity: "B", Year: 2025},
+			unchanged: 2,
+			added:     []string{"B-2025-05-05"},
+			removed:   []string{"jubilee"},
+			moved:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Diff(ctx, models.DiffSide{Entity: "A", Year: 2025}, tt.target)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+
+			if got.Unchanged != tt.unchanged {
+				t.Errorf("Diff() unchanged = %d, want %d", got.Unchanged, tt.unchanged)
+			}
+
+			if v := ids(got.Added); !slices.Equal(v, tt.added) {
+				t.Errorf("Diff() added = %v, want %v", v, tt.added)
+			}
+
+			if v := ids(got.Removed); !slices.Equal(v, tt.removed) {
+				t.Errorf("Diff() removed = %v, want %v", v, tt.removed)
+			}
+
+			moved := []string{}
+			for _, m := range got.Moved {
+				moved = append(moved, m.From.ID+">"+m.To.ID)
+			}
+
+			if !slices.Equal(moved, tt.moved) {
+				t.Errorf("Diff() moved = %v, want %v", moved, tt.moved)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"github.com/worldline-go/query"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// GetHistory returns the recorded changes of the events matching the query, like changed_by and changed_at.
+func (s *CalendarService) GetHistory(ctx context.Context, q *query.Query) ([]models.EventHistory, error) {
+	return s.db.GetHistory(ctx, q)
+}
+
+func (s *CalendarService) GetHistoryCount(ctx context.Context, q *query.Query) (uint64, error) {
+	return s.db.GetHistoryCount(ctx, q)
+}
+
+// GetEventHistory returns the recorded changes of the event, also of a removed event.
+func (s *CalendarService) GetEventHistory(ctx context.Context, id string, q *query.Query) ([]models.EventHistory, error) {
+	return s.db.GetHistory(ctx, fieldQuery(q, "event_id", id))
+}
+
+func (s *CalendarService) GetEventHistoryCount(ctx context.Context, id string, q *query.Query) (uint64, error) {
+	return s.db.GetHistoryCount(ctx, fieldQuery(q, "event_id", id))
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// DefaultHours are used for the entities without business hours, all days are open in UTC except the weekend.
+var DefaultHours = models.BusinessHours{
+	Hours: types.Map[[]models.Window]{
+		"monday":    {{From: "00:00", To: "24:00"}},
+		"tuesday":   {{From: "00:00", To: "24:00"}},
+		"wednesday": {{From: "00:00", To: "24:00"}},
+		"thursday":  {{From: "00:00", To: "24:00"}},
+		"friday":    {{From: "00:00", To: "24:00"}},
+		"saturday":  {{From: "00:00", To: "24:00"}},
+		"sunday":    {{From: "00:00", To: "24:00"}},
+	},
+}
+
+// ///////////////////////////////////////////////////////////////
+// Business Hours
+// ///////////////////////////////////////////////////////////////
+
+func (s *CalendarService) SetHours(ctx context.Context, hours *models.BusinessHours) error {
+	if err := s.db.SetHours(ctx, hours); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *CalendarService) GetHours(ctx context.Context, q *query.Query) ([]models.BusinessHours, error) {
+	hours, err := s.db.GetHours(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+func (s *CalendarService) GetHoursCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := s.db.GetHoursCount(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (s *CalendarService) GetEntityHours(ctx context.Context, entity string) (*models.BusinessHours, error) {
+	hours, err := s.db.GetEntityHours(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+func (s *CalendarService) RemoveHours(ctx context.Context, entity ...string) error {
+	if err := s.db.RemoveHours(ctx, entity...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IsOpenAt checks the business hours, holidays and half-day events of the entities at the time.
+// Multiple entities are combined with the joint mode, union needs all of them open and intersection any of them.
+func (s *CalendarService) IsOpenAt(ctx context.Context, q *query.Query, t types.Time) (*models.OpenAt, error) {
+	entities, mode, err := s.jointEntities(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.OpenAt{Time: t}
+
+	if !q.Has("joint") && len(entities) <= 1 {
+		entity := ""
+		if len(entities) == 1 {
+			entity = entities[0]
+		}
+
+		open, events, err := s.entityOpenAt(ctx, q, entity, t.Time)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Open = open
+		result.Events = events
+		if !open && entity != "" {
+			result.Closed = []string{entity}
+		}
+
+		return result, nil
+	}
+
+	openCount := 0
+	for _, entity := range entities {
+		open, events, err := s.entityOpenAt(ctx, entityQuery(q, entity), entity, t.Time)
+		if err != nil {
+			return nil, err
+		}
+
+		if open {
+			openCount++
+
+			continue
+		}
+
+		result.Closed = append(result.Closed, entity)
+		for _, e := range events {
+			if !containsOccurrence(result.Events, e) {
+				result.Events = append(result.Events, e)
+			}
+		}
+	}
+
+	if mode == models.JointModeIntersection {
+		result.Open = openCount > 0
+	} else {
+		result.Open = openCount == len(entities)
+	}
+
+	return result, nil
+}
+
+// entityOpenAt returns the open state of the entity with the events closing it at the time.
+// Weekend days and holidays close the whole day in the timezone of the business hours, half-day events only their window.
+func (s *CalendarService) entityOpenAt(ctx context.Context, q *query.Query, entity string, t time.Time) (bool, []models.Event, error) {
+	hours := &DefaultHours
+	if entity != "" {
+		entityHours, err := s.db.GetEntityHours(ctx, entity)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to get business hours: %w", err)
+		}
+
+		if entityHours != nil {
+			hours = entityHours
+		}
+	}
+
+	loc, err := s.TZLocation(hours.Tz)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get timezone location: %w", err)
+	}
+
+	local := t.In(loc)
+	if !inHours(hours, local) {
+		return false, nil, nil
+	}
+
+	day := civilDay(local)
+
+	weekends, err := s.weekends(ctx, q)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if weekends.isWeekend(day) {
+		return false, nil, nil
+	}
+
+	var events []models.Event
+	err = s.eachOccurrence(ctx, q, day.AddDate(0, 0, -1), day.AddDate(0, 0, 2), func(h models.Event) error {
+		if h.Type == models.EventTypeHalfDay {
+			if !t.Before(h.DateFrom.Time) && t.Before(h.DateTo.Time) {
+				events = append(events, h)
+			}
+
+			return nil
+		}
+
+		for _, occurrenceDay := range occurrenceDays(h) {
+			if occurrenceDay.Equal(day) {
+				events = append(events, h)
+
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	return len(events) == 0, events, nil
+}
+
+// inHours reports whether the local time is in one of the windows of its weekday.
+func inHours(hours *models.BusinessHours, local time.Time) bool {
+	minute := local.Hour()*60 + local.Minute()
+
+	for _, w := range hours.Hours[strings.ToLower(local.Weekday().String())] {
+		from, to, err := w.Minutes()
+		if err != nil {
+			continue
+		}
+
+		if minute >= from && minute < to {
+			return true
+		}
+	}
+
+	return false
+}
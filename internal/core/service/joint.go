@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/worldline-go/query"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// ///////////////////////////////////////////////////////////////
+// Joints
+// ///////////////////////////////////////////////////////////////
+
+func (s *CalendarService) AddJoints(ctx context.Context, joints []models.Joint) error {
+	if err := s.db.AddJoints(ctx, joints); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *CalendarService) GetJoints(ctx context.Context, q *query.Query) ([]models.Joint, error) {
+	joints, err := s.db.GetJoints(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return joints, nil
+}
+
+func (s *CalendarService) GetJointsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := s.db.GetJointsCount(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (s *CalendarService) GetJoint(ctx context.Context, name string) (*models.Joint, error) {
+	joint, err := s.db.GetJoint(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return joint, nil
+}
+
+func (s *CalendarService) UpdateJoint(ctx context.Context, name string, joint *models.Joint) error {
+	if err := s.db.UpdateJoint(ctx, name, joint); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *CalendarService) RemoveJoint(ctx context.Context, name ...string) error {
+	if err := s.db.RemoveJoint(ctx, name...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// jointEntities returns the entities of the query and the mode to combine them.
+// A saved joint calendar in the query gives the entities, mode in the query overrides the saved one.
+func (s *CalendarService) jointEntities(ctx context.Context, q *query.Query) ([]string, string, error) {
+	entities := q.GetValues("entity")
+	mode := q.GetValue("mode")
+
+	if name := q.GetValue("joint"); name != "" {
+		joint, err := s.db.GetJoint(ctx, name)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get joint: %w", err)
+		}
+
+		if joint == nil {
+			return nil, "", fmt.Errorf("%w: %s", domain.ErrJointNotFound, name)
+		}
+
+		entities = joint.Entities
+		if mode == "" {
+			mode = joint.Mode
+		}
+	}
+
+	if mode == "" {
+		mode = models.JointModeUnion
+	}
+
+	return entities, mode, nil
+}
+
+// combine resolves the days of every entity on its own and combines them with the joint mode.
+// Queries with a single entity and without a joint calendar are resolved directly.
+func (s *CalendarService) combine(ctx context.Context, q *query.Query, resolve func(q *query.Query) (map[time.Time][]models.Event, error)) (map[time.Time][]models.Event, error) {
+	entities, mode, err := s.jointEntities(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if !q.Has("joint") && len(entities) <= 1 {
+		return resolve(q)
+	}
+
+	var combined map[time.Time][]models.Event
+	for i, entity := range entities {
+		days, err := resolve(entityQuery(q, entity))
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			combined = days
+
+			continue
+		}
+
+		combined = combineDays(combined, days, mode)
+	}
+
+	return combined, nil
+}
+
+// combineDays merges the days of b into a.
+// With the intersection mode only the days existing in both of them are kept.
+func combineDays(a, b map[time.Time][]models.Event, mode string) map[time.Time][]models.Event {
+	if a == nil {
+		a = make(map[time.Time][]models.Event, len(b))
+	}
+
+	if mode == models.JointModeIntersection {
+		for day := range a {
+			if _, ok := b[day]; !ok {
+				delete(a, day)
+			}
+		}
+	}
+
+	for day, events := range b {
+		current, ok := a[day]
+		if !ok && mode == models.JointModeIntersection {
+			continue
+		}
+
+		for _, e := range events {
+			if !containsOccurrence(current, e) {
+				current = append(current, e)
+			}
+		}
+
+		// days without events are kept as closed
+		a[day] = current
+	}
+
+	return a
+}
+
+func containsOccurrence(events []models.Event, e models.Event) bool {
+	for _, v := range events {
+		if v.ID == e.ID && v.DateFrom.Equal(e.DateFrom.Time) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// entityQuery returns a copy of the query filtered with the given entities instead of the original ones.
+func entityQuery(q *query.Query, entities ...string) *query.Query {
+	return fieldQuery(q, "entity", entities...)
+}
+
+// fieldQuery returns a copy of the query with the field replaced with the values.
+func fieldQuery(q *query.Query, field string, values ...string) *query.Query {
+	cmp := query.ExpressionCmp{Operator: query.OperatorIn, Field: field, Value: values}
+	if len(values) == 1 {
+		cmp = query.ExpressionCmp{Operator: query.OperatorEq, Field: field, Value: values[0]}
+	}
+
+	newQuery := *q
+	newQuery.Values = maps.Clone(q.Values)
+	if newQuery.Values == nil {
+		newQuery.Values = make(map[string][]query.ExpressionCmp)
+	}
+	newQuery.Values[field] = []query.ExpressionCmp{cmp}
+	newQuery.Where = append(withoutField(q.Where, field), cmp)
+
+	return &newQuery
+}
+
+func withoutField(expressions []query.Expression, field string) []query.Expression {
+	result := make([]query.Expression, 0, len(expressions)+1)
+	for _, e := range expressions {
+		switch v := e.(type) {
+		case query.ExpressionCmp:
+			if v.Field == field {
+				continue
+			}
+		case query.ExpressionLogic:
+			v.List = withoutField(v.List, field)
+			if len(v.List) == 0 {
+				continue
+			}
+
+			e = v
+		}
+
+		result = append(result, e)
+	}
+
+	return result
+}
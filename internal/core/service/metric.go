@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// CoverageMetricInterval is the minimum time between coverage calculations of the metric.
+var CoverageMetricInterval = 5 * time.Minute
+
+type coverageCache struct {
+	m    sync.Mutex
+	at   time.Time
+	rows []models.Coverage
+}
+
+// RegisterMetrics registers the coverage gauge on the global meter provider.
+// calendar_coverage_occurrences is the occurrence count of an entity or event group in an upcoming year with its status.
+func (s *CalendarService) RegisterMetrics() error {
+	meter := otel.Meter("github.com/worldline-go/calendar")
+
+	gauge, err := meter.Int64ObservableGauge(
+		"calendar_coverage_occurrences",
+		metric.WithDescription("Occurrences of an entity or event group in an upcoming year"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create coverage gauge: %w", err)
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		rows, err := s.coverageMetric(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range rows {
+			o.ObserveInt64(gauge, int64(v.Occurrences), metric.WithAttributes(
+				attribute.String("entity", v.Entity),
+				attribute.String("event_group", v.EventGroup),
+				attribute.Int("year", v.Year),
+				attribute.String("status", v.Status),
+			))
+		}
+
+		return nil
+	}, gauge)
+	if err != nil {
+		return fmt.Errorf("failed to register coverage callback: %w", err)
+	}
+
+	return nil
+}
+
+// coverageMetric returns the coverage with the default years and ratio, calculated once in the interval.
+func (s *CalendarService) coverageMetric(ctx context.Context) ([]models.Coverage, error) {
+	s.coverage.m.Lock()
+	defer s.coverage.m.Unlock()
+
+	if time.Since(s.coverage.at) < CoverageMetricInterval {
+		return s.coverage.rows, nil
+	}
+
+	rows, err := s.Coverage(ctx, models.DefaultCoverageYears, models.DefaultCoverageRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	s.coverage.at = time.Now()
+	s.coverage.rows = rows
+
+	return rows, nil
+}
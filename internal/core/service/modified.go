@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/worldline-go/query"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Modified returns the last change of the events matching the query with the relations and weekends of its entities.
+// Disabled events are included to see them when they are enabled again.
+// All relations are used since inherited calendars depend on the relations of the other entities.
+// A joint calendar of the query adds its own change and the mode, both change the combined result.
+func (s *CalendarService) Modified(ctx context.Context, q *query.Query) (*models.Modified, error) {
+	entities, mode, err := s.jointEntities(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entities) > 0 && (q.Has("joint") || len(entities) > 1) {
+		q = entityQuery(q, entities...)
+	}
+
+	modified, err := s.db.GetModified(ctx, q, entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modified: %w", err)
+	}
+
+	modified.Mode = mode
+
+	if name := q.GetValue("joint"); name != "" {
+		joint, err := s.db.GetJoint(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get joint: %w", err)
+		}
+
+		if joint == nil {
+			return nil, fmt.Errorf("%w: %s", domain.ErrJointNotFound, name)
+		}
+
+		if joint.UpdatedAt.After(modified.UpdatedAt.Time) {
+			modified.UpdatedAt = joint.UpdatedAt
+		}
+	}
+
+	return modified, nil
+}
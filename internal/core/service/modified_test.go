@@ -0,0 +1,136 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestModified(t *testing.T) {
+	svc, db := newTestService(t)
+	ctx := t.Context()
+
+	if err := db.AddEvents(ctx, []models.Event{
+		{ID: "first", Name: "First Day", DateFrom: utcDay(2025, time.March, 1), DateTo: utcDay(2025, time.March, 2), AllDay: true, RRule: "RRULE:FREQ=YEARLY"},
+		{ID: "second", Name: "Second Day", DateFrom: utcDay(2025, time.March, 2), DateTo: utcDay(2025, time.March, 3), AllDay: true},
+	}); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+
+	if err := db.AddRelations(ctx, []models.Relation{
+		{Entity: "A", Type: models.RelationTypeInclude, EventID: types.NewNull("first")},
+		{Entity: "B", Type: models.RelationTypeInclude, EventID: types.NewNull("first")},
+		{Entity: "B", Type: models.RelationTypeInclude, EventID: types.NewNull("second")},
+	}); err != nil {
+		t.Fatalf("AddRelations() error = %v", err)
+	}
+
+	if err := db.AddWeekends(ctx, []models.Weekend{
+		{Entity: "A", EffectiveFrom: utcDay(2020, time.January, 1), Days: types.Slice[string]{"friday", "saturday"}},
+	}); err != nil {
+		t.Fatalf("AddWeekends() error = %v", err)
+	}
+
+	if err := db.AddJoints(ctx, []models.Joint{
+		{Name: "AB", Entities: types.Slice[string]{"A", "B"}, Mode: models.JointModeUnion},
+	}); err != nil {
+		t.Fatalf("AddJoints() error = %v", err)
+	}
+
+	modified := func(t *testing.T, raw string) *models.Modified {
+		t.Helper()
+
+		q, err := query.Parse(raw, query.WithSkipExpressionCmp("joint", "mode"))
+		if err != nil {
+			t.Fatalf("query.Parse() error = %v", err)
+		}
+
+		result, err := svc.Modified(ctx, q)
+		if err != nil {
+			t.Fatalf("Modified() error = %v", err)
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		events   int
+		weekends int
+		mode     string
+	}{
+		{name: "entity", query: "entity=A", events: 1, weekends: 1, mode: models.JointModeUnion},
+		{name: "joint", query: "joint=AB", events: 2, weekends: 1, mode: models.JointModeUnion},
+		{name: "entities with mode", query: "entity=A,B&mode=intersection", events: 2, weekends: 1, mode: models.JointModeIntersection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := modified(t, tt.query)
+			if got.Events != tt.events || got.Weekends != tt.weekends || got.Mode != tt.mode {
+				t.Errorf("Modified() = events %d, weekends %d, mode %s, want %d, %d, %s", got.Events, got.Weekends, got.Mode, tt.events, tt.weekends, tt.mode)
+			}
+
+			// all relations are used for the inherited calendars
+			if got.Relations != 3 {
+				t.Errorf("Modified() relations = %d, want 3", got.Relations)
+			}
+
+			if got.UpdatedAt.IsZero() {
+				t.Errorf("Modified() without the last change")
+			}
+		})
+	}
+
+	t.Run("joint change", func(t *testing.T) {
+		before := modified(t, "joint=AB")
+
+		joint, err := db.GetJoint(ctx, "AB")
+		if err != nil || joint == nil {
+			t.Fatalf("GetJoint() = %v, %v", joint, err)
+		}
+
+		// the joint changes the result without touching the events
+		joint.Mode = models.JointModeIntersection
+		if err := db.UpdateJoint(ctx, "AB", joint); err != nil {
+			t.Fatalf("UpdateJoint() error = %v", err)
+		}
+
+		after := modified(t, "joint=AB")
+		if after.Mode != models.JointModeIntersection || !after.UpdatedAt.After(before.UpdatedAt.Time) || after.Events != before.Events {
+			t.Errorf("Modified() after the joint change = %+v, before %+v", after, before)
+		}
+
+		if got := modified(t, "joint=AB&mode=union"); got.Mode != models.JointModeUnion {
+			t.Errorf("Modified() mode = %s, want the mode of the query", got.Mode)
+		}
+	})
+
+	t.Run("removal", func(t *testing.T) {
+		before := modified(t, "entity=B")
+
+		// the removal changes the calendar without an update of the rows left
+		if err := db.RemoveEvent(ctx, "admin", "second"); err != nil {
+			t.Fatalf("RemoveEvent() error = %v", err)
+		}
+
+		removed := modified(t, "entity=B")
+		if removed.Events != before.Events-1 || !removed.UpdatedAt.After(before.UpdatedAt.Time) {
+			t.Errorf("Modified() after the removal = %+v, before %+v", removed, before)
+		}
+
+		if err := db.RestoreEvent(ctx, "second", "admin"); err != nil {
+			t.Fatalf("RestoreEvent() error = %v", err)
+		}
+
+		restored := modified(t, "entity=B")
+		if restored.Events != before.Events || !restored.UpdatedAt.After(removed.UpdatedAt.Time) {
+			t.Errorf("Modified() after the restore = %+v, removed %+v", restored, removed)
+		}
+	})
+}
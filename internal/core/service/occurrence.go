@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/pkg/ical"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// eachEvent calls fn for every enabled event of the query in its own timezone.
+// Repeat is nil for the events without RRule and ExDates are filled from the occurrence exclusions.
+func (s *CalendarService) eachEvent(ctx context.Context, q *query.Query, fn func(models.Event, *ical.Repeat) error) error {
+	exDates, err := s.exclusions(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	return s.db.GetEventsWithFunc(ctx, q, func(h models.Event) error {
+		if h.Disabled {
+			return nil
+		}
+
+		s.tzTime(&h)
+		h.ExDates = exDates[h.ID]
+
+		if strings.TrimSpace(h.RRule) == "" {
+			return fn(h, nil)
+		}
+
+		icsRepeat, err := s.getRRule(ctx, h.RRule)
+		if err != nil {
+			return fmt.Errorf("failed to get rrule: %w", err)
+		}
+
+		return fn(h, icsRepeat)
+	})
+}
+
+// eachOccurrence calls fn for every occurrence overlapping with [from, to) of the events in the query.
+func (s *CalendarService) eachOccurrence(ctx context.Context, q *query.Query, from, to time.Time, fn func(models.Event) error) error {
+	return s.eachEvent(ctx, q, func(h models.Event, icsRepeat *ical.Repeat) error {
+		for _, occurrence := range ical.Occurrences(h, icsRepeat, from, to) {
+			if err := fn(occurrence); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// exclusions returns the excluded occurrence days per event ID for the entities in the query.
+// With multiple entities the events are listed once, a day is excluded only when every entity having the event excludes it.
+func (s *CalendarService) exclusions(ctx context.Context, q *query.Query) (map[string][]types.Time, error) {
+	entities := q.GetValues("entity")
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	at, err := asOf(q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entities) == 1 {
+		return s.entityExclusions(ctx, entities[0], at)
+	}
+
+	perEntity := make([]map[string][]types.Time, 0, len(entities))
+	var ids []string
+	for _, entity := range entities {
+		exDates, err := s.entityExclusions(ctx, entity, at)
+		if err != nil {
+			return nil, err
+		}
+
+		for id := range exDates {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+
+		perEntity = append(perEntity, exDates)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	exDates := make(map[string][]types.Time, len(ids))
+	resolved := make(map[string]bool, len(ids))
+	for i, entity := range entities {
+		having, err := s.entityHasEvents(ctx, q, entity, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id := range having {
+			if !resolved[id] {
+				resolved[id] = true
+				exDates[id] = perEntity[i][id]
+
+				continue
+			}
+
+			exDates[id] = slices.DeleteFunc(exDates[id], func(day types.Time) bool {
+				return !slices.ContainsFunc(perEntity[i][id], func(v types.Time) bool { return v.Equal(day.Time) })
+			})
+		}
+	}
+
+	return exDates, nil
+}
+
+// entityExclusions returns the excluded occurrence days per event ID of the entity.
+func (s *CalendarService) entityExclusions(ctx context.Context, entity string, asOf time.Time) (map[string][]types.Time, error) {
+	relations, err := s.db.GetExclusions(ctx, []string{entity}, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exclusions: %w", err)
+	}
+
+	exDates := make(map[string][]types.Time, len(relations))
+	for _, r := range relations {
+		if !r.EventID.Valid || !r.Occurrence.Valid {
+			continue
+		}
+
+		exDates[r.EventID.V] = append(exDates[r.EventID.V], r.Occurrence.V)
+	}
+
+	return exDates, nil
+}
+
+// entityHasEvents returns the IDs of the events in the calendar of the entity at the as_of time of the query.
+func (s *CalendarService) entityHasEvents(ctx context.Context, q *query.Query, entity string, ids []string) (map[string]bool, error) {
+	entityQ := &query.Query{Values: map[string][]query.ExpressionCmp{}}
+	if asOf := q.Values["as_of"]; len(asOf) > 0 {
+		entityQ.Values["as_of"] = asOf
+	}
+
+	events, err := s.db.GetEvents(ctx, fieldQuery(entityQuery(entityQ, entity), "id", ids...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events of %s: %w", entity, err)
+	}
+
+	having := make(map[string]bool, len(events))
+	for _, event := range events {
+		having[event.ID] = true
+	}
+
+	return having, nil
+}
+
+// asOf returns the as_of time of the query to resolve the calendar at that time, zero is the current calendar.
+func asOf(q *query.Query) (time.Time, error) {
+	var t types.Time
+	if v := q.GetValue("as_of"); v != "" {
+		if err := t.Parse(v); err != nil {
+			return time.Time{}, fmt.Errorf("%w %q: %w", domain.ErrAsOf, v, err)
+		}
+	}
+
+	return t.Time, nil
+}
+
+// closedDays returns the days in [from, to) closed by a holiday or the weekend, keyed with the day in UTC.
+// Weekend days without a holiday have no events, multiple entities and joint calendars are combined with the joint mode.
+func (s *CalendarService) closedDays(ctx context.Context, q *query.Query, from, to time.Time) (map[time.Time][]models.Event, error) {
+	return s.combine(ctx, q, func(q *query.Query) (map[time.Time][]models.Event, error) {
+		days, err := s.entityHolidays(ctx, q, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		weekends, err := s.weekends(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, events := range days {
+			weekends.markWeekend(events)
+		}
+
+		for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+			if _, ok := days[day]; !ok && weekends.isWeekend(day) {
+				days[day] = nil
+			}
+		}
+
+		return days, nil
+	})
+}
+
+// entityHolidays returns the days in [from, to) closed by an occurrence of the events in the query.
+// Half-day events do not close the day.
+func (s *CalendarService) entityHolidays(ctx context.Context, q *query.Query, from, to time.Time) (map[time.Time][]models.Event, error) {
+	days := make(map[time.Time][]models.Event)
+
+	// occurrences are checked on their own timezone, extend the range to catch all of them
+	err := s.eachOccurrence(ctx, q, from.AddDate(0, 0, -1), to.AddDate(0, 0, 1), func(h models.Event) error {
+		if h.Type == models.EventTypeHalfDay {
+			return nil
+		}
+
+		for _, day := range occurrenceDays(h) {
+			if day.Before(from) || !day.Before(to) {
+				continue
+			}
+
+			days[day] = append(days[day], h)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
+// occurrenceDays returns the days covered by the occurrence in its own timezone.
+func occurrenceDays(h models.Event) []time.Time {
+	var days []time.Time
+
+	last := h.DateTo.Time
+	if last.After(h.DateFrom.Time) {
+		last = last.Add(-time.Nanosecond)
+	}
+
+	for day := civilDay(h.DateFrom.Time); !day.After(civilDay(last)); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+
+	return days
+}
+
+// civilDay returns the calendar day of the time as UTC midnight.
+func civilDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestExclusions(t *testing.T) {
+	svc, db := newTestService(t)
+	ctx := t.Context()
+
+	if err := db.AddEvents(ctx, []models.Event{
+		{ID: "labour", Name: "Labour Day", DateFrom: utcDay(2025, time.May, 1), DateTo: utcDay(2025, time.May, 2), AllDay: true, RRule: "RRULE:FREQ=YEARLY"},
+		{ID: "local", Name: "Local Day", DateFrom: utcDay(2025, time.June, 1), DateTo: utcDay(2025, time.June, 2), AllDay: true, RRule: "RRULE:FREQ=YEARLY"},
+	}); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+
+	include := func(entity, id string) models.Relation {
+		return models.Relation{Entity: entity, Type: models.RelationTypeInclude, EventID: types.NewNull(id)}
+	}
+	exclude := func(entity, id string, occurrence types.Time) models.Relation {
+		return models.Relation{Entity: entity, Type: models.RelationTypeExclude, EventID: types.NewNull(id), Occurrence: types.NewNull(occurrence)}
+	}
+
+	// only A excludes the labour day of 2026, B keeps it
+	if err := db.AddRelations(ctx, []models.Relation{
+		include("A", "labour"),
+		include("B", "labour"),
+		include("A", "local"),
+		exclude("A", "labour", utcDay(2026, time.May, 1)),
+		exclude("A", "local", utcDay(2026, time.June, 1)),
+	}); err != nil {
+		t.Fatalf("AddRelations() error = %v", err)
+	}
+
+	t.Run("exdates", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			entity string
+			want   map[string][]string
+		}{
+			{name: "excluding entity", entity: "A", want: map[string][]string{"labour": {"2026-05-01"}, "local": {"2026-06-01"}}},
+			{name: "other entity", entity: "B", want: map[string][]string{"labour": {}}},
+			// B keeps the labour day, the local day is only in A and keeps its exclusion
+			{name: "multiple entities", entity: "A,B", want: map[string][]string{"labour": {}, "local": {"2026-06-01"}}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				q, err := query.Parse("entity=" + tt.entity)
+				if err != nil {
+					t.Fatalf("query.Parse() error = %v", err)
+				}
+
+				events, err := svc.GetDefinitions(ctx, q)
+				if err != nil {
+					t.Fatalf("GetDefinitions() error = %v", err)
+				}
+
+				got := make(map[string][]string, len(events))
+				for _, e := range events {
+					days := []string{}
+					for _, d := range e.ExDates {
+						days = append(days, d.Format(time.DateOnly))
+					}
+
+					slices.Sort(days)
+					got[e.ID] = days
+				}
+
+				if !maps.EqualFunc(got, tt.want, slices.Equal[[]string]) {
+					t.Errorf("GetDefinitions() exdates = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("holidays", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			entity string
+			want   int
+		}{
+			{name: "excluding entity", entity: "A", want: 0},
+			// the holidays of the day are resolved for every entity on its own
+			{name: "multiple entities", entity: "A,B", want: 1},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				q, err := query.Parse("entity="+tt.entity+"&date=2026-05-01", query.WithSkipExpressionCmp("date", "joint", "mode", "as_of"))
+				if err != nil {
+					t.Fatalf("query.Parse() error = %v", err)
+				}
+
+				events, err := svc.GetEvents(ctx, q)
+				if err != nil {
+					t.Fatalf("GetEvents() error = %v", err)
+				}
+
+				if len(events) != tt.want {
+					t.Errorf("GetEvents() = %d events, want %d", len(events), tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestAsOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    time.Time
+		wantErr error
+	}{
+		{name: "current", query: "entity=A"},
+		{name: "time", query: "entity=A&as_of=2025-05-01T10:00:00Z", want: time.Date(2025, time.May, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "invalid", query: "entity=A&as_of=yesterday", wantErr: domain.ErrAsOf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := asOf(testQuery(t, tt.query))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("asOf() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("asOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/ical"
+)
+
+// Schedule returns the business days of the schedule spec in [from, to) for the entities in the query.
+// Business days are counted in every period of the schedule, after the weekend and holidays are removed.
+func (s *CalendarService) Schedule(ctx context.Context, q *query.Query, spec string, from, to types.Time) ([]types.Time, error) {
+	schedule, err := ical.ParseSchedule(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+
+	rangeFrom, rangeTo := civilDay(from.Time), civilDay(to.Time)
+
+	periods := schedule.Periods(rangeFrom, rangeTo)
+	if len(periods) == 0 {
+		return nil, nil
+	}
+
+	closed, err := s.closedDays(ctx, q, periods[0].Start, periods[len(periods)-1].End)
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []types.Time
+	for _, period := range periods {
+		var open []time.Time
+		for day := period.Start; day.Before(period.End); day = day.AddDate(0, 0, 1) {
+			if _, ok := closed[day]; !ok {
+				open = append(open, day)
+			}
+		}
+
+		for _, day := range schedule.Pick(open) {
+			if day.Before(rangeFrom) || !day.Before(rangeTo) {
+				continue
+			}
+
+			dates = append(dates, types.Time{Time: day})
+
+			if schedule.Count != nil && len(dates) >= *schedule.Count {
+				return dates, nil
+			}
+		}
+	}
+
+	return dates, nil
+}
@@ -4,9 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"slices"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -15,6 +13,7 @@ import (
 	"github.com/worldline-go/query"
 	"github.com/worldline-go/types"
 
+	"github.com/worldline-go/calendar/internal/core/domain"
 	"github.com/worldline-go/calendar/internal/core/port"
 	"github.com/worldline-go/calendar/pkg/ical"
 	"github.com/worldline-go/calendar/pkg/models"
@@ -24,6 +23,7 @@ type CalendarService struct {
 	db        port.CalendarPort
 	cacheRule cache.Cacher[string, *ical.Repeat]
 	cacheTZ   cache.Cacher[string, *time.Location]
+	coverage  coverageCache
 	m         sync.RWMutex
 }
 
@@ -59,11 +59,6 @@ func NewCalendarService(ctx context.Context, db port.CalendarPort) (*CalendarSer
 	}, nil
 }
 
-// WorkDay returns the next workday after the given date.
-func (s *CalendarService) WorkDay(ctx context.Context, date types.Time) (types.Time, error) {
-	return types.Time{Time: time.Now()}, nil
-}
-
 // //////////////////////////////////////////////////////////////
 // Database
 // //////////////////////////////////////////////////////////////
@@ -76,9 +71,45 @@ func (s *CalendarService) AddEvents(ctx context.Context, events []models.Event)
 	return nil
 }
 
-func (s *CalendarService) RemoveEvent(ctx context.Context, id ...string) error {
-	err := s.db.RemoveEvent(ctx, id...)
-	if err != nil {
+// AddEventWithRelations adds a new event and its relations in one transaction, nothing is added when one of them fails.
+// Unlike AddEvents a stored event with the ID is not skipped, it returns ErrEventExists.
+func (s *CalendarService) AddEventWithRelations(ctx context.Context, event *models.Event, relations []models.Relation) error {
+	return s.db.Transaction(ctx, func(db port.CalendarPort) error {
+		stored, err := db.GetEvent(ctx, event.ID)
+		if err != nil {
+			return err
+		}
+
+		if stored != nil {
+			return fmt.Errorf("%w: %s", domain.ErrEventExists, event.ID)
+		}
+
+		events := []models.Event{*event}
+		if err := db.AddEvents(ctx, events); err != nil {
+			return err
+		}
+
+		*event = events[0]
+
+		return db.AddRelations(ctx, relations)
+	})
+}
+
+// RemoveEvents moves the events to the trash in one transaction, all of them are kept when one doesn't have its version.
+func (s *CalendarService) RemoveEvents(ctx context.Context, events []models.EventVersion, removedBy string) error {
+	return s.db.Transaction(ctx, func(db port.CalendarPort) error {
+		for _, e := range events {
+			if err := db.RemoveEventVersion(ctx, e.ID, e.Version, removedBy); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *CalendarService) RemoveEventVersion(ctx context.Context, id string, version int64, removedBy string) error {
+	if err := s.db.RemoveEventVersion(ctx, id, version, removedBy); err != nil {
 		return err
 	}
 
@@ -96,8 +127,6 @@ func (s *CalendarService) GetEventsCount(ctx context.Context, q *query.Query) (u
 
 func (s *CalendarService) GetEvents(ctx context.Context, q *query.Query) ([]models.Event, error) {
 	if q.HasAny("date") {
-		var events []models.Event
-
 		qDateCheck := types.Time{}
 		if qDate, _ := q.Values["date"]; len(qDate) > 0 {
 			qDateStr, ok := qDate[0].Value.(string)
@@ -109,55 +138,32 @@ func (s *CalendarService) GetEvents(ctx context.Context, q *query.Query) ([]mode
 			}
 		}
 
-		err := s.db.GetEventsWithFunc(ctx, q, func(h models.Event) error {
-			if h.Disabled {
-				return nil
-			}
-
-			s.tzTime(&h)
-
-			if strings.TrimSpace(h.RRule) == "" {
-				if !qDateCheck.Time.Before(h.DateFrom.Time) && qDateCheck.Time.Before(h.DateTo.Time) {
-					events = append(events, h)
-				}
+		// occurrences containing the date
+		days, err := s.combine(ctx, q, func(q *query.Query) (map[time.Time][]models.Event, error) {
+			var occurrences []models.Event
+			err := s.eachOccurrence(ctx, q, qDateCheck.Time, qDateCheck.Add(time.Nanosecond), func(h models.Event) error {
+				occurrences = append(occurrences, h)
 
 				return nil
+			})
+			if err != nil || len(occurrences) == 0 {
+				return nil, err
 			}
 
-			icsRepeat, err := s.getRRule(ctx, h.RRule)
+			weekends, err := s.weekends(ctx, q)
 			if err != nil {
-				return fmt.Errorf("failed to get rrule: %w", err)
+				return nil, err
 			}
 
-			for _, rrule := range icsRepeat.RRule {
-				start, stop, ok := ical.MatchRRuleAt(rrule, h.DateFrom.Time, h.DateTo.Time, qDateCheck.Time)
-				if !ok {
-					return nil
-				}
-
-				h.DateFrom.Time = start
-				h.DateTo.Time = stop
-
-				events = append(events, h)
-			}
-
-			for _, yearFn := range icsRepeat.Func {
-				newDate := yearFn(qDateCheck.Year())
-				h.DateFrom.Time = newDate
-				h.DateTo.Time = h.DateFrom.Time.AddDate(0, 0, 1)
-
-				if !qDateCheck.Time.Before(h.DateFrom.Time) && qDateCheck.Time.Before(h.DateTo.Time) {
-					events = append(events, h)
-				}
-			}
+			weekends.markWeekend(occurrences)
 
-			return nil
+			return map[time.Time][]models.Event{qDateCheck.Time: occurrences}, nil
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		return events, nil
+		return days[qDateCheck.Time], nil
 	}
 
 	events, err := s.db.GetEvents(ctx, q)
@@ -198,24 +204,50 @@ func (s *CalendarService) GetEventsICS(ctx context.Context, q *query.Query) ([]m
 		qYearCheck = append(qYearCheck, year-1, year, year+1, year+2)
 	}
 
-	err := s.db.GetEventsWithFunc(ctx, q, func(h models.Event) error {
-		if h.Disabled {
-			return nil
+	// joint calendars are listed with the events of all entities
+	if q.Has("joint") {
+		entities, _, err := s.jointEntities(ctx, q)
+		if err != nil {
+			return nil, err
 		}
 
-		s.tzTime(&h)
+		if len(entities) == 0 {
+			return nil, nil
+		}
 
-		if strings.TrimSpace(h.RRule) == "" {
-			if slices.Contains(qYearCheck, h.DateFrom.Year()) {
-				events = append(events, h)
-			}
+		q = entityQuery(q, entities...)
+	}
 
-			return nil
+	// an occurrence overlapping two of the years is listed once, special functions repeat the ID on other dates
+	listed := make(map[string]bool)
+
+	err := s.eachEvent(ctx, q, func(h models.Event, icsRepeat *ical.Repeat) error {
+		// single events and special functions are listed with their dates
+		var dated *ical.Repeat
+		if icsRepeat != nil {
+			dated = &ical.Repeat{Func: icsRepeat.Func}
 		}
 
-		icsRepeat, err := s.getRRule(ctx, h.RRule)
-		if err != nil {
-			return fmt.Errorf("failed to get rrule: %w", err)
+		if icsRepeat == nil || len(icsRepeat.Func) > 0 {
+			for _, year := range qYearCheck {
+				yearTime := time.Date(year, 1, 1, 0, 0, 0, 0, h.DateFrom.Location())
+				for _, occurrence := range ical.Occurrences(h, dated, yearTime, yearTime.AddDate(1, 0, 0)) {
+					occurrence.RRule = ""
+					occurrence.ExDates = nil
+
+					key := occurrence.ID + "/" + occurrence.DateFrom.Format(time.RFC3339)
+					if listed[key] {
+						continue
+					}
+
+					listed[key] = true
+					events = append(events, occurrence)
+				}
+			}
+		}
+
+		if icsRepeat == nil {
+			return nil
 		}
 
 		for _, rrule := range icsRepeat.RRule {
@@ -243,17 +275,22 @@ func (s *CalendarService) GetEventsICS(ctx context.Context, q *query.Query) ([]m
 			break
 		}
 
-		for _, yearFn := range icsRepeat.Func {
-			for year := range qYearCheck {
-				newDate := yearFn(year)
-				h.DateFrom.Time = newDate
-				h.DateTo.Time = h.DateFrom.Time.AddDate(0, 0, 1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-				if h.DateFrom.Year() == year {
-					events = append(events, h)
-				}
-			}
-		}
+	return events, nil
+}
+
+// GetDefinitions returns the enabled events of the query in their own timezone with the resolved exclusions.
+// Occurrences of the definitions are the same as the ones calculated by the service.
+func (s *CalendarService) GetDefinitions(ctx context.Context, q *query.Query) ([]models.Event, error) {
+	events := []models.Event{}
+
+	err := s.eachEvent(ctx, q, func(h models.Event, _ *ical.Repeat) error {
+		events = append(events, h)
 
 		return nil
 	})
@@ -278,7 +315,7 @@ func (s *CalendarService) tzTime(h *models.Event) error {
 
 func (s *CalendarService) GetEvent(ctx context.Context, id string) (*models.Event, error) {
 	h, err := s.db.GetEvent(ctx, id)
-	if err != nil {
+	if err != nil || h == nil {
 		return nil, err
 	}
 
@@ -308,8 +345,16 @@ func (s *CalendarService) AddRelations(ctx context.Context, relations []models.R
 	return nil
 }
 
-func (s *CalendarService) RemoveRelation(ctx context.Context, q *query.Query) error {
-	err := s.db.RemoveRelation(ctx, q)
+func (s *CalendarService) UpdateRelations(ctx context.Context, updates []models.RelationUpdate) error {
+	if err := s.db.UpdateRelations(ctx, updates); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *CalendarService) RemoveRelation(ctx context.Context, q *query.Query, removedBy string) error {
+	err := s.db.RemoveRelation(ctx, q, removedBy)
 	if err != nil {
 		return err
 	}
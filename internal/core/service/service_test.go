@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/adapter/memory"
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// newTestService returns the service on an in-memory calendar.
+func newTestService(t *testing.T) (*CalendarService, *memory.Memory) {
+	t.Helper()
+
+	db, err := memory.New("")
+	if err != nil {
+		t.Fatalf("memory.New() error = %v", err)
+	}
+
+	svc, err := NewCalendarService(t.Context(), db)
+	if err != nil {
+		t.Fatalf("NewCalendarService() error = %v", err)
+	}
+
+	return svc, db
+}
+
+// addHolidays adds single day holidays to the entity, the events are named with the entity and the day.
+func addHolidays(t *testing.T, db *memory.Memory, entity string, days ...types.Time) {
+	t.Helper()
+
+	events := make([]models.Event, 0, len(days))
+	relations := make([]models.Relation, 0, len(days))
+	for _, d := range days {
+		id := entity + "-" + d.Format(time.DateOnly)
+
+		events = append(events, models.Event{ID: id, Name: id, DateFrom: d, DateTo: types.Time{Time: d.AddDate(0, 0, 1)}, AllDay: true})
+		relations = append(relations, models.Relation{Entity: entity, Type: models.RelationTypeInclude, EventID: types.NewNull(id)})
+	}
+
+	if err := db.AddEvents(t.Context(), events); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+
+	if err := db.AddRelations(t.Context(), relations); err != nil {
+		t.Fatalf("AddRelations() error = %v", err)
+	}
+}
+
+// testQuery parses the query like the search endpoints, the special values are kept out of the filter.
+func testQuery(t *testing.T, raw string) *query.Query {
+	t.Helper()
+
+	q, err := query.Parse(raw, query.WithSkipExpressionCmp("date", "joint", "mode", "as_of"))
+	if err != nil {
+		t.Fatalf("query.Parse() error = %v", err)
+	}
+
+	return q
+}
+
+func utcDay(year int, month time.Month, d int) types.Time {
+	return types.Time{Time: time.Date(year, month, d, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestGetEvent(t *testing.T) {
+	svc, db := newTestService(t)
+
+	if err := db.AddEvents(t.Context(), []models.Event{
+		{ID: "new-year", Name: "New Year", DateFrom: utcDay(2025, time.January, 1), DateTo: utcDay(2025, time.January, 2), Tz: "Europe/Amsterdam", AllDay: true},
+	}); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		wantName string
+	}{
+		{name: "stored", id: "new-year", wantName: "New Year"},
+		{name: "missing", id: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetEvent(t.Context(), tt.id)
+			if err != nil {
+				t.Fatalf("GetEvent() error = %v", err)
+			}
+
+			if tt.wantName == "" {
+				if got != nil {
+					t.Errorf("GetEvent() = %+v, want nil", got)
+				}
+
+				return
+			}
+
+			if got == nil || got.Name != tt.wantName {
+				t.Errorf("GetEvent() = %+v, want %s", got, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestGetEventsICS(t *testing.T) {
+	svc, db := newTestService(t)
+
+	if err := db.AddEvents(t.Context(), []models.Event{
+		{ID: "new-year", Name: "New Year", DateFrom: utcDay(2025, time.December, 31), DateTo: utcDay(2026, time.January, 2), AllDay: true},
+		{ID: "easter", Name: "Easter Monday", DateFrom: utcDay(2025, time.April, 21), DateTo: utcDay(2025, time.April, 22), AllDay: true, RRule: "FUNC:EasterMonday"},
+	}); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "one year", query: "id=new-year&year=2025", want: []string{"new-year/2025-12-31"}},
+		// the event overlaps both years and is listed once
+		{name: "overlapping years", query: "id=new-year&year=2025&year=2026", want: []string{"new-year/2025-12-31"}},
+		{name: "function per year", query: "id=easter&year=2025&year=2026", want: []string{"easter/2025-04-21", "easter/2026-04-06"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := query.Parse(tt.query, query.WithSkipExpressionCmp("year"))
+			if err != nil {
+				t.Fatalf("query.Parse() error = %v", err)
+			}
+
+			events, err := svc.GetEventsICS(t.Context(), q)
+			if err != nil {
+				t.Fatalf("GetEventsICS() error = %v", err)
+			}
+
+			got := make([]string, 0, len(events))
+			for _, e := range events {
+				got = append(got, e.ID+"/"+e.DateFrom.Format(time.DateOnly))
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetEventsICS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []models.EventVersion
+		wantErr  error
+		wantKept []string
+	}{
+		{name: "versions", events: []models.EventVersion{{ID: "first", Version: 1}, {ID: "second", Version: 1}}},
+		// a stale version keeps all of the events
+		{name: "stale version", events: []models.EventVersion{{ID: "first", Version: 1}, {ID: "second", Version: 2}}, wantErr: domain.ErrEventVersion, wantKept: []string{"first", "second"}},
+		{name: "missing event", events: []models.EventVersion{{ID: "first", Version: 1}, {ID: "missing", Version: 1}}, wantErr: domain.ErrEventNotFound, wantKept: []string{"first", "second"}},
+		{name: "one of them", events: []models.EventVersion{{ID: "second", Version: 1}}, wantKept: []string{"first"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, db := newTestService(t)
+
+			if err := db.AddEvents(t.Context(), []models.Event{
+				{ID: "first", Name: "First", DateFrom: utcDay(2025, time.April, 1), DateTo: utcDay(2025, time.April, 2), AllDay: true},
+				{ID: "second", Name: "Second", DateFrom: utcDay(2025, time.April, 2), DateTo: utcDay(2025, time.April, 3), AllDay: true},
+			}); err != nil {
+				t.Fatalf("AddEvents() error = %v", err)
+			}
+
+			if err := svc.RemoveEvents(t.Context(), tt.events, "admin"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RemoveEvents() error = %v, want %v", err, tt.wantErr)
+			}
+
+			events, err := db.GetEvents(t.Context(), &query.Query{})
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+
+			kept := []string{}
+			for _, e := range events {
+				kept = append(kept, e.ID)
+			}
+
+			slices.Sort(kept)
+			if !slices.Equal(kept, tt.wantKept) {
+				t.Errorf("RemoveEvents() kept %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Settlement returns the settlement date of the trade after the given workdays for the entities in the query.
+// The trade is booked on the next workday when it is after the cut-off or not on a workday.
+// Multiple entities are combined with the joint mode, union needs a workday in all of them.
+func (s *CalendarService) Settlement(ctx context.Context, q *query.Query, req models.SettlementRequest) (*models.Settlement, error) {
+	loc, err := s.settlementLocation(ctx, q, req.Tz)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.Settlement{Trade: req.Trade}
+
+	local := req.Trade.In(loc)
+	tradeDay := civilDay(local)
+
+	if req.CutOff != "" {
+		cutOff, err := time.Parse("15:04", req.CutOff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cut-off %q: %w", req.CutOff, err)
+		}
+
+		if local.Hour()*60+local.Minute() >= cutOff.Hour()*60+cutOff.Minute() {
+			result.AfterCutOff = true
+			tradeDay = tradeDay.AddDate(0, 0, 1)
+		}
+	}
+
+	tradeDate, holidays, err := s.walkWorkDays(ctx, q, tradeDay, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	date := tradeDate
+	if req.Days > 0 {
+		var skipped []models.Event
+
+		date, skipped, err = s.walkWorkDays(ctx, q, tradeDate.AddDate(0, 0, 1), req.Days, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range skipped {
+			if !containsOccurrence(holidays, e) {
+				holidays = append(holidays, e)
+			}
+		}
+	}
+
+	result.TradeDate = types.Time{Time: tradeDate}
+	result.Date = types.Time{Time: date}
+	result.Holidays = holidays
+
+	return result, nil
+}
+
+// settlementLocation returns the timezone of the cut-off.
+// Without the tz, timezone of the first entity business hours is used and UTC at last.
+func (s *CalendarService) settlementLocation(ctx context.Context, q *query.Query, tz string) (*time.Location, error) {
+	if tz == "" {
+		entities, _, err := s.jointEntities(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(entities) > 0 {
+			hours, err := s.db.GetEntityHours(ctx, entities[0])
+			if err != nil {
+				return nil, fmt.Errorf("failed to get business hours: %w", err)
+			}
+
+			if hours != nil {
+				tz = hours.Tz
+			}
+		}
+	}
+
+	loc, err := s.TZLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timezone location: %w", err)
+	}
+
+	return loc, nil
+}
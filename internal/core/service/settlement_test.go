@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestSettlement(t *testing.T) {
+	svc, db := newTestService(t)
+
+	// friday after christmas is closed, the weekend follows it
+	addHolidays(t, db, "A", utcDay(2025, time.December, 26))
+
+	tests := []struct {
+		name        string
+		trade       time.Time
+		days        int
+		tz          string
+		afterCutOff bool
+		tradeDate   types.Time
+		date        types.Time
+		holidays    int
+	}{
+		{
+			name:      "before the cut-off",
+			trade:     time.Date(2025, time.December, 24, 10, 0, 0, 0, time.UTC),
+			days:      1,
+			tz:        "UTC",
+			tradeDate: utcDay(2025, time.December, 24),
+			date:      utcDay(2025, time.December, 25),
+		},
+		{
+			// the trade rolls to the holiday, it is booked on the next workday after the weekend
+			name:        "after the cut-off",
+			trade:       time.Date(2025, time.December, 25, 18, 0, 0, 0, time.UTC),
+			days:        2,
+			tz:          "UTC",
+			afterCutOff: true,
+			tradeDate:   utcDay(2025, time.December, 29),
+			date:        utcDay(2025, time.December, 31),
+			holidays:    1,
+		},
+		{
+			// 16:30 UTC is after 17:00 in Amsterdam
+			name:        "cut-off in the timezone",
+			trade:       time.Date(2025, time.December, 24, 16, 30, 0, 0, time.UTC),
+			days:        1,
+			tz:          "Europe/Amsterdam",
+			afterCutOff: true,
+			tradeDate:   utcDay(2025, time.December, 25),
+			date:        utcDay(2025, time.December, 29),
+			holidays:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Settlement(t.Context(), testQuery(t, "entity=A"), models.SettlementRequest{
+				Trade:  types.Time{Time: tt.trade},
+				Days:   tt.days,
+				CutOff: "17:00",
+				Tz:     tt.tz,
+			})
+			if err != nil {
+				t.Fatalf("Settlement() error = %v", err)
+			}
+
+			if got.AfterCutOff != tt.afterCutOff {
+				t.Errorf("Settlement() after cut-off = %v, want %v", got.AfterCutOff, tt.afterCutOff)
+			}
+
+			if !got.TradeDate.Equal(tt.tradeDate.Time) || !got.Date.Equal(tt.date.Time) {
+				t.Errorf("Settlement() = trade %s, date %s, want %s, %s", got.TradeDate, got.Date, tt.tradeDate, tt.date)
+			}
+
+			if len(got.Holidays) != tt.holidays {
+				t.Errorf("Settlement() holidays = %v, want %d", got.Holidays, tt.holidays)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/worldline-go/query"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// GetTrashEvents returns the removed events waiting for the purge, like deleted_by and deleted_at.
+func (s *CalendarService) GetTrashEvents(ctx context.Context, q *query.Query) ([]models.Event, error) {
+	return s.db.GetTrashEvents(ctx, q)
+}
+
+func (s *CalendarService) GetTrashEventsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	return s.db.GetTrashEventsCount(ctx, q)
+}
+
+// GetTrashRelations returns the removed relations waiting for the purge, also the ones removed with their event.
+func (s *CalendarService) GetTrashRelations(ctx context.Context, q *query.Query) ([]models.Relation, error) {
+	return s.db.GetTrashRelations(ctx, q)
+}
+
+func (s *CalendarService) GetTrashRelationsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	return s.db.GetTrashRelationsCount(ctx, q)
+}
+
+// RestoreEvent takes the event back from the trash with the relations removed together with it.
+func (s *CalendarService) RestoreEvent(ctx context.Context, id string, restoredBy string) error {
+	return s.db.RestoreEvent(ctx, id, restoredBy)
+}
+
+// RestoreRelations takes the matching relations back from the trash, relations of an event in the trash are skipped.
+func (s *CalendarService) RestoreRelations(ctx context.Context, q *query.Query, restoredBy string) error {
+	return s.db.RestoreRelations(ctx, q, restoredBy)
+}
+
+// PurgeTrash removes the events and relations staying in the trash longer than the retention permanently.
+func (s *CalendarService) PurgeTrash(ctx context.Context, retention time.Duration) (uint64, error) {
+	return s.db.PurgeTrash(ctx, time.Now().Add(-retention))
+}
+
+// RunPurge purges the trash in every interval until the context is done, a zero interval disables it.
+func (s *CalendarService) RunPurge(ctx context.Context, retention, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeTrash(ctx, retention)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to purge trash")
+		} else if purged > 0 {
+			log.Info().Uint64("purged", purged).Dur("retention", retention).Msg("purged trash")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
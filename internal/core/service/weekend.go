@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/worldline-go/query"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// DefaultWeekend is used for the days before the first weekend pattern of an entity.
+var DefaultWeekend = []string{"saturday", "sunday"}
+
+// ///////////////////////////////////////////////////////////////
+// Weekends
+// ///////////////////////////////////////////////////////////////
+
+func (s *CalendarService) AddWeekends(ctx context.Context, weekends []models.Weekend) error {
+	if err := s.db.AddWeekends(ctx, weekends); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *CalendarService) RemoveWeekends(ctx context.Context, q *query.Query) error {
+	if err := s.db.RemoveWeekends(ctx, q); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *CalendarService) GetWeekends(ctx context.Context, q *query.Query) ([]models.Weekend, error) {
+	weekends, err := s.db.GetWeekends(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return weekends, nil
+}
+
+func (s *CalendarService) GetWeekendsCount(ctx context.Context, q *query.Query) (uint64, error) {
+	count, err := s.db.GetWeekendsCount(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// weekendHistory is the weekend patterns of an entity ordered by the effective day.
+type weekendHistory []models.Weekend
+
+// isWeekend reports whether the day, as UTC midnight, is a weekend day with the pattern effective on that day.
+func (w weekendHistory) isWeekend(day time.Time) bool {
+	days := DefaultWeekend
+	for _, v := range w {
+		if civilDay(v.EffectiveFrom.Time).After(day) {
+			break
+		}
+
+		days = v.Days
+	}
+
+	return slices.Contains(days, strings.ToLower(day.Weekday().String()))
+}
+
+// weekends returns the weekend history of the single entity in the query.
+// Queries without an entity or with multiple entities use the default weekend.
+func (s *CalendarService) weekends(ctx context.Context, q *query.Query) (weekendHistory, error) {
+	entities := q.GetValues("entity")
+	if len(entities) != 1 {
+		return nil, nil
+	}
+
+	weekends, err := s.db.GetEntityWeekends(ctx, entities[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekends: %w", err)
+	}
+
+	return weekends, nil
+}
+
+// markWeekend flags the events starting on a weekend day.
+func (w weekendHistory) markWeekend(events []models.Event) {
+	for i := range events {
+		events[i].Weekend = w.isWeekend(civilDay(events[i].DateFrom.Time))
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+var (
+	// WorkDaySearchLimit is the maximum number of days to look for a workday.
+	WorkDaySearchLimit = 366
+	// workDayWindow is the number of days resolved at once while looking for a workday.
+	workDayWindow = 31
+)
+
+var ErrNoWorkDay = errors.New("no workday found")
+
+// WorkDay returns the n-th workday after the given date for the entities in the query, days is at least 1.
+// Weekend days of the entity and the days with a holiday occurrence are skipped, multiple entities are combined with the joint mode.
+func (s *CalendarService) WorkDay(ctx context.Context, q *query.Query, date types.Time, days int) (*models.WorkDay, error) {
+	if days < 1 {
+		days = 1
+	}
+
+	day, holidays, err := s.walkWorkDays(ctx, q, civilDay(date.Time).AddDate(0, 0, 1), days, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WorkDay{
+		Date:     types.Time{Time: day},
+		Holidays: holidays,
+	}, nil
+}
+
+// walkWorkDays returns the count-th workday starting from the day itself, with the holidays skipped on the way.
+// Step is 1 to walk forward and -1 to walk backward, days are UTC midnight of the calendar day.
+func (s *CalendarService) walkWorkDays(ctx context.Context, q *query.Query, day time.Time, count, step int) (time.Time, []models.Event, error) {
+	var holidays []models.Event
+
+	// search limit is for finding a single workday
+	for searched := 0; searched < WorkDaySearchLimit; {
+		windowStart, windowEnd := day, day.AddDate(0, 0, workDayWindow)
+		if step < 0 {
+			windowStart, windowEnd = day.AddDate(0, 0, 1-workDayWindow), day.AddDate(0, 0, 1)
+		}
+
+		closed, err := s.closedDays(ctx, q, windowStart, windowEnd)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+
+		for ; !day.Before(windowStart) && day.Before(windowEnd); day = day.AddDate(0, 0, step) {
+			if events, ok := closed[day]; ok {
+				for _, e := range events {
+					if !containsOccurrence(holidays, e) {
+						holidays = append(holidays, e)
+					}
+				}
+
+				searched++
+
+				continue
+			}
+
+			count--
+			if count > 0 {
+				searched = 0
+
+				continue
+			}
+
+			return day, holidays, nil
+		}
+	}
+
+	return time.Time{}, nil, ErrNoWorkDay
+}
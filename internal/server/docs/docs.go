@@ -15,61 +15,56 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/events": {
+        "/adjust": {
             "get": {
-                "description": "GetEvents",
+                "description": "Adjust the date to a workday of the entities with a business day convention.\nConventions are following (default), modified_following, preceding, modified_preceding and end_of_month.\nMultiple entities or a joint calendar are combined with the mode, union needs a workday in all entities.",
                 "tags": [
-                    "Events"
+                    "Search"
                 ],
-                "summary": "GetEvents",
+                "summary": "Adjust",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
+                        "description": "entity for relation",
+                        "name": "entity",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "name",
-                        "name": "name",
+                        "description": "country for relation",
+                        "name": "event_group",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "description",
-                        "name": "description",
+                        "description": "saved joint calendar name",
+                        "name": "joint",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "event_group",
-                        "name": "event_group",
+                        "description": "union (default) or intersection",
+                        "name": "mode",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "entity for relation",
-                        "name": "entity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "disabled",
-                        "name": "disabled",
-                        "in": "query"
+                        "description": "date to adjust",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 25,
-                        "description": "limit",
-                        "name": "limit",
+                        "type": "string",
+                        "default": "following",
+                        "description": "business day convention",
+                        "name": "convention",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "offset",
-                        "name": "offset",
+                        "type": "string",
+                        "description": "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z",
+                        "name": "as_of",
                         "in": "query"
                     }
                 ],
@@ -77,7 +72,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Event"
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_Adjustment"
                         }
                     },
                     "400": {
@@ -93,23 +88,25 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/batch": {
             "post": {
-                "description": "AddEvents",
+                "description": "Batch runs the event and relation operations in order in one transaction, a failing operation rolls back all of them.\nop is one of add_event, update_event, remove_event, add_relation, update_relation or remove_relation.\nEvent operations use id, version and event, relation operations use relation and to for the update.\nupdate_event and remove_event need the version of the event, without it 428 is returned.\nThe results are in the order of the operations, after a failure they are rolled_back, failed or skipped.",
                 "tags": [
-                    "Events"
+                    "Batch"
                 ],
-                "summary": "AddEvents",
+                "summary": "Batch",
                 "parameters": [
                     {
-                        "description": "Event",
+                        "description": "Operations",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Event"
+                                "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.BatchOperation"
                             }
                         }
                     }
@@ -118,7 +115,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Response-array_string"
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_BatchResult"
                         }
                     },
                     "400": {
@@ -127,6 +124,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ResponseMessage"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_BatchResult"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_BatchResult"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -134,27 +149,65 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "DeleteEvents for multiple events",
+            }
+        },
+        "/bridges": {
+            "get": {
+                "description": "Bridge days and long weekends starting in the year with the holidays and weekends of the entities.\nA bridge is a run of up to days workdays between closed days next to a holiday.\nA long weekend is a closed run of more than two days with a weekend day and a holiday.",
                 "tags": [
-                    "Events"
+                    "Search"
                 ],
-                "summary": "DeleteEvents",
+                "summary": "Bridges",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country for relation",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "saved joint calendar name",
+                        "name": "joint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "union (default) or intersection",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "year to check, default is current year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "maximum workdays of a bridge",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z",
+                        "name": "as_of",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.ResponseMessage"
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_Bridges"
                         }
                     },
                     "400": {
@@ -172,27 +225,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/{id}": {
+        "/changesets": {
             "get": {
-                "description": "GetEvent",
+                "description": "GetChangeSets returns the change-sets, the latest created is first.",
                 "tags": [
-                    "Events"
+                    "ChangeSets"
                 ],
-                "summary": "GetEvent",
+                "summary": "GetChangeSets",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "id",
                         "name": "id",
-                        "in": "path",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, approved, applied or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_by",
+                        "name": "created_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "approved_by",
+                        "name": "approved_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "effective at or after the time",
+                        "name": "effective_at[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "effective before the time",
+                        "name": "effective_at[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_Event"
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_ChangeSet"
                         }
                     },
                     "400": {
@@ -201,6 +302,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ResponseMessage"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -209,27 +316,20 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "PutEvent",
+            "post": {
+                "description": "AddChangeSet stores the event and relation changes as a draft, the calendar is not changed until another user approves it.\nNew events without an ID get one in the draft, relations of the same change-set could use it.\nWithout effective_at the changes are applied on the approval, otherwise at that time.",
                 "tags": [
-                    "Events"
+                    "ChangeSets"
                 ],
-                "summary": "PutEvent",
+                "summary": "AddChangeSet",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Event",
+                        "description": "ChangeSet, only name, description, changes and effective_at are used",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Event"
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.ChangeSet"
                         }
                     }
                 ],
@@ -237,7 +337,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.ResponseMessage"
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_ChangeSet"
                         }
                     },
                     "400": {
@@ -253,17 +353,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "DeleteEvent",
+            }
+        },
+        "/changesets/{id}": {
+            "get": {
+                "description": "GetChangeSet",
                 "tags": [
-                    "Events"
+                    "ChangeSets"
                 ],
-                "summary": "DeleteEvent",
+                "summary": "GetChangeSet",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "ChangeSet ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -273,7 +375,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.ResponseMessage"
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_ChangeSet"
                         }
                     },
                     "400": {
@@ -282,6 +384,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ResponseMessage"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -289,41 +397,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/holidays": {
-            "get": {
-                "description": "Holidays for specific date",
+            },
+            "put": {
+                "description": "PutChangeSet replaces the name, description, changes and effective_at of a draft.",
                 "tags": [
-                    "Search"
+                    "ChangeSets"
                 ],
-                "summary": "Holidays",
+                "summary": "PutChangeSet",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "entity for relation",
-                        "name": "entity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "country for relation",
-                        "name": "event_group",
-                        "in": "query"
+                        "description": "ChangeSet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "date specific event",
-                        "name": "date",
-                        "in": "query",
-                        "required": true
+                        "description": "ChangeSet",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.ChangeSet"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Event"
+                            "$ref": "#/definitions/rest.ResponseMessage"
                         }
                     },
                     "400": {
@@ -332,6 +435,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ResponseMessage"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -339,33 +454,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/ics": {
-            "get": {
-                "description": "GetICS",
+            },
+            "delete": {
+                "description": "DeleteChangeSet removes a change-set not applied yet, like a draft or an approved one waiting for its effective_at.",
                 "tags": [
-                    "iCal"
+                    "ChangeSets"
                 ],
-                "summary": "GetICS",
+                "summary": "DeleteChangeSet",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "entity for relation",
-                        "name": "entity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "country",
-                        "name": "event_group",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "specific year events",
-                        "name": "year",
-                        "in": "query"
+                        "description": "ChangeSet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -381,6 +483,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ResponseMessage"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -388,42 +502,116 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "AddICS",
-                "consumes": [
-                    "multipart/form-data"
+            }
+        },
+        "/changesets/{id}/approve": {
+            "post": {
+                "description": "ApproveChangeSet approves a draft of another user.\nWithout effective_at or with a passed one the changes are applied at once in one transaction, otherwise at the effective_at.\nChanges conflicting with the calendar, like an updated event changed after the draft, fail the approval and keep the draft.",
+                "tags": [
+                    "ChangeSets"
+                ],
+                "summary": "ApproveChangeSet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ChangeSet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_ChangeSet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/changesets/{id}/holidays": {
+            "get": {
+                "description": "ChangeSetHolidays previews the holidays with the changes of the change-set, like /holidays after the approval.\nThe calendar is not changed, an applied change-set shows the current holidays.",
                 "tags": [
-                    "iCal"
+                    "ChangeSets"
                 ],
-                "summary": "AddICS",
+                "summary": "ChangeSetHolidays",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "ICS file",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "ChangeSet ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "event_group for ics",
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country for relation",
                         "name": "event_group",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "timezone like Europe/Amsterdam default UTC",
-                        "name": "tz",
+                        "description": "saved joint calendar name",
+                        "name": "joint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "union (default) or intersection",
+                        "name": "mode",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date specific event",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.ResponseMessage"
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Event"
                         }
                     },
                     "400": {
@@ -432,6 +620,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ResponseMessage"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -441,59 +641,94 @@ const docTemplate = `{
                 }
             }
         },
-        "/relations": {
+        "/coverage": {
             "get": {
-                "description": "GetRelations",
+                "description": "Entities and event groups with missing or low occurrences in the upcoming years.\nA year is low when it has fewer occurrences than the ratio of the current year, all lists the ok ones too.",
                 "tags": [
-                    "Relations"
+                    "Search"
                 ],
-                "summary": "GetRelations",
+                "summary": "Coverage",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "entity",
-                        "name": "entity",
+                        "type": "integer",
+                        "default": 2,
+                        "description": "number of upcoming years",
+                        "name": "years",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "event_id",
-                        "name": "event_id",
+                        "type": "number",
+                        "default": 0.5,
+                        "description": "ratio of the current year occurrences",
+                        "name": "ratio",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "event_group",
-                        "name": "event_group",
+                        "type": "boolean",
+                        "description": "list all entities and event groups",
+                        "name": "all",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Coverage"
+                        }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/definitions": {
+            "get": {
+                "description": "Enabled events of the entities with the resolved exclusions, to calculate the occurrences offline.\nETag is the hash of the result, If-None-Match with the same value returns 304.",
+                "tags": [
+                    "Search"
+                ],
+                "summary": "GetDefinitions",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "sort",
-                        "name": "sort",
+                        "description": "entity for relation",
+                        "name": "entity",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 25,
-                        "description": "limit",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "country for relation",
+                        "name": "event_group",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
+                        "type": "string",
+                        "description": "ETag of the previous result",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Relation"
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Event"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -507,32 +742,47 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "AddRelations",
+            }
+        },
+        "/diff": {
+            "get": {
+                "description": "Added, removed and moved holidays of the entity and year compared with the base entity and year.\nHolidays are matched by event ID and then by name, base dates are shifted to the year before comparing.",
                 "tags": [
-                    "Relations"
+                    "Search"
                 ],
-                "summary": "AddRelations",
+                "summary": "Diff",
                 "parameters": [
                     {
-                        "description": "Relation",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Relation"
-                            }
-                        }
+                        "type": "string",
+                        "description": "entity to compare",
+                        "name": "entity",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "year to compare, default is current year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "base entity, default is entity",
+                        "name": "base_entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "base year, default is the previous year for the same entity and year otherwise",
+                        "name": "base_year",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.ResponseMessage"
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_Diff"
                         }
                     },
                     "400": {
@@ -548,25 +798,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "DeleteRelations for multiple relations",
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "GetEvents",
                 "tags": [
-                    "Relations"
+                    "Events"
                 ],
-                "summary": "DeleteRelations",
+                "summary": "GetEvents",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "entity",
-                        "name": "entity",
-                        "in": "query",
-                        "required": true
+                        "description": "id",
+                        "name": "id",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "event_id",
-                        "name": "event_id",
+                        "description": "name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "description",
+                        "name": "description",
                         "in": "query"
                     },
                     {
@@ -574,13 +831,44 @@ const docTemplate = `{
                         "description": "event_group",
                         "name": "event_group",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "disabled",
+                        "name": "disabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "type, holiday or half-day",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.ResponseMessage"
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Event"
                         }
                     },
                     "400": {
@@ -596,22 +884,2551 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "github_com_worldline-go_calendar_pkg_models.Event": {
-            "type": "object",
-            "properties": {
-                "all_day": {
-                    "type": "boolean"
-                },
+            },
+            "post": {
+                "description": "AddEvents",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "AddEvents",
+                "parameters": [
+                    {
+                        "description": "Event",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Event"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteEvents for multiple events, every id has the version of the event like id=\u003cid\u003e:\u003cversion\u003e.\nThe events move to the trash with their relations in one transaction, nothing is removed when one of them has another version.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "DeleteEvents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID with its version like 01JQ...:3, comma separated for multiple events",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "description": "GetEvent, ETag is the version of the event to update or delete it with If-Match.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "GetEvent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "PutEvent, If-Match is the ETag of the event or \"*\" for any version.\nETag of the response is the new version of the event.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "PutEvent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the event",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Event",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteEvent, If-Match is the ETag of the event or \"*\" for any version.\nThe event moves to the trash with its relations, it is restored with POST /events/{id}/restore.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "DeleteEvent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the event",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "PatchEvent with a JSON merge patch (RFC 7396), only the fields in the patch are changed and null clears a field.\nIf-Match is the ETag of the event or \"*\" for any version, ETag of the response is the new version of the event.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "PatchEvent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the event",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields of the event to change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/history": {
+            "get": {
+                "description": "GetEventHistory returns the recorded changes of the event, the latest change is first.\nHistory of a removed event is kept.",
+                "tags": [
+                    "History"
+                ],
+                "summary": "GetEventHistory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "insert, update, delete or restore",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "changed_by",
+                        "name": "changed_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "changed at or after the time",
+                        "name": "changed_at[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "changed before the time",
+                        "name": "changed_at[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_EventHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/restore": {
+            "post": {
+                "description": "RestoreEvent takes the event back from the trash with the relations removed together with it.",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "RestoreEvent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/history": {
+            "get": {
+                "description": "GetHistory returns the recorded changes of all events for auditing, the latest change is first.",
+                "tags": [
+                    "History"
+                ],
+                "summary": "GetHistory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "event_id",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "insert, update, delete or restore",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "changed_by",
+                        "name": "changed_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "changed at or after the time",
+                        "name": "changed_at[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "changed before the time",
+                        "name": "changed_at[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_EventHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/holidays": {
+            "get": {
+                "description": "Holidays for specific date, events starting on a weekend day of the entity have the weekend flag.\nMultiple entities or a joint calendar are combined with the mode, intersection returns holidays only when all entities have one.\nETag and Last-Modified come from the last change of the events, relations, weekends and the joint calendar, If-None-Match or If-Modified-Since returns 304.",
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Holidays",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country for relation",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "saved joint calendar name",
+                        "name": "joint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "union (default) or intersection",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date specific event",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the previous result",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the previous result",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Event"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/hours": {
+            "get": {
+                "description": "GetHours",
+                "tags": [
+                    "Hours"
+                ],
+                "summary": "GetHours",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "tz",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_BusinessHours"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/hours/{entity}": {
+            "get": {
+                "description": "GetEntityHours",
+                "tags": [
+                    "Hours"
+                ],
+                "summary": "GetEntityHours",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_BusinessHours"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "PutEntityHours sets the weekly business hours of the entity.\nHours are lowercase weekday names to open windows like {\"monday\": [{\"from\": \"09:00\", \"to\": \"17:00\"}]}, a weekday without windows is closed.",
+                "tags": [
+                    "Hours"
+                ],
+                "summary": "PutEntityHours",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "BusinessHours",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.BusinessHours"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteEntityHours, the entity falls back to the default hours.",
+                "tags": [
+                    "Hours"
+                ],
+                "summary": "DeleteEntityHours",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/ics": {
+            "get": {
+                "description": "GetICS\nETag and Last-Modified come from the last change of the events, relations, weekends and the joint calendar, If-None-Match or If-Modified-Since returns 304.",
+                "tags": [
+                    "iCal"
+                ],
+                "summary": "GetICS",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "saved joint calendar name, events of all entities are listed",
+                        "name": "joint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "specific year events",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the previous result",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the previous result",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "AddICS",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "iCal"
+                ],
+                "summary": "AddICS",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "ICS file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_group for ics",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "timezone like Europe/Amsterdam default UTC",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/is-open-at": {
+            "get": {
+                "description": "Open state at the time with the business hours, holidays and half-day events of the entities.\nEntities without business hours are open all day in UTC, weekend days of the entities are closed.\nMultiple entities or a joint calendar are combined with the mode, union needs all entities open.",
+                "tags": [
+                    "Search"
+                ],
+                "summary": "IsOpenAt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country for relation",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "saved joint calendar name",
+                        "name": "joint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "union (default) or intersection",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "time to check like 2025-12-24T14:00:00Z",
+                        "name": "time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_OpenAt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/joints": {
+            "get": {
+                "description": "GetJoints",
+                "tags": [
+                    "Joints"
+                ],
+                "summary": "GetJoints",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Joint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "AddJoints, a joint calendar combines the entities with the mode union (default) or intersection.\nUnion closes a day when any of the entities has a holiday, intersection only when all of them have.",
+                "tags": [
+                    "Joints"
+                ],
+                "summary": "AddJoints",
+                "parameters": [
+                    {
+                        "description": "Joint",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Joint"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/joints/{name}": {
+            "get": {
+                "description": "GetJoint",
+                "tags": [
+                    "Joints"
+                ],
+                "summary": "GetJoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Joint name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_Joint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "PutJoint",
+                "tags": [
+                    "Joints"
+                ],
+                "summary": "PutJoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Joint name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Joint",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Joint"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteJoint",
+                "tags": [
+                    "Joints"
+                ],
+                "summary": "DeleteJoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Joint name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/relations": {
+            "get": {
+                "description": "GetRelations",
+                "tags": [
+                    "Relations"
+                ],
+                "summary": "GetRelations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_id",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_group",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "parent",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "occurrence",
+                        "name": "occurrence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Relation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "AddRelations, type is one of include (default), parent or exclude.\nParent relations make the entity inherit the calendar of the parent entity.\nExclude relations remove an event_id or event_group from the entity and its inherited calendars.\nExclude relations with an occurrence day remove only that occurrence of the event_id.",
+                "tags": [
+                    "Relations"
+                ],
+                "summary": "AddRelations",
+                "parameters": [
+                    {
+                        "description": "Relation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Relation"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteRelations for multiple relations, they move to the trash and are restored with POST /relations/restore.",
+                "tags": [
+                    "Relations"
+                ],
+                "summary": "DeleteRelations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity",
+                        "name": "entity",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_id",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_group",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "parent",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "occurrence",
+                        "name": "occurrence",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "PatchRelations changes the matching relations in place with a JSON merge patch (RFC 7396).\nThe patch is applied to every matching relation and checked like a new relation, all of them are changed or none.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "tags": [
+                    "Relations"
+                ],
+                "summary": "PatchRelations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity",
+                        "name": "entity",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_id",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_group",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "parent",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "occurrence",
+                        "name": "occurrence",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields of the relations to change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Relation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Relation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/relations/restore": {
+            "post": {
+                "description": "RestoreRelations takes the matching relations back from the trash.\nRelations of an event in the trash are skipped, they are restored with the event.",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "RestoreRelations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity",
+                        "name": "entity",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_id",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_group",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "parent",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "occurrence",
+                        "name": "occurrence",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule": {
+            "get": {
+                "description": "Business days of a recurring schedule between from and to, resolved with the holidays and weekends of the entities.\nRule is like FREQ=MONTHLY;BYBUSINESSDAY=-1 with FREQ of WEEKLY, MONTHLY or YEARLY and optional INTERVAL, BYMONTH and COUNT.\nBYBUSINESSDAY ordinals count the business days in every period, negative ones from the end.",
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country for relation",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "saved joint calendar name",
+                        "name": "joint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "union (default) or intersection",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "schedule rule, URL encoded",
+                        "name": "rule",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, inclusive",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, exclusive",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/settlement": {
+            "get": {
+                "description": "Settlement date of a trade after T+N workdays of the entities, skipped holidays are returned.\nTrades at or after the cut-off, or not on a workday, are booked on the next workday.\nMultiple entities or a joint calendar are combined with the mode, union needs a workday in all entities.",
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Settlement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country for relation",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "saved joint calendar name",
+                        "name": "joint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "union (default) or intersection",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "trade timestamp like 2025-04-17T16:30:00Z",
+                        "name": "trade",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "number of workdays after the trade date",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cut-off time like 16:00",
+                        "name": "cutoff",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "timezone of the cut-off, default is the business hours timezone of the first entity",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_Settlement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/events": {
+            "get": {
+                "description": "GetTrashEvents returns the removed events, the latest removed is first.\nEvents stay in the trash until the purge after the retention.",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "GetTrashEvents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_group",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "deleted_by",
+                        "name": "deleted_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "removed at or after the time",
+                        "name": "deleted_at[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "removed before the time",
+                        "name": "deleted_at[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/relations": {
+            "get": {
+                "description": "GetTrashRelations returns the removed relations, the latest removed is first.\nRelations removed with their event have the deleted_at of the event.",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "GetTrashRelations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_id",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_group",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "deleted_by",
+                        "name": "deleted_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "removed at or after the time",
+                        "name": "deleted_at[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "removed before the time",
+                        "name": "deleted_at[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Relation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/weekends": {
+            "get": {
+                "description": "GetWeekends",
+                "tags": [
+                    "Weekends"
+                ],
+                "summary": "GetWeekends",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "effective_from",
+                        "name": "effective_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 25,
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-array_github_com_worldline-go_calendar_pkg_models_Weekend"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "AddWeekends, the weekend days of the entity starting from effective_from until the next pattern.\nDays before the first pattern use saturday and sunday, an existing effective_from of the entity is replaced.",
+                "tags": [
+                    "Weekends"
+                ],
+                "summary": "AddWeekends",
+                "parameters": [
+                    {
+                        "description": "Weekend",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Weekend"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteWeekends for multiple weekend patterns",
+                "tags": [
+                    "Weekends"
+                ],
+                "summary": "DeleteWeekends",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity",
+                        "name": "entity",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "effective_from",
+                        "name": "effective_from",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/workday": {
+            "get": {
+                "description": "Next workday after the date, weekends and holidays of the entities are skipped\nMultiple entities or a joint calendar are combined with the mode, union needs a workday in all entities.",
+                "tags": [
+                    "Search"
+                ],
+                "summary": "WorkDay",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "entity for relation",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country for relation",
+                        "name": "event_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "saved joint calendar name",
+                        "name": "joint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "union (default) or intersection",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date to start from",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "number of workdays to move",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "resolve the calendar as it was at the time, like 2025-03-01T12:00:00Z",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Response-github_com_worldline-go_calendar_pkg_models_WorkDay"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_worldline-go_calendar_internal_core_domain.DiffSide": {
+            "type": "object",
+            "properties": {
+                "entity": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_internal_core_domain.Event": {
+            "type": "object",
+            "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is set when the event is in the trash, it is only changed by removing and restoring.",
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "event_group": {
+                    "$ref": "#/definitions/types.Null-string"
+                },
+                "exdates": {
+                    "description": "ExDates are the excluded occurrence days of the event, resolved from the exclude relations.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "tz": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is increased on every update, updates and removals of the event match it.",
+                    "type": "integer"
+                },
+                "weekend": {
+                    "description": "Weekend is set when the occurrence starts on a weekend day of the entity.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_internal_core_domain.Move": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                },
+                "to": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_internal_core_domain.Relation": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "description": "DeletedAt is set when the relation is in the trash, relations removed with their event have its time.",
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "event_group": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "occurrence": {
+                    "type": "string"
+                },
+                "parent": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_internal_core_domain.Span": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.Adjustment": {
+            "type": "object",
+            "properties": {
+                "adjusted": {
+                    "type": "string"
+                },
+                "convention": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "holidays": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                    }
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.BatchOperation": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "description": "Event is the new event of add_event and update_event.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "ID is the event of update_event and remove_event.",
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "relation": {
+                    "description": "Relation is added, removed or replaced with To, relations are matched with all of their fields.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Relation"
+                        }
+                    ]
+                },
+                "to": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Relation"
+                },
+                "version": {
+                    "description": "Version is the expected version of the event to update or remove, it is required for them.",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the event of the event operations, added events without an ID get it here.",
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the new version of an added or updated event.",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.Bridges": {
+            "type": "object",
+            "properties": {
+                "bridges": {
+                    "description": "Bridges are workdays between a holiday and another closed day.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Span"
+                    }
+                },
+                "long_weekends": {
+                    "description": "LongWeekends are closed runs longer than a weekend with a holiday in it.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Span"
+                    }
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.BusinessHours": {
+            "type": "object",
+            "properties": {
+                "entity": {
+                    "type": "string"
+                },
+                "hours": {
+                    "description": "Hours are the open windows with the lowercase weekday names, a weekday without windows is closed.",
+                    "type": "object"
+                },
+                "tz": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.ChangeSet": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "approved_at": {
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_at": {
+                    "description": "EffectiveAt applies the approved change-set at that time, null applies it on the approval.",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error is the reason of a failed change-set.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.Coverage": {
+            "type": "object",
+            "properties": {
+                "baseline": {
+                    "description": "Baseline is the number of occurrences in the current year.",
+                    "type": "integer"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "event_group": {
+                    "type": "string"
+                },
+                "occurrences": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.Diff": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                    }
+                },
+                "base": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.DiffSide"
+                },
+                "moved": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Move"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                    }
+                },
+                "target": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.DiffSide"
+                },
+                "unchanged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.Event": {
+            "type": "object",
+            "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
                 "date_from": {
                     "type": "string"
                 },
                 "date_to": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "description": "DeletedAt is set when the event is in the trash, it is only changed by removing and restoring.",
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -619,18 +3436,154 @@ const docTemplate = `{
                     "type": "boolean"
                 },
                 "event_group": {
-                    "$ref": "#/definitions/types.Null-string"
+                    "$ref": "#/definitions/types.Null-string"
+                },
+                "exdates": {
+                    "description": "ExDates are the excluded occurrence days of the event, resolved from the exclude relations.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "tz": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is increased on every update, updates and removals of the event match it.",
+                    "type": "integer"
+                },
+                "weekend": {
+                    "description": "Weekend is set when the occurrence starts on a weekend day of the entity.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.EventHistory": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "description": "Before is null for inserts and After is null for deletes.",
+                    "type": "object"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version is the version after the change, the removed version for deletes.",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.Joint": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "entities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.OpenAt": {
+            "type": "object",
+            "properties": {
+                "closed": {
+                    "description": "Closed are the entities closed at the time.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "events": {
+                    "description": "Events are the holidays and the half-day events closing the entities at the time.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                    }
+                },
+                "open": {
+                    "type": "boolean"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.Relation": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "description": "DeletedAt is set when the relation is in the trash, relations removed with their event have its time.",
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "event_group": {
+                    "type": "string"
                 },
-                "id": {
+                "event_id": {
                     "type": "string"
                 },
-                "name": {
+                "occurrence": {
                     "type": "string"
                 },
-                "rrule": {
+                "parent": {
                     "type": "string"
                 },
-                "tz": {
+                "type": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -641,16 +3594,44 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_worldline-go_calendar_pkg_models.Relation": {
+        "github_com_worldline-go_calendar_pkg_models.Settlement": {
             "type": "object",
             "properties": {
-                "entity": {
+                "after_cut_off": {
+                    "type": "boolean"
+                },
+                "date": {
                     "type": "string"
                 },
-                "event_group": {
+                "holidays": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                    }
+                },
+                "trade": {
                     "type": "string"
                 },
-                "event_id": {
+                "trade_date": {
+                    "description": "TradeDate is the workday the trade is booked on.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_worldline-go_calendar_pkg_models.Weekend": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Days are the lowercase weekday names of the weekend.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "entity": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -661,6 +3642,20 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_worldline-go_calendar_pkg_models.WorkDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "holidays": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_internal_core_domain.Event"
+                    }
+                }
+            }
+        },
         "rest.Message": {
             "type": "object",
             "properties": {
@@ -693,6 +3688,74 @@ const docTemplate = `{
                 }
             }
         },
+        "rest.Response-array_github_com_worldline-go_calendar_pkg_models_BatchResult": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.BatchResult"
+                    }
+                }
+            }
+        },
+        "rest.Response-array_github_com_worldline-go_calendar_pkg_models_BusinessHours": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.BusinessHours"
+                    }
+                }
+            }
+        },
+        "rest.Response-array_github_com_worldline-go_calendar_pkg_models_ChangeSet": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.ChangeSet"
+                    }
+                }
+            }
+        },
+        "rest.Response-array_github_com_worldline-go_calendar_pkg_models_Coverage": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Coverage"
+                    }
+                }
+            }
+        },
         "rest.Response-array_github_com_worldline-go_calendar_pkg_models_Event": {
             "type": "object",
             "properties": {
@@ -710,6 +3773,40 @@ const docTemplate = `{
                 }
             }
         },
+        "rest.Response-array_github_com_worldline-go_calendar_pkg_models_EventHistory": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.EventHistory"
+                    }
+                }
+            }
+        },
+        "rest.Response-array_github_com_worldline-go_calendar_pkg_models_Joint": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Joint"
+                    }
+                }
+            }
+        },
         "rest.Response-array_github_com_worldline-go_calendar_pkg_models_Relation": {
             "type": "object",
             "properties": {
@@ -727,6 +3824,23 @@ const docTemplate = `{
                 }
             }
         },
+        "rest.Response-array_github_com_worldline-go_calendar_pkg_models_Weekend": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Weekend"
+                    }
+                }
+            }
+        },
         "rest.Response-array_string": {
             "type": "object",
             "properties": {
@@ -744,6 +3858,76 @@ const docTemplate = `{
                 }
             }
         },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_Adjustment": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Adjustment"
+                }
+            }
+        },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_Bridges": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Bridges"
+                }
+            }
+        },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_BusinessHours": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.BusinessHours"
+                }
+            }
+        },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_ChangeSet": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.ChangeSet"
+                }
+            }
+        },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_Diff": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Diff"
+                }
+            }
+        },
         "rest.Response-github_com_worldline-go_calendar_pkg_models_Event": {
             "type": "object",
             "properties": {
@@ -758,6 +3942,62 @@ const docTemplate = `{
                 }
             }
         },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_Joint": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Joint"
+                }
+            }
+        },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_OpenAt": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.OpenAt"
+                }
+            }
+        },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_Settlement": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.Settlement"
+                }
+            }
+        },
+        "rest.Response-github_com_worldline-go_calendar_pkg_models_WorkDay": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.Message"
+                },
+                "meta": {
+                    "$ref": "#/definitions/rest.Meta"
+                },
+                "payload": {
+                    "$ref": "#/definitions/github_com_worldline-go_calendar_pkg_models.WorkDay"
+                }
+            }
+        },
         "rest.ResponseMessage": {
             "type": "object",
             "properties": {
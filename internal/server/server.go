@@ -37,10 +37,13 @@ func NewServer(ctx context.Context, svc port.CalendarService) (*server.Server, e
 		// ////////////////////////////
 
 		handleHTTP.RegisterRoutes(v1Group)
+		handleHTTP.RegisterCalDAV(sGroup.Group("/dav"))
 
 		// ////////////////////////////
 		// add handler to mux
 		mux.HandleFunc("/calendar/", e.ServeHTTP)
+		// calendar clients discover the CalDAV root with the well-known path
+		mux.Handle("/.well-known/caldav", http.RedirectHandler("/calendar/dav/", http.StatusMovedPermanently))
 
 		return nil
 	})
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/worldline-go/klient"
+	"github.com/worldline-go/rest"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Batch runs the operations in one transaction and returns the result of each operation in the same order.
+// A failed operation rolls back all of them, the results are returned with the ResponseError to find the failed one.
+func (c *Calendar) Batch(ctx context.Context, operations []models.BatchOperation) ([]models.BatchResult, error) {
+	req, err := request(ctx, http.MethodPost, "/batch", nil, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp rest.Response[[]models.BatchResult]
+	if err := c.klient.Do(req, func(r *http.Response) error {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+
+		// the results of a rolled back batch come with the error status
+		decodeErr := json.Unmarshal(body, &resp)
+
+		if r.StatusCode < 200 || r.StatusCode >= 300 {
+			return &klient.ResponseError{
+				StatusCode: r.StatusCode,
+				Body:       string(body),
+				RequestID:  r.Header.Get("X-Request-Id"),
+			}
+		}
+
+		return decodeErr
+	}); err != nil {
+		return resp.Payload, err
+	}
+
+	return resp.Payload, nil
+}
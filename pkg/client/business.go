@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/worldline-go/rest"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Business day queries use the filter for entity, event_group, joint and mode.
+
+// Holidays returns the holidays on the date, no holiday is an empty list.
+func (c *Calendar) Holidays(ctx context.Context, date time.Time, filter url.Values) ([]models.Event, error) {
+	var resp rest.Response[[]models.Event]
+	if err := c.do(ctx, http.MethodGet, "/holidays", with(filter, "date", date.Format(time.DateOnly)), nil, &resp); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
+
+// WorkDay returns the workday days workdays after the date.
+func (c *Calendar) WorkDay(ctx context.Context, date time.Time, days int, filter url.Values) (*models.WorkDay, error) {
+	var resp rest.Response[models.WorkDay]
+	values := with(filter, "date", date.Format(time.DateOnly), "days", strconv.Itoa(days))
+	if err := c.do(ctx, http.MethodGet, "/workday", values, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// IsOpenAt returns the open state at the time with the business hours.
+func (c *Calendar) IsOpenAt(ctx context.Context, t time.Time, filter url.Values) (*models.OpenAt, error) {
+	var resp rest.Response[models.OpenAt]
+	if err := c.do(ctx, http.MethodGet, "/is-open-at", with(filter, "time", t.Format(time.RFC3339)), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// Settlement returns the settlement date of the trade.
+func (c *Calendar) Settlement(ctx context.Context, req models.SettlementRequest, filter url.Values) (*models.Settlement, error) {
+	var resp rest.Response[models.Settlement]
+	values := with(filter,
+		"trade", req.Trade.Format(time.RFC3339),
+		"days", strconv.Itoa(req.Days),
+		"cutoff", req.CutOff,
+		"tz", req.Tz,
+	)
+	if err := c.do(ctx, http.MethodGet, "/settlement", values, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// Adjust moves the date to a workday with the convention, empty convention is following.
+func (c *Calendar) Adjust(ctx context.Context, date time.Time, convention string, filter url.Values) (*models.Adjustment, error) {
+	var resp rest.Response[models.Adjustment]
+	values := with(filter, "date", date.Format(time.DateOnly), "convention", convention)
+	if err := c.do(ctx, http.MethodGet, "/adjust", values, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// Schedule returns the business days of the rule like "FREQ=MONTHLY;BYBUSINESSDAY=-1" in [from, to).
+func (c *Calendar) Schedule(ctx context.Context, rule string, from, to time.Time, filter url.Values) ([]types.Time, error) {
+	var resp rest.Response[[]types.Time]
+	values := with(filter, "rule", rule, "from", from.Format(time.DateOnly), "to", to.Format(time.DateOnly))
+	if err := c.do(ctx, http.MethodGet, "/schedule", values, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
+
+// Bridges returns the bridge days of at most days workdays and the long weekends of the year.
+func (c *Calendar) Bridges(ctx context.Context, year, days int, filter url.Values) (*models.Bridges, error) {
+	var resp rest.Response[models.Bridges]
+	values := with(filter, "year", strconv.Itoa(year), "days", strconv.Itoa(days))
+	if err := c.do(ctx, http.MethodGet, "/bridges", values, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// Diff compares the holidays of the target with the base entity and year.
+func (c *Calendar) Diff(ctx context.Context, base, target models.DiffSide) (*models.Diff, error) {
+	var resp rest.Response[models.Diff]
+	values := with(nil,
+		"entity", target.Entity,
+		"year", strconv.Itoa(target.Year),
+		"base_entity", base.Entity,
+		"base_year", strconv.Itoa(base.Year),
+	)
+	if err := c.do(ctx, http.MethodGet, "/diff", values, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// Coverage returns the entities and event groups with missing or low occurrences in the upcoming years, all lists the ok ones too.
+func (c *Calendar) Coverage(ctx context.Context, years int, ratio float64, all bool) ([]models.Coverage, error) {
+	var resp rest.Response[[]models.Coverage]
+	values := with(nil,
+		"years", strconv.Itoa(years),
+		"ratio", strconv.FormatFloat(ratio, 'f', -1, 64),
+		"all", strconv.FormatBool(all),
+	)
+	if err := c.do(ctx, http.MethodGet, "/coverage", values, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/worldline-go/rest"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// ///////////////////////////////////////////////////////////////
+// Change-sets
+// ///////////////////////////////////////////////////////////////
+
+// GetChangeSets returns the change-sets matching the filter like status and created_by, the latest created is first.
+// No matching change-set is an empty response.
+func (c *Calendar) GetChangeSets(ctx context.Context, filter url.Values) (*rest.Response[[]models.ChangeSet], error) {
+	var resp rest.Response[[]models.ChangeSet]
+	if err := c.do(ctx, http.MethodGet, "/changesets", filter, nil, &resp); err != nil {
+		if IsNotFound(err) {
+			return &rest.Response[[]models.ChangeSet]{}, nil
+		}
+
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AddChangeSet stores the change-set as a draft, the stored draft with the IDs of the new events is returned.
+func (c *Calendar) AddChangeSet(ctx context.Context, changeSet models.ChangeSet) (*models.ChangeSet, error) {
+	var resp rest.Response[models.ChangeSet]
+	if err := c.do(ctx, http.MethodPost, "/changesets", nil, changeSet, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// GetChangeSet returns the change-set with the ID.
+func (c *Calendar) GetChangeSet(ctx context.Context, id string) (*models.ChangeSet, error) {
+	var resp rest.Response[models.ChangeSet]
+	if err := c.do(ctx, http.MethodGet, "/changesets/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// UpdateChangeSet replaces the draft with the ID, a change-set not in draft returns a 409 ResponseError.
+func (c *Calendar) UpdateChangeSet(ctx context.Context, id string, changeSet models.ChangeSet) error {
+	return c.do(ctx, http.MethodPut, "/changesets/"+url.PathEscape(id), nil, changeSet, nil)
+}
+
+// DeleteChangeSet removes the change-set with the ID when it is not applied yet.
+func (c *Calendar) DeleteChangeSet(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/changesets/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ApproveChangeSet approves the draft of another user, the status of the returned change-set is applied or approved for a later effective_at.
+func (c *Calendar) ApproveChangeSet(ctx context.Context, id string) (*models.ChangeSet, error) {
+	var resp rest.Response[models.ChangeSet]
+	if err := c.do(ctx, http.MethodPost, "/changesets/"+url.PathEscape(id)+"/approve", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// ChangeSetHolidays returns the holidays on the date like Holidays with the changes of the change-set, no holiday is an empty list.
+func (c *Calendar) ChangeSetHolidays(ctx context.Context, id string, date time.Time, filter url.Values) ([]models.Event, error) {
+	var resp rest.Response[[]models.Event]
+	path := "/changesets/" + url.PathEscape(id) + "/holidays"
+	if err := c.do(ctx, http.MethodGet, path, with(filter, "date", date.Format(time.DateOnly)), nil, &resp); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
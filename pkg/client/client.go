@@ -1,11 +1,25 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/worldline-go/klient"
 )
 
+// BasePath is the path of the calendar API on the base URL.
+var BasePath = "/calendar/v1"
+
+// MIMEMergePatch is the content type of the patch requests.
+const MIMEMergePatch = "application/merge-patch+json"
+
 type Calendar struct {
 	klient *klient.Client
 }
@@ -20,3 +34,70 @@ func New(opts ...klient.OptionClientFn) (*Calendar, error) {
 		klient: client,
 	}, nil
 }
+
+// IsNotFound reports whether the error is a not found response of the API.
+func IsNotFound(err error) bool {
+	var respErr *klient.ResponseError
+
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound
+}
+
+// operatorReplacer keeps the operators of the keys like "changed_at[gte]", the query keys are not unescaped.
+var operatorReplacer = strings.NewReplacer("%5B", "[", "%5D", "]")
+
+// request creates a request to the path under the base path, body is sent as JSON when it is not nil.
+func request(ctx context.Context, method, path string, values url.Values, body any) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+
+		bodyReader = bytes.NewReader(data)
+	}
+
+	u := BasePath + path
+	if len(values) > 0 {
+		u += "?" + operatorReplacer.Replace(values.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// do sends the request and decodes the JSON response into data, data can be nil.
+func (c *Calendar) do(ctx context.Context, method, path string, values url.Values, body, data any) error {
+	req, err := request(ctx, method, path, values, body)
+	if err != nil {
+		return err
+	}
+
+	return c.klient.Do(req, klient.ResponseFuncJSON(data))
+}
+
+// with returns a copy of the values with the key set, empty values are skipped.
+func with(values url.Values, kv ...string) url.Values {
+	result := make(url.Values, len(values)+len(kv)/2)
+	for k, v := range values {
+		result[k] = append([]string(nil), v...)
+	}
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] == "" {
+			continue
+		}
+
+		result.Set(kv[i], kv[i+1])
+	}
+
+	return result
+}
@@ -0,0 +1,1089 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/worldline-go/klient"
+	"github.com/worldline-go/query"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/internal/adapter/handler"
+	"github.com/worldline-go/calendar/internal/core/domain"
+	"github.com/worldline-go/calendar/internal/core/port"
+	"github.com/worldline-go/calendar/pkg/client"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// fakeService records the calls of the handler and returns the stored values.
+type fakeService struct {
+	port.CalendarService
+
+	events    []models.Event
+	relations []models.Relation
+	joint     *models.Joint
+	hours     *models.BusinessHours
+	weekends  []models.Weekend
+	history   []models.EventHistory
+	trash     []models.Event
+	changes   []models.ChangeSet
+
+	query     *query.Query
+	removed   []string
+	historyID string
+	updated   *models.Event
+	ics       string
+	group     string
+}
+
+func (f *fakeService) GetEvents(_ context.Context, q *query.Query) ([]models.Event, error) {
+	f.query = q
+
+	return f.events, nil
+}
+
+func (f *fakeService) GetEventsCount(_ context.Context, _ *query.Query) (uint64, error) {
+	return uint64(len(f.events)), nil
+}
+
+func (f *fakeService) AddEvents(_ context.Context, events []models.Event) error {
+	for i := range events {
+		events[i].ID = "id-" + events[i].Name
+		events[i].Version = 1
+	}
+
+	f.events = append(f.events, events...)
+
+	return nil
+}
+
+func (f *fakeService) GetEvent(_ context.Context, id string) (*models.Event, error) {
+	for _, e := range f.events {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (f *fakeService) UpdateEvent(_ context.Context, id string, event *models.Event) error {
+	i, err := f.version(id, event.Version)
+	if err != nil {
+		return err
+	}
+
+	event.ID = id
+	event.Version++
+	f.events[i] = *event
+	f.updated = event
+
+	return nil
+}
+
+func (f *fakeService) RemoveEvents(ctx context.Context, events []models.EventVersion, by string) error {
+	for _, e := range events {
+		if _, err := f.version(e.ID, e.Version); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range events {
+		if err := f.RemoveEventVersion(ctx, e.ID, e.Version, by); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeService) RemoveEventVersion(_ context.Context, id string, version int64, by string) error {
+	i, err := f.version(id, version)
+	if err != nil {
+		return err
+	}
+
+	removed := f.events[i]
+	removed.DeletedAt = types.NewNull(types.Time{Time: time.Now()})
+	removed.DeletedBy = types.NewNull(by)
+
+	f.trash = append(f.trash, removed)
+	f.events = slices.Delete(f.events, i, i+1)
+	f.removed = append(f.removed, id)
+
+	return nil
+}
+
+func (f *fakeService) GetTrashEvents(_ context.Context, q *query.Query) ([]models.Event, error) {
+	f.query = q
+
+	return f.trash, nil
+}
+
+func (f *fakeService) GetTrashEventsCount(_ context.Context, _ *query.Query) (uint64, error) {
+	return uint64(len(f.trash)), nil
+}
+
+func (f *fakeService) GetTrashRelations(_ context.Context, q *query.Query) ([]models.Relation, error) {
+	f.query = q
+
+	return nil, nil
+}
+
+func (f *fakeService) RestoreEvent(_ context.Context, id string, _ string) error {
+	i := slices.IndexFunc(f.trash, func(e models.Event) bool { return e.ID == id })
+	if i < 0 {
+		return domain.ErrEventNotFound
+	}
+
+	restored := f.trash[i]
+	restored.DeletedAt = types.Null[types.Time]{}
+	restored.DeletedBy = types.Null[string]{}
+
+	f.events = append(f.events, restored)
+	f.trash = slices.Delete(f.trash, i, i+1)
+
+	return nil
+}
+
+func (f *fakeService) RestoreRelations(_ context.Context, q *query.Query, _ string) error {
+	f.query = q
+
+	return nil
+}
+
+func (f *fakeService) AddChangeSet(_ context.Context, changeSet *models.ChangeSet) error {
+	changeSet.ID = "cs-" + changeSet.Name
+	changeSet.Status = models.ChangeSetStatusDraft
+	f.changes = append(f.changes, *changeSet)
+
+	return nil
+}
+
+func (f *fakeService) GetChangeSets(_ context.Context, q *query.Query) ([]models.ChangeSet, error) {
+	f.query = q
+
+	return f.changes, nil
+}
+
+func (f *fakeService) GetChangeSetsCount(_ context.Context, _ *query.Query) (uint64, error) {
+	return uint64(len(f.changes)), nil
+}
+
+func (f *fakeService) GetChangeSet(_ context.Context, id string) (*models.ChangeSet, error) {
+	i := slices.IndexFunc(f.changes, func(cs models.ChangeSet) bool { return cs.ID == id })
+	if i < 0 {
+		return nil, nil
+	}
+
+	return &f.changes[i], nil
+}
+
+func (f *fakeService) UpdateChangeSet(_ context.Context, id string, changeSet *models.ChangeSet) error {
+	i := slices.IndexFunc(f.changes, func(cs models.ChangeSet) bool { return cs.ID == id })
+	if i < 0 {
+		return domain.ErrChangeSetNotFound
+	}
+
+	if f.changes[i].Status != models.ChangeSetStatusDraft {
+		return domain.ErrChangeSetStatus
+	}
+
+	changeSet.Status = models.ChangeSetStatusDraft
+	f.changes[i] = *changeSet
+
+	return nil
+}
+
+func (f *fakeService) ApproveChangeSet(_ context.Context, id string, approvedBy string) (*models.ChangeSet, error) {
+	i := slices.IndexFunc(f.changes, func(cs models.ChangeSet) bool { return cs.ID == id })
+	if i < 0 {
+		return nil, domain.ErrChangeSetNotFound
+	}
+
+	if f.changes[i].CreatedBy == approvedBy {
+		return nil, domain.ErrChangeSetApprover
+	}
+
+	f.changes[i].Status = models.ChangeSetStatusApplied
+	f.changes[i].ApprovedBy = types.NewNull(approvedBy)
+	f.events = append(f.events, f.changes[i].Changes.V.AddEvents...)
+
+	return &f.changes[i], nil
+}
+
+func (f *fakeService) PreviewChangeSet(_ context.Context, id string, q *query.Query) ([]models.Event, error) {
+	f.query = q
+
+	i := slices.IndexFunc(f.changes, func(cs models.ChangeSet) bool { return cs.ID == id })
+	if i < 0 {
+		return nil, domain.ErrChangeSetNotFound
+	}
+
+	return append(slices.Clone(f.events), f.changes[i].Changes.V.AddEvents...), nil
+}
+
+// Batch applies the added events, a missing event to remove fails the batch.
+func (f *fakeService) Batch(_ context.Context, operations []models.BatchOperation, _ string) ([]models.BatchResult, error) {
+	results := make([]models.BatchResult, len(operations))
+	added := slices.Clone(f.events)
+	for i, op := range operations {
+		results[i] = models.BatchResult{Op: op.Op, Status: models.BatchStatusApplied}
+
+		switch op.Op {
+		case models.BatchOpAddEvent:
+			op.Event.ID = "id-" + op.Event.Name
+			added = append(added, *op.Event)
+			results[i].ID = op.Event.ID
+		case models.BatchOpRemoveEvent:
+			if !slices.ContainsFunc(added, func(e models.Event) bool { return e.ID == op.ID }) {
+				for j := range i {
+					results[j].Status = models.BatchStatusRolledBack
+				}
+				results[i].Status = models.BatchStatusFailed
+				for j := i + 1; j < len(results); j++ {
+					results[j] = models.BatchResult{Op: operations[j].Op, Status: models.BatchStatusSkipped}
+				}
+
+				return results, domain.ErrEventNotFound
+			}
+		}
+	}
+
+	f.events = added
+
+	return results, nil
+}
+
+// version returns the index of the event with the version like the database.
+func (f *fakeService) version(id string, version int64) (int, error) {
+	i := slices.IndexFunc(f.events, func(e models.Event) bool { return e.ID == id })
+	if i < 0 {
+		return 0, domain.ErrEventNotFound
+	}
+
+	if f.events[i].Version != version {
+		return 0, domain.ErrEventVersion
+	}
+
+	return i, nil
+}
+
+func (f *fakeService) GetHistory(_ context.Context, q *query.Query) ([]models.EventHistory, error) {
+	f.query = q
+
+	return f.history, nil
+}
+
+func (f *fakeService) GetHistoryCount(_ context.Context, _ *query.Query) (uint64, error) {
+	return uint64(len(f.history)), nil
+}
+
+func (f *fakeService) GetEventHistory(_ context.Context, id string, q *query.Query) ([]models.EventHistory, error) {
+	f.query = q
+	f.historyID = id
+
+	return f.history, nil
+}
+
+func (f *fakeService) GetEventHistoryCount(_ context.Context, _ string, _ *query.Query) (uint64, error) {
+	return uint64(len(f.history)), nil
+}
+
+func (f *fakeService) GetRelations(_ context.Context, q *query.Query) ([]models.Relation, error) {
+	f.query = q
+
+	return f.relations, nil
+}
+
+func (f *fakeService) GetRelationsCount(_ context.Context, _ *query.Query) (uint64, error) {
+	return uint64(len(f.relations)), nil
+}
+
+func (f *fakeService) AddRelations(_ context.Context, relations []models.Relation) error {
+	f.relations = append(f.relations, relations...)
+
+	return nil
+}
+
+func (f *fakeService) UpdateRelations(_ context.Context, updates []models.RelationUpdate) error {
+	for _, u := range updates {
+		i := slices.IndexFunc(f.relations, func(r models.Relation) bool { return r == u.From })
+		if i < 0 {
+			return domain.ErrRelationNotFound
+		}
+
+		f.relations[i] = u.To
+	}
+
+	return nil
+}
+
+func (f *fakeService) GetJoint(_ context.Context, name string) (*models.Joint, error) {
+	if f.joint == nil || f.joint.Name != name {
+		return nil, nil
+	}
+
+	return f.joint, nil
+}
+
+func (f *fakeService) AddJoints(_ context.Context, joints []models.Joint) error {
+	f.joint = &joints[0]
+
+	return nil
+}
+
+func (f *fakeService) SetHours(_ context.Context, hours *models.BusinessHours) error {
+	f.hours = hours
+
+	return nil
+}
+
+func (f *fakeService) GetEntityHours(_ context.Context, _ string) (*models.BusinessHours, error) {
+	return f.hours, nil
+}
+
+func (f *fakeService) AddWeekends(_ context.Context, weekends []models.Weekend) error {
+	f.weekends = append(f.weekends, weekends...)
+
+	return nil
+}
+
+func (f *fakeService) GetWeekends(_ context.Context, q *query.Query) ([]models.Weekend, error) {
+	f.query = q
+
+	return f.weekends, nil
+}
+
+func (f *fakeService) GetWeekendsCount(_ context.Context, _ *query.Query) (uint64, error) {
+	return uint64(len(f.weekends)), nil
+}
+
+func (f *fakeService) AddIcal(_ context.Context, data io.Reader, _ *time.Location, group types.Null[string], _ string) error {
+	v, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+
+	f.ics = string(v)
+	f.group = group.V
+
+	return nil
+}
+
+func (f *fakeService) GetEventsICS(_ context.Context, q *query.Query) ([]models.Event, error) {
+	f.query = q
+
+	return f.events, nil
+}
+
+func (f *fakeService) Modified(_ context.Context, _ *query.Query) (*models.Modified, error) {
+	return &models.Modified{Events: len(f.events), Relations: len(f.relations)}, nil
+}
+
+func (f *fakeService) GetDefinitions(_ context.Context, q *query.Query) ([]models.Event, error) {
+	f.query = q
+
+	return f.events, nil
+}
+
+func (f *fakeService) WorkDay(_ context.Context, q *query.Query, date types.Time, days int) (*models.WorkDay, error) {
+	f.query = q
+
+	return &models.WorkDay{Date: types.Time{Time: date.AddDate(0, 0, days)}}, nil
+}
+
+func (f *fakeService) IsOpenAt(_ context.Context, q *query.Query, t types.Time) (*models.OpenAt, error) {
+	f.query = q
+
+	return &models.OpenAt{Time: t, Closed: q.GetValues("entity")}, nil
+}
+
+func (f *fakeService) Settlement(_ context.Context, _ *query.Query, req models.SettlementRequest) (*models.Settlement, error) {
+	return &models.Settlement{
+		Trade:     req.Trade,
+		TradeDate: req.Trade,
+		Date:      types.Time{Time: req.Trade.AddDate(0, 0, req.Days)},
+	}, nil
+}
+
+func (f *fakeService) Adjust(_ context.Context, _ *query.Query, date types.Time, convention string) (*models.Adjustment, error) {
+	return &models.Adjustment{Date: date, Adjusted: types.Time{Time: date.AddDate(0, 0, 1)}, Convention: convention}, nil
+}
+
+func (f *fakeService) Schedule(_ context.Context, _ *query.Query, _ string, from, _ types.Time) ([]types.Time, error) {
+	return []types.Time{from}, nil
+}
+
+func (f *fakeService) Bridges(_ context.Context, _ *query.Query, year, _ int) (*models.Bridges, error) {
+	return &models.Bridges{Year: year}, nil
+}
+
+func (f *fakeService) Diff(_ context.Context, base, target models.DiffSide) (*models.Diff, error) {
+	return &models.Diff{Base: base, Target: target, Unchanged: 1}, nil
+}
+
+func (f *fakeService) Coverage(_ context.Context, years int, _ float64) ([]models.Coverage, error) {
+	return []models.Coverage{
+		{Entity: "NLD", Year: 2030, Status: models.CoverageStatusOK},
+		{Entity: "BEL", Year: 2030 + years, Status: models.CoverageStatusMissing},
+	}, nil
+}
+
+func newTestClient(t *testing.T, svc *fakeService) *client.Calendar {
+	t.Helper()
+
+	h, err := handler.NewHTTP(svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	h.RegisterRoutes(e.Group(client.BasePath))
+
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(
+		klient.WithBaseURL(srv.URL),
+		klient.WithDisableRetry(true),
+		klient.WithDisableEnvValues(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEvents(t *testing.T) {
+	svc := &fakeService{}
+	c := newTestClient(t, svc)
+	ctx := context.Background()
+
+	resp, err := c.GetEvents(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetEvents() empty error = %v", err)
+	}
+	if len(resp.Payload) != 0 {
+		t.Errorf("GetEvents() empty = %v", resp.Payload)
+	}
+
+	ids, err := c.AddEvents(ctx, []models.Event{{
+		Name:     "Christmas",
+		DateFrom: types.Time{Time: day(2025, 12, 25)},
+		DateTo:   types.Time{Time: day(2025, 12, 26)},
+	}})
+	if err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "id-Christmas" {
+		t.Errorf("AddEvents() = %v", ids)
+	}
+
+	resp, err = c.GetEvents(ctx, url.Values{"name": {"Christmas"}})
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(resp.Payload) != 1 || resp.Meta == nil || resp.Meta.TotalItemCount != 1 {
+		t.Errorf("GetEvents() = %+v", resp)
+	}
+	if got := svc.query.GetValue("name"); got != "Christmas" {
+		t.Errorf("GetEvents() name filter = %q", got)
+	}
+
+	event, err := c.GetEvent(ctx, "id-Christmas")
+	if err != nil {
+		t.Fatalf("GetEvent() error = %v", err)
+	}
+	if event.Name != "Christmas" || !event.DateFrom.Equal(day(2025, 12, 25)) {
+		t.Errorf("GetEvent() = %+v", event)
+	}
+
+	stale := *event
+	event.Description = "updated"
+	if err := c.UpdateEvent(ctx, "id-Christmas", event); err != nil {
+		t.Fatalf("UpdateEvent() error = %v", err)
+	}
+	if svc.updated == nil || svc.updated.Description != "updated" || event.Version != 2 {
+		t.Errorf("UpdateEvent() = %+v version %d", svc.updated, event.Version)
+	}
+
+	var respErr *klient.ResponseError
+	if err := c.UpdateEvent(ctx, "id-Christmas", &stale); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("UpdateEvent() stale error = %v", err)
+	}
+	if err := c.DeleteEvent(ctx, "id-Christmas", stale.Version); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("DeleteEvent() stale error = %v", err)
+	}
+
+	patched, err := c.PatchEvent(ctx, "id-Christmas", event.Version, map[string]any{"disabled": true, "description": nil})
+	if err != nil {
+		t.Fatalf("PatchEvent() error = %v", err)
+	}
+	if !patched.Disabled || patched.Description != "" || patched.Name != "Christmas" || !patched.DateFrom.Equal(day(2025, 12, 25)) || patched.Version != 3 {
+		t.Errorf("PatchEvent() = %+v", patched)
+	}
+	if _, err := c.PatchEvent(ctx, "id-Christmas", event.Version, map[string]any{"disabled": false}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("PatchEvent() stale error = %v", err)
+	}
+	if _, err := c.PatchEvent(ctx, "id-Christmas", 0, map[string]any{"type": "unknown"}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("PatchEvent() invalid type error = %v", err)
+	}
+	if _, err := c.PatchEvent(ctx, "id-Christmas", 0, map[string]any{"id": "other"}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("PatchEvent() id error = %v", err)
+	}
+	event.Version = patched.Version
+	if err := c.DeleteEvent(ctx, "id-Christmas", event.Version); err != nil {
+		t.Fatalf("DeleteEvent() error = %v", err)
+	}
+	if err := c.DeleteEvent(ctx, "id-Christmas", 0); !client.IsNotFound(err) {
+		t.Errorf("DeleteEvent() missing error = %v", err)
+	}
+
+	trash, err := c.GetTrashEvents(ctx, url.Values{"deleted_at[gte]": {"2025-01-01T00:00:00Z"}})
+	if err != nil {
+		t.Fatalf("GetTrashEvents() error = %v", err)
+	}
+	if len(trash.Payload) != 1 || trash.Payload[0].ID != "id-Christmas" || !trash.Payload[0].DeletedAt.Valid || trash.Meta.TotalItemCount != 1 {
+		t.Errorf("GetTrashEvents() = %+v", trash.Payload)
+	}
+	if got := svc.query.GetValue("deleted_at"); got != "2025-01-01T00:00:00Z" {
+		t.Errorf("GetTrashEvents() deleted_at filter = %q", got)
+	}
+	if err := c.RestoreEvent(ctx, "id-Christmas"); err != nil {
+		t.Fatalf("RestoreEvent() error = %v", err)
+	}
+	if err := c.RestoreEvent(ctx, "id-Christmas"); !client.IsNotFound(err) {
+		t.Errorf("RestoreEvent() missing error = %v", err)
+	}
+	if restored, err := c.GetEvent(ctx, "id-Christmas"); err != nil || restored.DeletedAt.Valid {
+		t.Errorf("GetEvent() restored = %+v, %v", restored, err)
+	}
+	trash, err = c.GetTrashEvents(ctx, nil)
+	if err != nil || len(trash.Payload) != 0 {
+		t.Errorf("GetTrashEvents() empty = %+v, %v", trash, err)
+	}
+
+	svc.removed = nil
+	if _, err := c.AddEvents(ctx, []models.Event{{Name: "Boxing Day"}, {Name: "New Year"}}); err != nil {
+		t.Fatalf("AddEvents() error = %v", err)
+	}
+	if err := c.DeleteEvents(ctx, models.EventVersion{ID: "id-Boxing Day", Version: 1}, models.EventVersion{ID: "id-New Year", Version: 2}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("DeleteEvents() stale error = %v", err)
+	}
+	if len(svc.removed) != 0 {
+		t.Errorf("DeleteEvents() stale removed = %v", svc.removed)
+	}
+	if err := c.DeleteEvents(ctx, models.EventVersion{ID: "id-Boxing Day", Version: 1}, models.EventVersion{ID: "id-New Year", Version: 1}); err != nil {
+		t.Fatalf("DeleteEvents() error = %v", err)
+	}
+	if strings.Join(svc.removed, ",") != "id-Boxing Day,id-New Year" {
+		t.Errorf("DeleteEvents() removed = %v", svc.removed)
+	}
+
+	history, err := c.GetHistory(ctx, url.Values{"changed_by": {"admin"}})
+	if err != nil {
+		t.Fatalf("GetHistory() empty error = %v", err)
+	}
+	if len(history.Payload) != 0 {
+		t.Errorf("GetHistory() empty = %v", history.Payload)
+	}
+
+	svc.history = []models.EventHistory{
+		{ID: 2, EventID: "id-Christmas", Action: models.HistoryActionDelete, Version: 3, Before: types.NewJSON(*patched)},
+		{ID: 1, EventID: "id-Christmas", Action: models.HistoryActionUpdate, Version: 3, After: types.NewJSON(*patched)},
+	}
+	history, err = c.GetEventHistory(ctx, "id-Christmas", url.Values{"changed_at[gte]": {"2025-01-01T00:00:00Z"}})
+	if err != nil {
+		t.Fatalf("GetEventHistory() error = %v", err)
+	}
+	if len(history.Payload) != 2 || svc.historyID != "id-Christmas" || history.Payload[0].After.Valid || history.Payload[0].Before.V.Version != 3 {
+		t.Errorf("GetEventHistory() = %+v", history.Payload)
+	}
+	if got := svc.query.GetValue("changed_at"); got != "2025-01-01T00:00:00Z" {
+		t.Errorf("GetEventHistory() changed_at filter = %q", got)
+	}
+	if _, err := c.GetHistory(ctx, url.Values{"action": {"rename"}}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("GetHistory() invalid action error = %v", err)
+	}
+}
+
+func TestRelations(t *testing.T) {
+	svc := &fakeService{}
+	c := newTestClient(t, svc)
+	ctx := context.Background()
+
+	err := c.AddRelations(ctx, []models.Relation{{
+		Entity:     "NLD",
+		EventGroup: types.NewNull("NL"),
+	}})
+	if err != nil {
+		t.Fatalf("AddRelations() error = %v", err)
+	}
+
+	resp, err := c.GetRelations(ctx, url.Values{"entity": {"NLD"}})
+	if err != nil {
+		t.Fatalf("GetRelations() error = %v", err)
+	}
+	if len(resp.Payload) != 1 || resp.Payload[0].Type != models.RelationTypeInclude {
+		t.Errorf("GetRelations() = %+v", resp.Payload)
+	}
+	if got := svc.query.GetValue("entity"); got != "NLD" {
+		t.Errorf("GetRelations() entity filter = %q", got)
+	}
+
+	relations, err := c.PatchRelations(ctx, url.Values{"entity": {"NLD"}}, map[string]any{"event_group": "NL-NEW"})
+	if err != nil {
+		t.Fatalf("PatchRelations() error = %v", err)
+	}
+	if len(relations) != 1 || relations[0].EventGroup.V != "NL-NEW" || svc.relations[0].EventGroup.V != "NL-NEW" {
+		t.Errorf("PatchRelations() = %+v", relations)
+	}
+
+	var respErr *klient.ResponseError
+	_, err = c.PatchRelations(ctx, url.Values{"entity": {"NLD"}}, map[string]any{"event_group": nil})
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("PatchRelations() invalid error = %v", err)
+	}
+
+	trash, err := c.GetTrashRelations(ctx, url.Values{"entity": {"NLD"}})
+	if err != nil || len(trash.Payload) != 0 {
+		t.Errorf("GetTrashRelations() empty = %+v, %v", trash, err)
+	}
+	if err := c.RestoreRelations(ctx, url.Values{"entity": {"NLD"}, "event_group": {"NL"}}); err != nil {
+		t.Fatalf("RestoreRelations() error = %v", err)
+	}
+	if got := svc.query.GetValue("event_group"); got != "NL" {
+		t.Errorf("RestoreRelations() event_group filter = %q", got)
+	}
+	if err := c.RestoreRelations(ctx, url.Values{"event_group": {"NL"}}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("RestoreRelations() missing entity error = %v", err)
+	}
+}
+
+func TestChangeSets(t *testing.T) {
+	svc := &fakeService{}
+	c := newTestClient(t, svc)
+	ctx := context.Background()
+
+	var respErr *klient.ResponseError
+	_, err := c.AddChangeSet(ctx, models.ChangeSet{Name: "empty"})
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("AddChangeSet() empty error = %v", err)
+	}
+
+	changeSet, err := c.AddChangeSet(ctx, models.ChangeSet{
+		Name: "kings-day",
+		Changes: types.NewJSON(models.Changes{
+			AddEvents: []models.Event{{ID: "kings-day", Name: "Kings Day", DateFrom: types.Time{Time: day(2030, 4, 27)}}},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("AddChangeSet() error = %v", err)
+	}
+	if changeSet.ID != "cs-kings-day" || changeSet.Status != models.ChangeSetStatusDraft || changeSet.Changes.V.AddEvents[0].Type != models.EventTypeHoliday {
+		t.Errorf("AddChangeSet() = %+v", changeSet)
+	}
+
+	events, err := c.ChangeSetHolidays(ctx, changeSet.ID, day(2030, 4, 27), url.Values{"entity": {"NLD"}})
+	if err != nil {
+		t.Fatalf("ChangeSetHolidays() error = %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "kings-day" || len(svc.events) != 0 {
+		t.Errorf("ChangeSetHolidays() = %+v", events)
+	}
+	if got := svc.query.GetValue("date"); got != "2030-04-27" {
+		t.Errorf("ChangeSetHolidays() date = %q", got)
+	}
+	if _, err := c.ChangeSetHolidays(ctx, changeSet.ID, day(2030, 4, 27), url.Values{"as_of": {"2030-01-01T00:00:00Z"}}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("ChangeSetHolidays() as_of error = %v", err)
+	}
+
+	changeSet.Description = "moved"
+	if err := c.UpdateChangeSet(ctx, changeSet.ID, *changeSet); err != nil {
+		t.Fatalf("UpdateChangeSet() error = %v", err)
+	}
+
+	// the creator cannot approve its own change-set
+	if _, err := c.ApproveChangeSet(ctx, changeSet.ID); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusForbidden {
+		t.Errorf("ApproveChangeSet() creator error = %v", err)
+	}
+
+	svc.changes[0].CreatedBy = "alice"
+	approved, err := c.ApproveChangeSet(ctx, changeSet.ID)
+	if err != nil {
+		t.Fatalf("ApproveChangeSet() error = %v", err)
+	}
+	if approved.Status != models.ChangeSetStatusApplied || len(svc.events) != 1 {
+		t.Errorf("ApproveChangeSet() = %+v", approved)
+	}
+
+	if err := c.UpdateChangeSet(ctx, changeSet.ID, *changeSet); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusConflict {
+		t.Errorf("UpdateChangeSet() applied error = %v", err)
+	}
+
+	got, err := c.GetChangeSet(ctx, changeSet.ID)
+	if err != nil || got.Description != "moved" {
+		t.Errorf("GetChangeSet() = %+v, %v", got, err)
+	}
+	if _, err := c.GetChangeSet(ctx, "missing"); !client.IsNotFound(err) {
+		t.Errorf("GetChangeSet() missing error = %v", err)
+	}
+
+	resp, err := c.GetChangeSets(ctx, url.Values{"status": {models.ChangeSetStatusApplied}})
+	if err != nil || len(resp.Payload) != 1 {
+		t.Errorf("GetChangeSets() = %+v, %v", resp, err)
+	}
+	if _, err := c.GetChangeSets(ctx, url.Values{"status": {"unknown"}}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("GetChangeSets() invalid status error = %v", err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	svc := &fakeService{}
+	c := newTestClient(t, svc)
+	ctx := context.Background()
+
+	results, err := c.Batch(ctx, []models.BatchOperation{
+		{Op: models.BatchOpAddEvent, Event: &models.Event{Name: "Kings Day"}},
+		{Op: models.BatchOpAddRelation, Relation: &models.Relation{Entity: "NLD", EventID: types.NewNull("id-Kings Day")}},
+	})
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "id-Kings Day" || results[1].Status != models.BatchStatusApplied || len(svc.events) != 1 {
+		t.Errorf("Batch() = %+v", results)
+	}
+
+	// the failed operation rolls back the others
+	var respErr *klient.ResponseError
+	results, err = c.Batch(ctx, []models.BatchOperation{
+		{Op: models.BatchOpAddEvent, Event: &models.Event{Name: "Liberation Day"}},
+		{Op: models.BatchOpRemoveEvent, ID: "missing", Version: 1},
+		{Op: models.BatchOpRemoveRelation, Relation: &models.Relation{Entity: "NLD", EventID: types.NewNull("missing")}},
+	})
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Batch() missing error = %v", err)
+	}
+	statuses := []string{models.BatchStatusRolledBack, models.BatchStatusFailed, models.BatchStatusSkipped}
+	if len(results) != 3 || !slices.Equal([]string{results[0].Status, results[1].Status, results[2].Status}, statuses) || len(svc.events) != 1 {
+		t.Errorf("Batch() rolled back = %+v", results)
+	}
+
+	if _, err := c.Batch(ctx, []models.BatchOperation{{Op: models.BatchOpUpdateEvent, Event: &models.Event{Name: "Kings Day"}}}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Batch() missing ID error = %v", err)
+	}
+	if _, err := c.Batch(ctx, []models.BatchOperation{{Op: models.BatchOpRemoveEvent, ID: "id-Kings Day"}}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusPreconditionRequired {
+		t.Errorf("Batch() missing version error = %v", err)
+	}
+	if _, err := c.Batch(ctx, []models.BatchOperation{{Op: "move_event"}}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Batch() invalid op error = %v", err)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	svc := &fakeService{}
+	c := newTestClient(t, svc)
+	ctx := context.Background()
+
+	err := c.AddJoints(ctx, []models.Joint{{Name: "EU", Entities: types.Slice[string]{"NLD", "BEL"}}})
+	if err != nil {
+		t.Fatalf("AddJoints() error = %v", err)
+	}
+
+	joint, err := c.GetJoint(ctx, "EU")
+	if err != nil {
+		t.Fatalf("GetJoint() error = %v", err)
+	}
+	if len(joint.Entities) != 2 {
+		t.Errorf("GetJoint() = %+v", joint)
+	}
+
+	if _, err := c.GetJoint(ctx, "US"); !client.IsNotFound(err) {
+		t.Errorf("GetJoint() missing error = %v", err)
+	}
+
+	err = c.SetEntityHours(ctx, "XAMS", &models.BusinessHours{
+		Tz:    "Europe/Amsterdam",
+		Hours: types.Map[[]models.Window]{"monday": {{From: "09:00", To: "17:30"}}},
+	})
+	if err != nil {
+		t.Fatalf("SetEntityHours() error = %v", err)
+	}
+
+	hours, err := c.GetEntityHours(ctx, "XAMS")
+	if err != nil {
+		t.Fatalf("GetEntityHours() error = %v", err)
+	}
+	if hours.Entity != "XAMS" || hours.Tz != "Europe/Amsterdam" {
+		t.Errorf("GetEntityHours() = %+v", hours)
+	}
+
+	err = c.AddWeekends(ctx, []models.Weekend{{
+		Entity:        "ARE",
+		EffectiveFrom: types.Time{Time: day(2022, 1, 1)},
+		Days:          types.Slice[string]{"saturday", "sunday"},
+	}})
+	if err != nil {
+		t.Fatalf("AddWeekends() error = %v", err)
+	}
+
+	weekends, err := c.GetWeekends(ctx, url.Values{"entity": {"ARE"}})
+	if err != nil {
+		t.Fatalf("GetWeekends() error = %v", err)
+	}
+	if len(weekends.Payload) != 1 {
+		t.Errorf("GetWeekends() = %+v", weekends.Payload)
+	}
+}
+
+func TestBusinessDays(t *testing.T) {
+	svc := &fakeService{
+		events: []models.Event{{ID: "xmas", Name: "Christmas", DateFrom: types.Time{Time: day(2025, 12, 25)}}},
+	}
+	c := newTestClient(t, svc)
+	ctx := context.Background()
+	filter := url.Values{"entity": {"NLD"}}
+
+	holidays, err := c.Holidays(ctx, day(2025, 12, 25), filter)
+	if err != nil {
+		t.Fatalf("Holidays() error = %v", err)
+	}
+	if len(holidays) != 1 || svc.query.GetValue("entity") != "NLD" {
+		t.Errorf("Holidays() = %+v", holidays)
+	}
+
+	if _, err := c.Holidays(ctx, day(2025, 12, 25), url.Values{"entity": {"NLD"}, "as_of": {"2025-03-01T12:00:00Z"}}); err != nil {
+		t.Fatalf("Holidays() as_of error = %v", err)
+	}
+	if got := svc.query.GetValue("as_of"); got != "2025-03-01T12:00:00Z" {
+		t.Errorf("Holidays() as_of = %q", got)
+	}
+
+	var respErr *klient.ResponseError
+	if _, err := c.Holidays(ctx, day(2025, 12, 25), url.Values{"as_of": {"yesterday"}}); !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Holidays() invalid as_of error = %v", err)
+	}
+
+	workDay, err := c.WorkDay(ctx, day(2025, 12, 24), 2, filter)
+	if err != nil {
+		t.Fatalf("WorkDay() error = %v", err)
+	}
+	if !workDay.Date.Equal(day(2025, 12, 26)) {
+		t.Errorf("WorkDay() = %v", workDay.Date)
+	}
+
+	openAt, err := c.IsOpenAt(ctx, day(2025, 12, 24).Add(14*time.Hour), url.Values{"entity": {"NLD,BEL"}})
+	if err != nil {
+		t.Fatalf("IsOpenAt() error = %v", err)
+	}
+	if len(openAt.Closed) != 2 {
+		t.Errorf("IsOpenAt() = %+v", openAt)
+	}
+
+	settlement, err := c.Settlement(ctx, models.SettlementRequest{
+		Trade:  types.Time{Time: day(2025, 4, 17).Add(14 * time.Hour)},
+		Days:   2,
+		CutOff: "16:00",
+		Tz:     "Europe/Amsterdam",
+	}, filter)
+	if err != nil {
+		t.Fatalf("Settlement() error = %v", err)
+	}
+	if !settlement.Date.Equal(day(2025, 4, 19).Add(14 * time.Hour)) {
+		t.Errorf("Settlement() = %+v", settlement)
+	}
+
+	adjustment, err := c.Adjust(ctx, day(2025, 5, 31), models.ConventionModifiedFollowing, filter)
+	if err != nil {
+		t.Fatalf("Adjust() error = %v", err)
+	}
+	if adjustment.Convention != models.ConventionModifiedFollowing {
+		t.Errorf("Adjust() = %+v", adjustment)
+	}
+
+	if _, err := c.Adjust(ctx, day(2025, 5, 31), "nearest", filter); err == nil {
+		t.Error("Adjust() invalid convention error = nil")
+	}
+
+	dates, err := c.Schedule(ctx, "FREQ=MONTHLY;BYBUSINESSDAY=1,-1", day(2025, 1, 1), day(2026, 1, 1), filter)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if len(dates) != 1 || !dates[0].Equal(day(2025, 1, 1)) {
+		t.Errorf("Schedule() = %v", dates)
+	}
+
+	bridges, err := c.Bridges(ctx, 2025, 1, filter)
+	if err != nil {
+		t.Fatalf("Bridges() error = %v", err)
+	}
+	if bridges.Year != 2025 {
+		t.Errorf("Bridges() = %+v", bridges)
+	}
+
+	diff, err := c.Diff(ctx, models.DiffSide{Entity: "NLD", Year: 2025}, models.DiffSide{Entity: "BEL", Year: 2026})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if diff.Base.Entity != "NLD" || diff.Target.Year != 2026 {
+		t.Errorf("Diff() = %+v", diff)
+	}
+
+	coverage, err := c.Coverage(ctx, 2, 0.5, false)
+	if err != nil {
+		t.Fatalf("Coverage() error = %v", err)
+	}
+	if len(coverage) != 1 || coverage[0].Entity != "BEL" {
+		t.Errorf("Coverage() = %+v", coverage)
+	}
+}
+
+func TestICS(t *testing.T) {
+	svc := &fakeService{
+		events: []models.Event{{
+			ID:       "xmas",
+			Name:     "Christmas",
+			DateFrom: types.Time{Time: day(2025, 12, 25)},
+			DateTo:   types.Time{Time: day(2025, 12, 26)},
+		}},
+	}
+	c := newTestClient(t, svc)
+	ctx := context.Background()
+
+	data := "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"
+	if err := c.AddICS(ctx, strings.NewReader(data), "NL", "Europe/Amsterdam"); err != nil {
+		t.Fatalf("AddICS() error = %v", err)
+	}
+	if svc.ics != data || svc.group != "NL" {
+		t.Errorf("AddICS() = %q %q", svc.ics, svc.group)
+	}
+
+	ics, err := c.GetICS(ctx, url.Values{"entity": {"NLD"}, "year": {"2025"}})
+	if err != nil {
+		t.Fatalf("GetICS() error = %v", err)
+	}
+	if !strings.Contains(string(ics), "SUMMARY:Christmas") {
+		t.Errorf("GetICS() = %s", ics)
+	}
+}
+
+func TestError(t *testing.T) {
+	c := newTestClient(t, &fakeService{})
+
+	_, err := c.WorkDay(context.Background(), day(2025, 1, 1), 1, url.Values{"unknown": {"x"}})
+
+	var respErr *klient.ResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("WorkDay() error = %v", err)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &fakeService{
+		events: []models.Event{
+			{
+				ID:       "kings",
+				Name:     "King's Day",
+				DateFrom: types.Time{Time: time.Date(2014, 4, 27, 0, 0, 0, 0, amsterdam)},
+				DateTo:   types.Time{Time: time.Date(2014, 4, 28, 0, 0, 0, 0, amsterdam)},
+				RRule:    "RRULE:FREQ=YEARLY",
+				Tz:       "Europe/Amsterdam",
+				ExDates:  []types.Time{{Time: time.Date(2025, 4, 27, 0, 0, 0, 0, time.UTC)}},
+			},
+			{
+				ID:       "easter",
+				Name:     "Easter Monday",
+				DateFrom: types.Time{Time: day(2000, 1, 1)},
+				DateTo:   types.Time{Time: day(2000, 1, 2)},
+				RRule:    "FUNC:EasterMonday",
+				Tz:       "UTC",
+			},
+			{
+				ID:       "eve",
+				Name:     "New Year's Eve",
+				DateFrom: types.Time{Time: day(2025, 12, 31).Add(13 * time.Hour)},
+				DateTo:   types.Time{Time: day(2026, 1, 1)},
+				Type:     models.EventTypeHalfDay,
+				Tz:       "UTC",
+			},
+		},
+	}
+	c := newTestClient(t, svc)
+	ctx := context.Background()
+
+	o, err := c.Offline(ctx, url.Values{"entity": {"NLD"}})
+	if err != nil {
+		t.Fatalf("Offline() error = %v", err)
+	}
+	if got := svc.query.GetValue("entity"); got != "NLD" {
+		t.Errorf("Offline() entity filter = %q", got)
+	}
+
+	tests := []struct {
+		name    string
+		time    time.Time
+		want    string
+		holiday bool
+	}{
+		{name: "rrule in timezone", time: time.Date(2026, 4, 27, 12, 0, 0, 0, amsterdam), want: "King's Day", holiday: true},
+		{name: "rrule before midnight UTC", time: time.Date(2026, 4, 26, 22, 30, 0, 0, time.UTC), want: "King's Day", holiday: true},
+		{name: "excluded occurrence", time: time.Date(2025, 4, 27, 12, 0, 0, 0, amsterdam)},
+		{name: "function", time: day(2026, 4, 6), want: "Easter Monday", holiday: true},
+		{name: "half-day", time: day(2025, 12, 31).Add(14 * time.Hour), want: "New Year's Eve"},
+		{name: "before half-day", time: day(2025, 12, 31).Add(12 * time.Hour)},
+		{name: "workday", time: day(2026, 4, 7)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if events := o.Holidays(tt.time); len(events) > 0 {
+				got = events[0].Name
+			}
+
+			if got != tt.want {
+				t.Errorf("Holidays() = %q, want %q", got, tt.want)
+			}
+
+			if holiday := o.IsHoliday(tt.time); holiday != tt.holiday {
+				t.Errorf("IsHoliday() = %v, want %v", holiday, tt.holiday)
+			}
+		})
+	}
+
+	changed, err := o.Refresh(ctx)
+	if err != nil || changed {
+		t.Fatalf("Refresh() unchanged = %v, %v", changed, err)
+	}
+
+	svc.events = svc.events[1:]
+
+	changed, err = o.Refresh(ctx)
+	if err != nil || !changed {
+		t.Fatalf("Refresh() changed = %v, %v", changed, err)
+	}
+
+	if o.IsHoliday(time.Date(2026, 4, 27, 12, 0, 0, 0, amsterdam)) {
+		t.Error("IsHoliday() after refresh = true")
+	}
+}
@@ -0,0 +1,305 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/worldline-go/klient"
+	"github.com/worldline-go/rest"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// ///////////////////////////////////////////////////////////////
+// Events
+// ///////////////////////////////////////////////////////////////
+
+// GetEvents returns the events matching the filter like name, event_group, entity, limit and offset.
+// No matching event is an empty response.
+func (c *Calendar) GetEvents(ctx context.Context, filter url.Values) (*rest.Response[[]models.Event], error) {
+	var resp rest.Response[[]models.Event]
+	if err := c.do(ctx, http.MethodGet, "/events", filter, nil, &resp); err != nil {
+		if IsNotFound(err) {
+			return &rest.Response[[]models.Event]{}, nil
+		}
+
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AddEvents adds the events and returns their IDs.
+func (c *Calendar) AddEvents(ctx context.Context, events []models.Event) ([]string, error) {
+	var resp rest.Response[[]string]
+	if err := c.do(ctx, http.MethodPost, "/events", nil, events, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
+
+// GetEvent returns the event with the ID.
+func (c *Calendar) GetEvent(ctx context.Context, id string) (*models.Event, error) {
+	var resp rest.Response[models.Event]
+	if err := c.do(ctx, http.MethodGet, "/events/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// UpdateEvent replaces the event with the ID when the stored version is the version of the event.
+// Version of the event is set to the new version, zero version replaces any version.
+// A changed event returns a 412 ResponseError.
+func (c *Calendar) UpdateEvent(ctx context.Context, id string, event *models.Event) error {
+	req, err := request(ctx, http.MethodPut, "/events/"+url.PathEscape(id), nil, event)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("If-Match", ifMatch(event.Version))
+
+	return c.klient.Do(req, func(r *http.Response) error {
+		if err := klient.UnexpectedResponse(r); err != nil {
+			return err
+		}
+
+		if v, err := strconv.ParseInt(strings.Trim(r.Header.Get("ETag"), `"`), 10, 64); err == nil {
+			event.Version = v
+		}
+
+		return nil
+	})
+}
+
+// PatchEvent changes only the fields of the JSON merge patch like map[string]any{"disabled": true}, nil values clear the field.
+// Version is checked like UpdateEvent, the changed event is returned.
+func (c *Calendar) PatchEvent(ctx context.Context, id string, version int64, patch any) (*models.Event, error) {
+	req, err := request(ctx, http.MethodPatch, "/events/"+url.PathEscape(id), nil, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", MIMEMergePatch)
+	req.Header.Set("If-Match", ifMatch(version))
+
+	var resp rest.Response[models.Event]
+	if err := c.klient.Do(req, klient.ResponseFuncJSON(&resp)); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// DeleteEvent moves the event with the ID to the trash when the stored version is the version, zero version removes any version.
+func (c *Calendar) DeleteEvent(ctx context.Context, id string, version int64) error {
+	req, err := request(ctx, http.MethodDelete, "/events/"+url.PathEscape(id), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("If-Match", ifMatch(version))
+
+	return c.klient.Do(req, klient.ResponseFuncJSON(nil))
+}
+
+// DeleteEvents moves the events to the trash when all of them have their versions, none of them is removed otherwise.
+func (c *Calendar) DeleteEvents(ctx context.Context, events ...models.EventVersion) error {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID+":"+strconv.FormatInt(e.Version, 10))
+	}
+
+	return c.do(ctx, http.MethodDelete, "/events", url.Values{"id": {strings.Join(ids, ",")}}, nil, nil)
+}
+
+func ifMatch(version int64) string {
+	if version == 0 {
+		return "*"
+	}
+
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ///////////////////////////////////////////////////////////////
+// History
+// ///////////////////////////////////////////////////////////////
+
+// GetEventHistory returns the recorded changes of the event, also of a removed one, the latest change is first.
+// Filter could have action, changed_by and changed_at like "changed_at[gte]".
+func (c *Calendar) GetEventHistory(ctx context.Context, id string, filter url.Values) (*rest.Response[[]models.EventHistory], error) {
+	return c.getHistory(ctx, "/events/"+url.PathEscape(id)+"/history", filter)
+}
+
+// GetHistory returns the recorded changes of all events matching the filter like event_id, changed_by and changed_at.
+func (c *Calendar) GetHistory(ctx context.Context, filter url.Values) (*rest.Response[[]models.EventHistory], error) {
+	return c.getHistory(ctx, "/history", filter)
+}
+
+// getHistory returns an empty response when no change is found.
+func (c *Calendar) getHistory(ctx context.Context, path string, filter url.Values) (*rest.Response[[]models.EventHistory], error) {
+	var resp rest.Response[[]models.EventHistory]
+	if err := c.do(ctx, http.MethodGet, path, filter, nil, &resp); err != nil {
+		if IsNotFound(err) {
+			return &rest.Response[[]models.EventHistory]{}, nil
+		}
+
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ///////////////////////////////////////////////////////////////
+// Relations
+// ///////////////////////////////////////////////////////////////
+
+// GetRelations returns the relations matching the filter like entity, event_id and event_group.
+func (c *Calendar) GetRelations(ctx context.Context, filter url.Values) (*rest.Response[[]models.Relation], error) {
+	var resp rest.Response[[]models.Relation]
+	if err := c.do(ctx, http.MethodGet, "/relations", filter, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AddRelations adds the relations.
+func (c *Calendar) AddRelations(ctx context.Context, relations []models.Relation) error {
+	return c.do(ctx, http.MethodPost, "/relations", nil, relations, nil)
+}
+
+// PatchRelations changes the relations matching the filter in place with the JSON merge patch, the changed relations are returned.
+func (c *Calendar) PatchRelations(ctx context.Context, filter url.Values, patch any) ([]models.Relation, error) {
+	req, err := request(ctx, http.MethodPatch, "/relations", filter, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", MIMEMergePatch)
+
+	var resp rest.Response[[]models.Relation]
+	if err := c.klient.Do(req, klient.ResponseFuncJSON(&resp)); err != nil {
+		return nil, err
+	}
+
+	return resp.Payload, nil
+}
+
+// DeleteRelations moves the relations matching the filter to the trash.
+func (c *Calendar) DeleteRelations(ctx context.Context, filter url.Values) error {
+	return c.do(ctx, http.MethodDelete, "/relations", filter, nil, nil)
+}
+
+// ///////////////////////////////////////////////////////////////
+// Trash
+// ///////////////////////////////////////////////////////////////
+
+// GetTrashEvents returns the removed events matching the filter like deleted_by and "deleted_at[gte]", the latest removed is first.
+// No removed event is an empty response.
+func (c *Calendar) GetTrashEvents(ctx context.Context, filter url.Values) (*rest.Response[[]models.Event], error) {
+	var resp rest.Response[[]models.Event]
+	if err := c.do(ctx, http.MethodGet, "/trash/events", filter, nil, &resp); err != nil {
+		if IsNotFound(err) {
+			return &rest.Response[[]models.Event]{}, nil
+		}
+
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetTrashRelations returns the removed relations matching the filter like entity, event_id and deleted_by.
+// No removed relation is an empty response.
+func (c *Calendar) GetTrashRelations(ctx context.Context, filter url.Values) (*rest.Response[[]models.Relation], error) {
+	var resp rest.Response[[]models.Relation]
+	if err := c.do(ctx, http.MethodGet, "/trash/relations", filter, nil, &resp); err != nil {
+		if IsNotFound(err) {
+			return &rest.Response[[]models.Relation]{}, nil
+		}
+
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RestoreEvent takes the event back from the trash with the relations removed together with it.
+func (c *Calendar) RestoreEvent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/events/"+url.PathEscape(id)+"/restore", nil, nil, nil)
+}
+
+// RestoreRelations takes the relations matching the filter back from the trash, entity is required.
+func (c *Calendar) RestoreRelations(ctx context.Context, filter url.Values) error {
+	return c.do(ctx, http.MethodPost, "/relations/restore", filter, nil, nil)
+}
+
+// ///////////////////////////////////////////////////////////////
+// iCal
+// ///////////////////////////////////////////////////////////////
+
+// AddICS uploads the ICS data, eventGroup and tz are optional.
+func (c *Calendar) AddICS(ctx context.Context, data io.Reader, eventGroup, tz string) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", "calendar.ics")
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+
+	if _, err := io.Copy(part, data); err != nil {
+		return fmt.Errorf("failed to write form file: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close form: %w", err)
+	}
+
+	u := BasePath + "/ics"
+	if values := with(nil, "event_group", eventGroup, "tz", tz); len(values) > 0 {
+		u += "?" + values.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return c.klient.Do(req, klient.ResponseFuncJSON(nil))
+}
+
+// GetICS returns the ICS file of the events matching the filter like entity, event_group, joint and year.
+func (c *Calendar) GetICS(ctx context.Context, filter url.Values) ([]byte, error) {
+	req, err := request(ctx, http.MethodGet, "/ics", filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = c.klient.Do(req, func(resp *http.Response) error {
+		if err := klient.UnexpectedResponse(resp); err != nil {
+			return err
+		}
+
+		data, err = io.ReadAll(resp.Body)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/worldline-go/klient"
+	"github.com/worldline-go/rest"
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/ical"
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Offline evaluates the holidays locally with the event definitions downloaded once from the server.
+// Definitions are refreshed with Refresh, unchanged ones are not downloaded again with the ETag.
+//
+// Occurrences are calculated with pkg/ical like the server, weekends and joint calendars are not applied.
+type Offline struct {
+	c      *Calendar
+	filter url.Values
+
+	m           sync.RWMutex
+	etag        string
+	definitions []definition
+	// years are the occurrences overlapping with the UTC year
+	years map[int][]models.Event
+}
+
+type definition struct {
+	event  models.Event
+	repeat *ical.Repeat
+}
+
+// Offline downloads the event definitions matching the filter like entity and event_group.
+func (c *Calendar) Offline(ctx context.Context, filter url.Values) (*Offline, error) {
+	o := &Offline{
+		c:      c,
+		filter: filter,
+	}
+
+	if _, err := o.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Refresh downloads the event definitions again when they are changed on the server.
+// It reports whether the definitions are changed.
+func (o *Offline) Refresh(ctx context.Context) (bool, error) {
+	req, err := request(ctx, http.MethodGet, "/definitions", o.filter, nil)
+	if err != nil {
+		return false, err
+	}
+
+	o.m.RLock()
+	etag := o.etag
+	o.m.RUnlock()
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	var (
+		resp    rest.Response[[]models.Event]
+		newETag string
+		changed bool
+	)
+
+	err = o.c.klient.Do(req, func(r *http.Response) error {
+		if r.StatusCode == http.StatusNotModified {
+			return nil
+		}
+
+		if err := klient.UnexpectedResponse(r); err != nil {
+			return err
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+			return fmt.Errorf("decode response body: %w", err)
+		}
+
+		newETag = r.Header.Get("ETag")
+		changed = true
+
+		return nil
+	})
+	if err != nil || !changed {
+		return false, err
+	}
+
+	definitions := make([]definition, 0, len(resp.Payload))
+	for _, h := range resp.Payload {
+		if h.Disabled {
+			continue
+		}
+
+		// occurrences are calculated in the event's timezone like the server
+		if h.Tz != "" {
+			loc, err := time.LoadLocation(h.Tz)
+			if err != nil {
+				return false, fmt.Errorf("failed to load timezone of event %s: %w", h.ID, err)
+			}
+
+			h.DateFrom = types.Time{Time: h.DateFrom.In(loc)}
+			h.DateTo = types.Time{Time: h.DateTo.In(loc)}
+		}
+
+		v := definition{event: h}
+		if strings.TrimSpace(h.RRule) != "" {
+			v.repeat, err = ical.ParseRepeat(h.RRule)
+			if err != nil {
+				return false, fmt.Errorf("failed to parse rrule of event %s: %w", h.ID, err)
+			}
+		}
+
+		definitions = append(definitions, v)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.etag = newETag
+	o.definitions = definitions
+	o.years = make(map[int][]models.Event)
+
+	return true, nil
+}
+
+// Holidays returns the occurrences containing the time, same as the holidays endpoint without weekends.
+func (o *Offline) Holidays(t time.Time) []models.Event {
+	var events []models.Event
+	for _, occ := range o.year(t.UTC().Year()) {
+		if !occ.DateFrom.After(t) && occ.DateTo.After(t) {
+			events = append(events, occ)
+		}
+	}
+
+	return events
+}
+
+// IsHoliday reports whether a holiday contains the time, half-day events are not holidays.
+func (o *Offline) IsHoliday(t time.Time) bool {
+	for _, occ := range o.Holidays(t) {
+		if occ.Type != models.EventTypeHalfDay {
+			return true
+		}
+	}
+
+	return false
+}
+
+// year returns the occurrences overlapping with the UTC year, calculated once per definitions.
+func (o *Offline) year(year int) []models.Event {
+	o.m.RLock()
+	occurrences, ok := o.years[year]
+	o.m.RUnlock()
+
+	if ok {
+		return occurrences
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if occurrences, ok := o.years[year]; ok {
+		return occurrences
+	}
+
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	occurrences = []models.Event{}
+	for _, v := range o.definitions {
+		occurrences = append(occurrences, ical.Occurrences(v.event, v.repeat, from, to)...)
+	}
+
+	o.years[year] = occurrences
+
+	return occurrences
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/worldline-go/rest"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// ///////////////////////////////////////////////////////////////
+// Joints
+// ///////////////////////////////////////////////////////////////
+
+// GetJoints returns the joint calendars matching the filter like name.
+func (c *Calendar) GetJoints(ctx context.Context, filter url.Values) (*rest.Response[[]models.Joint], error) {
+	var resp rest.Response[[]models.Joint]
+	if err := c.do(ctx, http.MethodGet, "/joints", filter, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AddJoints adds the joint calendars.
+func (c *Calendar) AddJoints(ctx context.Context, joints []models.Joint) error {
+	return c.do(ctx, http.MethodPost, "/joints", nil, joints, nil)
+}
+
+// GetJoint returns the joint calendar with the name.
+func (c *Calendar) GetJoint(ctx context.Context, name string) (*models.Joint, error) {
+	var resp rest.Response[models.Joint]
+	if err := c.do(ctx, http.MethodGet, "/joints/"+url.PathEscape(name), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// UpdateJoint replaces the joint calendar with the name.
+func (c *Calendar) UpdateJoint(ctx context.Context, name string, joint *models.Joint) error {
+	return c.do(ctx, http.MethodPut, "/joints/"+url.PathEscape(name), nil, joint, nil)
+}
+
+// DeleteJoint removes the joint calendar with the name.
+func (c *Calendar) DeleteJoint(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/joints/"+url.PathEscape(name), nil, nil, nil)
+}
+
+// ///////////////////////////////////////////////////////////////
+// Business hours
+// ///////////////////////////////////////////////////////////////
+
+// GetHours returns the business hours matching the filter like entity.
+func (c *Calendar) GetHours(ctx context.Context, filter url.Values) (*rest.Response[[]models.BusinessHours], error) {
+	var resp rest.Response[[]models.BusinessHours]
+	if err := c.do(ctx, http.MethodGet, "/hours", filter, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetEntityHours returns the business hours of the entity.
+func (c *Calendar) GetEntityHours(ctx context.Context, entity string) (*models.BusinessHours, error) {
+	var resp rest.Response[models.BusinessHours]
+	if err := c.do(ctx, http.MethodGet, "/hours/"+url.PathEscape(entity), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Payload, nil
+}
+
+// SetEntityHours sets the business hours of the entity.
+func (c *Calendar) SetEntityHours(ctx context.Context, entity string, hours *models.BusinessHours) error {
+	return c.do(ctx, http.MethodPut, "/hours/"+url.PathEscape(entity), nil, hours, nil)
+}
+
+// DeleteEntityHours removes the business hours of the entity.
+func (c *Calendar) DeleteEntityHours(ctx context.Context, entity string) error {
+	return c.do(ctx, http.MethodDelete, "/hours/"+url.PathEscape(entity), nil, nil, nil)
+}
+
+// ///////////////////////////////////////////////////////////////
+// Weekends
+// ///////////////////////////////////////////////////////////////
+
+// GetWeekends returns the weekend definitions matching the filter like entity.
+func (c *Calendar) GetWeekends(ctx context.Context, filter url.Values) (*rest.Response[[]models.Weekend], error) {
+	var resp rest.Response[[]models.Weekend]
+	if err := c.do(ctx, http.MethodGet, "/weekends", filter, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AddWeekends adds or replaces the weekend definitions.
+func (c *Calendar) AddWeekends(ctx context.Context, weekends []models.Weekend) error {
+	return c.do(ctx, http.MethodPost, "/weekends", nil, weekends, nil)
+}
+
+// DeleteWeekends removes the weekend definitions matching the filter like entity and effective_from.
+func (c *Calendar) DeleteWeekends(ctx context.Context, filter url.Values) error {
+	return c.do(ctx, http.MethodDelete, "/weekends", filter, nil, nil)
+}
@@ -71,6 +71,10 @@ func GenerateICS(events []models.Event, category string) (string, error) {
 
 		if e.RRule != "" {
 			b.WriteString(fmt.Sprintf("RRULE:%s\r\n", e.RRule))
+
+			if len(e.ExDates) > 0 {
+				b.WriteString(exDateICS(e.ExDates, from, isAllDay))
+			}
 		}
 		b.WriteString("TRANSP:TRANSPARENT\r\n")
 		b.WriteString("END:VEVENT\r\n")
@@ -217,6 +221,32 @@ func ParseICS(data io.Reader, tz *time.Location) ([]models.Event, error) {
 	return events, nil
 }
 
+// exDateICS returns the EXDATE line, days are placed on the time of the event start.
+func exDateICS(exDates []types.Time, from time.Time, isAllDay bool) string {
+	values := make([]string, 0, len(exDates))
+	for _, exDate := range exDates {
+		day := time.Date(exDate.Year(), exDate.Month(), exDate.Day(), from.Hour(), from.Minute(), from.Second(), 0, from.Location())
+
+		switch {
+		case isAllDay:
+			values = append(values, day.Format("20060102"))
+		case from.Location() != time.UTC:
+			values = append(values, day.Format("20060102T150405"))
+		default:
+			values = append(values, day.UTC().Format("20060102T150405Z"))
+		}
+	}
+
+	switch {
+	case isAllDay:
+		return fmt.Sprintf("EXDATE;VALUE=DATE:%s\r\n", strings.Join(values, ","))
+	case from.Location() != time.UTC:
+		return fmt.Sprintf("EXDATE;TZID=%s:%s\r\n", from.Location().String(), strings.Join(values, ","))
+	default:
+		return fmt.Sprintf("EXDATE:%s\r\n", strings.Join(values, ","))
+	}
+}
+
 // escapeICS escapes special characters for ICS fields
 func escapeICS(s string) string {
 	s = strings.ReplaceAll(s, "\\", "\\\\")
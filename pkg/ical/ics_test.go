@@ -55,6 +55,41 @@ func TestGenerateICS(t *testing.T) {
 				"END:VCALENDAR\r\n",
 			wantErr: false,
 		},
+		{
+			name: "excluded occurrence",
+			args: args{
+				events: []models.Event{
+					{
+						ID:       "liberation-day",
+						Name:     "Liberation Day",
+						DateFrom: types.Time{Time: time.Date(2020, 5, 5, 0, 0, 0, 0, time.UTC)},
+						DateTo:   types.Time{Time: time.Date(2020, 5, 6, 0, 0, 0, 0, time.UTC)},
+						RRule:    "FREQ=YEARLY;BYMONTH=5;BYMONTHDAY=5",
+						AllDay:   true,
+						ExDates: []types.Time{
+							{Time: time.Date(2021, 5, 5, 0, 0, 0, 0, time.UTC)},
+							{Time: time.Date(2022, 5, 5, 0, 0, 0, 0, time.UTC)},
+						},
+					},
+				},
+			},
+			want: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//worldline-go//calendar//EN\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:liberation-day\r\n" +
+				"CATEGORIES:Holidays\r\n" +
+				"CLASS:PUBLIC\r\n" +
+				"SUMMARY:Liberation Day\r\n" +
+				"DTSTART;VALUE=DATE:20200505\r\n" +
+				"DTEND;VALUE=DATE:20200506\r\n" +
+				"RRULE:FREQ=YEARLY;BYMONTH=5;BYMONTHDAY=5\r\n" +
+				"EXDATE;VALUE=DATE:20210505,20220505\r\n" +
+				"TRANSP:TRANSPARENT\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
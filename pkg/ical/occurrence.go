@@ -0,0 +1,92 @@
+package ical
+
+import (
+	"slices"
+	"time"
+
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+// Occurrences returns the occurrences of the event overlapping with [from, to), sorted by start.
+// The repeat is the parsed RRule of the event, nil for an event without repetition.
+// Occurrences starting on one of the event's ExDates are skipped.
+func Occurrences(e models.Event, repeat *Repeat, from, to time.Time) []models.Event {
+	var occurrences []models.Event
+
+	add := func(start, end time.Time) {
+		if !start.Before(to) || !end.After(from) {
+			return
+		}
+
+		if isExDate(e.ExDates, start) {
+			return
+		}
+
+		for _, occ := range occurrences {
+			if occ.DateFrom.Equal(start) {
+				return
+			}
+		}
+
+		occ := e
+		occ.DateFrom = types.Time{Time: start}
+		occ.DateTo = types.Time{Time: end}
+
+		occurrences = append(occurrences, occ)
+	}
+
+	if repeat == nil {
+		add(e.DateFrom.Time, e.DateTo.Time)
+
+		return occurrences
+	}
+
+	for _, rrule := range repeat.RRule {
+		cursor := from
+		for {
+			start, end, ok := MatchRRuleBetween(rrule, e.DateFrom.Time, e.DateTo.Time, cursor, to)
+			if !ok || !start.Before(to) {
+				break
+			}
+
+			add(start, end)
+
+			// continue right after the found occurrence
+			if end.Before(start) || end.Equal(start) {
+				end = start
+			}
+			cursor = end.Add(time.Nanosecond)
+		}
+	}
+
+	loc := e.DateFrom.Location()
+	for _, yearFn := range repeat.Func {
+		for year := from.Year() - 1; year <= to.Year(); year++ {
+			day := yearFn(year)
+			start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+			add(start, start.AddDate(0, 0, 1))
+		}
+	}
+
+	slices.SortFunc(occurrences, func(a, b models.Event) int {
+		return a.DateFrom.Compare(b.DateFrom.Time)
+	})
+
+	return occurrences
+}
+
+// isExDate reports whether start is on one of the excluded days, compared in the start's timezone.
+func isExDate(exDates []types.Time, start time.Time) bool {
+	year, month, day := start.Date()
+	for _, exDate := range exDates {
+		exYear, exMonth, exDay := exDate.Date()
+		if exYear == year && exMonth == month && exDay == day {
+			return true
+		}
+	}
+
+	return false
+}
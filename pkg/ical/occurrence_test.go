@@ -0,0 +1,112 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/worldline-go/types"
+
+	"github.com/worldline-go/calendar/pkg/models"
+)
+
+func TestOccurrences(t *testing.T) {
+	tzIstanbul, _ := time.LoadLocation("Europe/Istanbul")
+
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, tzIstanbul)
+	}
+
+	yearly, err := ParseRepeat("RRULE:FREQ=YEARLY;BYMONTH=4;BYMONTHDAY=23")
+	if err != nil {
+		t.Fatalf("ParseRepeat() error = %v", err)
+	}
+
+	easter, err := ParseRepeat("FUNC:EasterMonday")
+	if err != nil {
+		t.Fatalf("ParseRepeat() error = %v", err)
+	}
+
+	event := models.Event{
+		ID:       "23-nisan",
+		DateFrom: types.Time{Time: day(2020, 4, 23)},
+		DateTo:   types.Time{Time: day(2020, 4, 24)},
+	}
+
+	tests := []struct {
+		name   string
+		event  models.Event
+		repeat *Repeat
+		from   time.Time
+		to     time.Time
+		want   []time.Time
+	}{
+		{
+			name:  "single event",
+			event: event,
+			from:  day(2020, 1, 1),
+			to:    day(2021, 1, 1),
+			want:  []time.Time{day(2020, 4, 23)},
+		},
+		{
+			name:  "single event out of range",
+			event: event,
+			from:  day(2021, 1, 1),
+			to:    day(2022, 1, 1),
+		},
+		{
+			name:   "rrule over years",
+			event:  event,
+			repeat: yearly,
+			from:   day(2023, 1, 1),
+			to:     day(2026, 1, 1),
+			want:   []time.Time{day(2023, 4, 23), day(2024, 4, 23), day(2025, 4, 23)},
+		},
+		{
+			name:   "rrule search inside the occurrence",
+			event:  event,
+			repeat: yearly,
+			from:   time.Date(2025, 4, 23, 0, 0, 0, 0, time.UTC),
+			to:     time.Date(2025, 4, 23, 0, 0, 0, 1, time.UTC),
+			want:   []time.Time{day(2025, 4, 23)},
+		},
+		{
+			name: "rrule with exdate",
+			event: func() models.Event {
+				e := event
+				e.ExDates = []types.Time{{Time: time.Date(2024, 4, 23, 0, 0, 0, 0, time.UTC)}}
+
+				return e
+			}(),
+			repeat: yearly,
+			from:   day(2023, 1, 1),
+			to:     day(2026, 1, 1),
+			want:   []time.Time{day(2023, 4, 23), day(2025, 4, 23)},
+		},
+		{
+			name:   "function",
+			event:  event,
+			repeat: easter,
+			from:   day(2024, 1, 1),
+			to:     day(2026, 1, 1),
+			want:   []time.Time{day(2024, 4, 1), day(2025, 4, 21)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Occurrences(tt.event, tt.repeat, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() got %d occurrences, want %d: %v", len(got), len(tt.want), got)
+			}
+
+			for i := range got {
+				if !got[i].DateFrom.Equal(tt.want[i]) {
+					t.Errorf("Occurrences()[%d] start = %v, want %v", i, got[i].DateFrom, tt.want[i])
+				}
+				if !got[i].DateTo.Equal(tt.want[i].AddDate(0, 0, 1)) {
+					t.Errorf("Occurrences()[%d] end = %v, want %v", i, got[i].DateTo, tt.want[i].AddDate(0, 0, 1))
+				}
+			}
+		})
+	}
+}
@@ -220,9 +220,14 @@ func MatchRRuleBetween(rrule *RRule, dtstart, dtend, dateFrom, dateTo time.Time)
 	}
 
 	occ := start
-	// Fast forward to the approximate first occurrence near dateFrom
-	for occ.Before(dateFrom) {
-		occ = nextFreq(occ, rrule.Freq, rrule.Interval)
+	// Fast forward to the period which can still overlap with dateFrom
+	for {
+		next := nextFreq(occ, rrule.Freq, rrule.Interval)
+		if next.Add(duration).After(dateFrom) {
+			break
+		}
+
+		occ = next
 		count++
 		if maxCount > 0 && count >= maxCount {
 			return time.Time{}, time.Time{}, false
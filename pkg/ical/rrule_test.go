@@ -88,6 +88,8 @@ func TestMatchRRuleBetween(t *testing.T) {
 		t.Fatalf("Failed to load location: %v", err)
 	}
 
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
 	type args struct {
 		rrule    *RRule
 		dtstart  time.Time
@@ -155,6 +157,115 @@ func TestMatchRRuleBetween(t *testing.T) {
 			want1: time.Date(2000, 11, 8, 0, 0, 0, 0, locationNewYork),
 			want2: true,
 		},
+		{
+			name: "weekly with interval",
+			args: args{
+				rrule:    &RRule{Freq: "WEEKLY", Interval: 2},
+				dtstart:  day(2025, 1, 6),
+				dtend:    day(2025, 1, 7),
+				dateFrom: day(2025, 2, 1),
+				dateTo:   day(2025, 3, 1),
+			},
+			want:  day(2025, 2, 3),
+			want1: day(2025, 2, 4),
+			want2: true,
+		},
+		{
+			name: "monthly with interval",
+			args: args{
+				rrule:    &RRule{Freq: "MONTHLY", Interval: 3},
+				dtstart:  day(2025, 1, 15),
+				dtend:    day(2025, 1, 16),
+				dateFrom: day(2025, 3, 1),
+				dateTo:   day(2025, 6, 1),
+			},
+			want:  day(2025, 4, 15),
+			want1: day(2025, 4, 16),
+			want2: true,
+		},
+		{
+			name: "monthly by day with interval",
+			args: args{
+				rrule:    &RRule{Freq: "MONTHLY", Interval: 2, ByDay: []string{"1MO"}},
+				dtstart:  day(2025, 1, 6),
+				dtend:    day(2025, 1, 7),
+				dateFrom: day(2025, 2, 1),
+				dateTo:   day(2025, 5, 1),
+			},
+			want:  day(2025, 3, 3),
+			want1: day(2025, 3, 4),
+			want2: true,
+		},
+		{
+			name: "yearly with interval",
+			args: args{
+				rrule:    &RRule{Freq: "YEARLY", Interval: 2},
+				dtstart:  day(2020, 5, 5),
+				dtend:    day(2020, 5, 6),
+				dateFrom: day(2021, 1, 1),
+				dateTo:   day(2023, 1, 1),
+			},
+			want:  day(2022, 5, 5),
+			want1: day(2022, 5, 6),
+			want2: true,
+		},
+		{
+			name: "yearly with interval skipping the range",
+			args: args{
+				rrule:    &RRule{Freq: "YEARLY", Interval: 2},
+				dtstart:  day(2020, 5, 5),
+				dtend:    day(2020, 5, 6),
+				dateFrom: day(2021, 1, 1),
+				dateTo:   day(2022, 1, 1),
+			},
+		},
+		{
+			name: "range starting before dtstart",
+			args: args{
+				rrule:    &RRule{Freq: "WEEKLY"},
+				dtstart:  day(2025, 6, 9),
+				dtend:    day(2025, 6, 10),
+				dateFrom: day(2025, 1, 1),
+				dateTo:   day(2025, 12, 31),
+			},
+			want:  day(2025, 6, 9),
+			want1: day(2025, 6, 10),
+			want2: true,
+		},
+		{
+			name: "range ending before dtstart",
+			args: args{
+				rrule:    &RRule{Freq: "WEEKLY"},
+				dtstart:  day(2025, 6, 9),
+				dtend:    day(2025, 6, 10),
+				dateFrom: day(2025, 1, 1),
+				dateTo:   day(2025, 6, 1),
+			},
+		},
+		{
+			name: "count reached before the range",
+			args: args{
+				rrule:    &RRule{Freq: "YEARLY", Count: func(v int) *int { return &v }(2)},
+				dtstart:  day(2020, 5, 5),
+				dtend:    day(2020, 5, 6),
+				dateFrom: day(2023, 1, 1),
+				dateTo:   day(2024, 1, 1),
+			},
+		},
+		{
+			// the period before the range is checked for an occurrence ending in the range
+			name: "occurrence overlapping the range start",
+			args: args{
+				rrule:    &RRule{Freq: "YEARLY"},
+				dtstart:  day(2024, 12, 31),
+				dtend:    day(2025, 1, 2),
+				dateFrom: day(2026, 1, 1),
+				dateTo:   day(2026, 2, 1),
+			},
+			want:  day(2025, 12, 31),
+			want1: day(2026, 1, 2),
+			want2: true,
+		},
 	}
 
 	for _, tt := range tests {
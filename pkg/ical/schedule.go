@@ -0,0 +1,188 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a recurring rule of business days like "FREQ=MONTHLY;BYBUSINESSDAY=-1".
+//   - FREQ is WEEKLY, MONTHLY or YEARLY, business days are counted in each period.
+//   - INTERVAL skips periods, it starts from the period of the range start.
+//   - BYMONTH keeps only the periods in the months, with YEARLY every month is a period.
+//   - BYBUSINESSDAY is the list of business day ordinals in the period, negative ones count from the end.
+//   - COUNT limits the number of dates.
+type Schedule struct {
+	Freq          string
+	Interval      int
+	Count         *int
+	ByMonth       []int
+	ByBusinessDay []int
+}
+
+// Period is a part of the schedule with the days in [Start, End).
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseSchedule parses a business day schedule string into a Schedule struct.
+func ParseSchedule(s string) (*Schedule, error) {
+	schedule := &Schedule{Interval: 1}
+	for part := range strings.SplitSeq(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid schedule part: %q", part)
+		}
+		key := strings.ToUpper(kv[0])
+		val := kv[1]
+		switch key {
+		case "FREQ":
+			schedule.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL: %q", val)
+			}
+			schedule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid COUNT: %q", val)
+			}
+			schedule.Count = &count
+		case "BYMONTH":
+			schedule.ByMonth = parseIntList(val)
+			for _, month := range schedule.ByMonth {
+				if month < 1 || month > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH: %d", month)
+				}
+			}
+		case "BYBUSINESSDAY":
+			schedule.ByBusinessDay = parseIntList(val)
+			if slices.Contains(schedule.ByBusinessDay, 0) {
+				return nil, errors.New("invalid BYBUSINESSDAY: 0")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported schedule part: %q", key)
+		}
+	}
+
+	switch schedule.Freq {
+	case "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, errors.New("missing FREQ")
+	default:
+		return nil, fmt.Errorf("unsupported FREQ: %q", schedule.Freq)
+	}
+
+	if len(schedule.ByBusinessDay) == 0 {
+		return nil, errors.New("missing BYBUSINESSDAY")
+	}
+
+	return schedule, nil
+}
+
+// Periods returns the periods of the schedule overlapping with [from, to).
+// Days are UTC midnight of the calendar day, the first period contains from.
+func (s *Schedule) Periods(from, to time.Time) []Period {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+
+	var start time.Time
+	switch s.Freq {
+	case "WEEKLY":
+		// weeks start on monday
+		start = from.AddDate(0, 0, -((int(from.Weekday()) + 6) % 7))
+	case "MONTHLY":
+		start = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case "YEARLY":
+		start = time.Date(from.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return nil
+	}
+
+	var periods []Period
+	for ; start.Before(to); start = s.next(start) {
+		for _, period := range s.expand(start) {
+			if period.End.After(from) && period.Start.Before(to) {
+				periods = append(periods, period)
+			}
+		}
+	}
+
+	return periods
+}
+
+// next returns the start of the next period with the interval.
+func (s *Schedule) next(start time.Time) time.Time {
+	switch s.Freq {
+	case "WEEKLY":
+		return start.AddDate(0, 0, 7*s.Interval)
+	case "MONTHLY":
+		return start.AddDate(0, s.Interval, 0)
+	default:
+		return start.AddDate(s.Interval, 0, 0)
+	}
+}
+
+// expand returns the periods starting at start filtered with BYMONTH.
+func (s *Schedule) expand(start time.Time) []Period {
+	switch s.Freq {
+	case "WEEKLY":
+		if len(s.ByMonth) > 0 && !slices.Contains(s.ByMonth, int(start.Month())) {
+			return nil
+		}
+
+		return []Period{{Start: start, End: start.AddDate(0, 0, 7)}}
+	case "MONTHLY":
+		if len(s.ByMonth) > 0 && !slices.Contains(s.ByMonth, int(start.Month())) {
+			return nil
+		}
+
+		return []Period{{Start: start, End: start.AddDate(0, 1, 0)}}
+	default:
+		if len(s.ByMonth) == 0 {
+			return []Period{{Start: start, End: start.AddDate(1, 0, 0)}}
+		}
+
+		months := slices.Clone(s.ByMonth)
+		slices.Sort(months)
+
+		periods := make([]Period, 0, len(months))
+		for _, month := range slices.Compact(months) {
+			monthStart := time.Date(start.Year(), time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+			periods = append(periods, Period{Start: monthStart, End: monthStart.AddDate(0, 1, 0)})
+		}
+
+		return periods
+	}
+}
+
+// Pick returns the days of the ordinals from the ordered open days of a period.
+func (s *Schedule) Pick(days []time.Time) []time.Time {
+	var picked []time.Time
+	for _, ordinal := range s.ByBusinessDay {
+		i := ordinal - 1
+		if ordinal < 0 {
+			i = len(days) + ordinal
+		}
+
+		if i < 0 || i >= len(days) {
+			continue
+		}
+
+		if !slices.ContainsFunc(picked, days[i].Equal) {
+			picked = append(picked, days[i])
+		}
+	}
+
+	slices.SortFunc(picked, time.Time.Compare)
+
+	return picked
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{name: "last business day", spec: "FREQ=MONTHLY;BYBUSINESSDAY=-1"},
+		{name: "quarterly", spec: "FREQ=MONTHLY;INTERVAL=3;BYBUSINESSDAY=5;COUNT=4"},
+		{name: "yearly months", spec: "FREQ=YEARLY;BYMONTH=3,6,9,12;BYBUSINESSDAY=1,-1"},
+		{name: "missing freq", spec: "BYBUSINESSDAY=1", wantErr: true},
+		{name: "daily", spec: "FREQ=DAILY;BYBUSINESSDAY=1", wantErr: true},
+		{name: "missing ordinal", spec: "FREQ=MONTHLY", wantErr: true},
+		{name: "zero ordinal", spec: "FREQ=MONTHLY;BYBUSINESSDAY=0", wantErr: true},
+		{name: "invalid month", spec: "FREQ=YEARLY;BYMONTH=13;BYBUSINESSDAY=1", wantErr: true},
+		{name: "unsupported part", spec: "FREQ=MONTHLY;BYDAY=MO;BYBUSINESSDAY=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedulePeriods(t *testing.T) {
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		to   time.Time
+		want []time.Time
+	}{
+		{
+			name: "monthly",
+			spec: "FREQ=MONTHLY;BYBUSINESSDAY=1",
+			from: day(2025, 1, 15),
+			to:   day(2025, 4, 1),
+			want: []time.Time{day(2025, 1, 1), day(2025, 2, 1), day(2025, 3, 1)},
+		},
+		{
+			name: "quarterly",
+			spec: "FREQ=MONTHLY;INTERVAL=3;BYBUSINESSDAY=1",
+			from: day(2025, 1, 1),
+			to:   day(2026, 1, 1),
+			want: []time.Time{day(2025, 1, 1), day(2025, 4, 1), day(2025, 7, 1), day(2025, 10, 1)},
+		},
+		{
+			name: "weekly",
+			spec: "FREQ=WEEKLY;BYBUSINESSDAY=1",
+			from: day(2025, 1, 1),
+			to:   day(2025, 1, 15),
+			want: []time.Time{day(2024, 12, 30), day(2025, 1, 6), day(2025, 1, 13)},
+		},
+		{
+			name: "yearly with months",
+			spec: "FREQ=YEARLY;BYMONTH=6,12;BYBUSINESSDAY=1",
+			from: day(2025, 7, 1),
+			to:   day(2026, 7, 1),
+			want: []time.Time{day(2025, 12, 1), day(2026, 6, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+
+			got := schedule.Periods(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Periods() got %d periods, want %d: %v", len(got), len(tt.want), got)
+			}
+
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i]) {
+					t.Errorf("Periods()[%d] start = %v, want %v", i, got[i].Start, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulePick(t *testing.T) {
+	days := []time.Time{
+		time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	schedule, err := ParseSchedule("FREQ=MONTHLY;BYBUSINESSDAY=-1,2,1,5")
+	if err != nil {
+		t.Fatalf("ParseSchedule() error = %v", err)
+	}
+
+	got := schedule.Pick(days)
+	want := []time.Time{days[0], days[1], days[2]}
+	if len(got) != len(want) {
+		t.Fatalf("Pick() = %v, want %v", got, want)
+	}
+
+	for i := range got {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Pick()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
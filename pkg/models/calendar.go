@@ -5,6 +5,81 @@ import (
 )
 
 type (
-	Event    = domain.Event
-	Relation = domain.Relation
+	Event          = domain.Event
+	EventVersion   = domain.EventVersion
+	EventHistory   = domain.EventHistory
+	Relation       = domain.Relation
+	RelationUpdate = domain.RelationUpdate
+	ChangeSet      = domain.ChangeSet
+	Changes        = domain.Changes
+	BatchOperation = domain.BatchOperation
+	BatchResult    = domain.BatchResult
+	WorkDay        = domain.WorkDay
+	Joint          = domain.Joint
+
+	Window        = domain.Window
+	BusinessHours = domain.BusinessHours
+	OpenAt        = domain.OpenAt
+	Weekend       = domain.Weekend
+
+	SettlementRequest = domain.SettlementRequest
+	Settlement        = domain.Settlement
+	Adjustment        = domain.Adjustment
+
+	Span    = domain.Span
+	Bridges = domain.Bridges
+
+	DiffSide = domain.DiffSide
+	Move     = domain.Move
+	Diff     = domain.Diff
+
+	Coverage = domain.Coverage
+
+	Modified = domain.Modified
+)
+
+const (
+	EventTypeHoliday = domain.EventTypeHoliday
+	EventTypeHalfDay = domain.EventTypeHalfDay
+
+	RelationTypeInclude = domain.RelationTypeInclude
+	RelationTypeParent  = domain.RelationTypeParent
+	RelationTypeExclude = domain.RelationTypeExclude
+
+	HistoryActionInsert  = domain.HistoryActionInsert
+	HistoryActionUpdate  = domain.HistoryActionUpdate
+	HistoryActionDelete  = domain.HistoryActionDelete
+	HistoryActionRestore = domain.HistoryActionRestore
+
+	ChangeSetStatusDraft    = domain.ChangeSetStatusDraft
+	ChangeSetStatusApproved = domain.ChangeSetStatusApproved
+	ChangeSetStatusApplied  = domain.ChangeSetStatusApplied
+	ChangeSetStatusFailed   = domain.ChangeSetStatusFailed
+
+	BatchOpAddEvent       = domain.BatchOpAddEvent
+	BatchOpUpdateEvent    = domain.BatchOpUpdateEvent
+	BatchOpRemoveEvent    = domain.BatchOpRemoveEvent
+	BatchOpAddRelation    = domain.BatchOpAddRelation
+	BatchOpUpdateRelation = domain.BatchOpUpdateRelation
+	BatchOpRemoveRelation = domain.BatchOpRemoveRelation
+
+	BatchStatusApplied    = domain.BatchStatusApplied
+	BatchStatusFailed     = domain.BatchStatusFailed
+	BatchStatusRolledBack = domain.BatchStatusRolledBack
+	BatchStatusSkipped    = domain.BatchStatusSkipped
+
+	JointModeUnion        = domain.JointModeUnion
+	JointModeIntersection = domain.JointModeIntersection
+
+	ConventionFollowing         = domain.ConventionFollowing
+	ConventionModifiedFollowing = domain.ConventionModifiedFollowing
+	ConventionPreceding         = domain.ConventionPreceding
+	ConventionModifiedPreceding = domain.ConventionModifiedPreceding
+	ConventionEndOfMonth        = domain.ConventionEndOfMonth
+
+	CoverageStatusOK      = domain.CoverageStatusOK
+	CoverageStatusLow     = domain.CoverageStatusLow
+	CoverageStatusMissing = domain.CoverageStatusMissing
+	DefaultCoverageYears  = domain.DefaultCoverageYears
+	DefaultCoverageRatio  = domain.DefaultCoverageRatio
 )